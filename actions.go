@@ -0,0 +1,186 @@
+package statetrooper
+
+import (
+	"context"
+	"time"
+)
+
+// Action is a side effect run when a state is entered or exited
+// ctx is derived from the context passed to TransitionCtx
+type Action[T comparable] func(ctx context.Context, tr Transition[T]) error
+
+// FailurePolicy determines what happens when an entry action fails after all of its attempts
+type FailurePolicy int
+
+const (
+	// FailureStay keeps the FSM in the entered state
+	FailureStay FailurePolicy = iota
+	// FailureRevert moves the FSM back to the state it was in before the transition
+	FailureRevert
+	// FailureTransition moves the FSM to the policy's ErrorState
+	FailureTransition
+)
+
+// ActionPolicy configures how an entry action is retried and what happens when it keeps failing
+type ActionPolicy[T comparable] struct {
+	// Attempts is the maximum number of times the action is run. Values below 1 mean a single attempt
+	Attempts int
+	// Backoff is the delay before the first retry
+	Backoff time.Duration
+	// Multiplier grows the delay after each retry. Values below 1 keep the delay constant
+	Multiplier float64
+	// OnFailure determines what happens once all attempts have failed
+	OnFailure FailurePolicy
+	// ErrorState is the state moved to when OnFailure is FailureTransition
+	ErrorState T
+}
+
+// stateAction is an action together with its policy
+type stateAction[T comparable] struct {
+	action Action[T]
+	policy ActionPolicy[T]
+}
+
+// SetEntryAction sets the action run after each transition into state, replacing any existing one
+// The action runs after the transition is committed and the FSM is unlocked, once post-commit hooks have run
+// If it still fails after the configured attempts, the transition returns an ActionError and the failure policy
+// is applied. Reverting or moving to the error state bypasses rules, guards and entry actions and is recorded
+// in the history with the action error in the "action_error" metadata key
+// A nil action removes the entry action for state
+func (fsm *FSM[T]) SetEntryAction(state T, action Action[T], policy ActionPolicy[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if action == nil {
+		delete(fsm.entryActions, state)
+		return
+	}
+
+	if fsm.entryActions == nil {
+		fsm.entryActions = make(map[T]stateAction[T])
+	}
+
+	fsm.entryActions[state] = stateAction[T]{action: action, policy: policy}
+}
+
+// runEntryAction runs the entry action of the committed transition's target state and applies its failure policy
+// It returns the resulting state of the FSM and an ActionError if the action failed
+func (fsm *FSM[T]) runEntryAction(ctx context.Context, tr *Transition[T]) (T, error) {
+	fsm.mu.Lock()
+	sa, ok := fsm.entryActions[tr.ToState]
+	fsm.mu.Unlock()
+
+	if !ok {
+		return tr.ToState, nil
+	}
+
+	attempts, err := sa.run(ctx, *tr)
+	if err == nil {
+		return tr.ToState, nil
+	}
+
+	aErr := ActionError[T]{
+		State:    tr.ToState,
+		Action:   "entry",
+		Attempts: attempts,
+		Err:      err,
+	}
+
+	var targetState T
+
+	switch sa.policy.OnFailure {
+	case FailureRevert:
+		targetState = tr.FromState
+	case FailureTransition:
+		targetState = sa.policy.ErrorState
+	default:
+		return tr.ToState, aErr
+	}
+
+	if forced := fsm.force(tr.ToState, targetState, map[string]string{"action_error": aErr.Error()}); forced != nil {
+		fsm.committed(ctx, forced)
+	}
+
+	return fsm.CurrentState(), aErr
+}
+
+// exitAction is an exit action together with its timeout
+type exitAction[T comparable] struct {
+	action  Action[T]
+	timeout time.Duration
+}
+
+// SetExitAction sets the action run after each transition out of state, replacing any existing one
+// The action runs after the transition is committed and the FSM is unlocked, and always completes before
+// the entry action of the new state starts. If it fails or exceeds timeout, the transition returns an ActionError
+// but the FSM stays in the new state. A zero timeout means no timeout. A nil action removes the exit action for state
+func (fsm *FSM[T]) SetExitAction(state T, action Action[T], timeout time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if action == nil {
+		delete(fsm.exitActions, state)
+		return
+	}
+
+	if fsm.exitActions == nil {
+		fsm.exitActions = make(map[T]exitAction[T])
+	}
+
+	fsm.exitActions[state] = exitAction[T]{action: action, timeout: timeout}
+}
+
+// runExitAction runs the exit action of the committed transition's source state
+// The initial pseudo-transition recorded by Start does not exit any state
+func (fsm *FSM[T]) runExitAction(ctx context.Context, tr *Transition[T]) error {
+	if tr.Initial {
+		return nil
+	}
+
+	fsm.mu.Lock()
+	ea, ok := fsm.exitActions[tr.FromState]
+	fsm.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err := callWithTimeout(ctx, ea.timeout, *tr, ea.action); err != nil {
+		return ActionError[T]{
+			State:    tr.FromState,
+			Action:   "exit",
+			Attempts: 1,
+			Err:      err,
+		}
+	}
+
+	return nil
+}
+
+// run runs the action until it succeeds, its attempts are used up or ctx is done
+// It returns the number of attempts made and the last error
+func (sa stateAction[T]) run(ctx context.Context, tr Transition[T]) (int, error) {
+	backoff := sa.policy.Backoff
+
+	attempts := 0
+	for {
+		attempts++
+
+		err := sa.action(ctx, tr)
+		if err == nil || attempts >= sa.policy.Attempts {
+			return attempts, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempts, err
+		}
+
+		if sa.policy.Multiplier > 1 {
+			backoff = time.Duration(float64(backoff) * sa.policy.Multiplier)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_entryActionRetry(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	calls := 0
+	fsm.SetEntryAction(CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		calls++
+		if calls < 3 {
+			return errors.New("inventory service unavailable")
+		}
+		return nil
+	}, ActionPolicy[CustomStateEnum]{Attempts: 3, Backoff: time.Millisecond, Multiplier: 2})
+
+	newState, err := fsm.Transition(CustomStateEnumB, nil)
+	if err != nil || newState != CustomStateEnumB {
+		t.Errorf("Transition returned (%v, %v), expected (%v, nil)", newState, err, CustomStateEnumB)
+	}
+
+	if calls != 3 {
+		t.Errorf("Entry action ran %d times, expected 3", calls)
+	}
+}
+
+func Test_entryActionFailurePolicies(t *testing.T) {
+	errReserve := errors.New("reservation failed")
+	failing := func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errReserve
+	}
+
+	tests := []struct {
+		policy   FailurePolicy
+		expected CustomStateEnum
+		history  int
+	}{
+		{FailureStay, CustomStateEnumB, 1},
+		{FailureRevert, CustomStateEnumA, 2},
+		{FailureTransition, CustomStateEnumD, 2},
+	}
+
+	for _, test := range tests {
+		fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+		fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+		fsm.SetEntryAction(CustomStateEnumB, failing, ActionPolicy[CustomStateEnum]{
+			Attempts:   2,
+			OnFailure:  test.policy,
+			ErrorState: CustomStateEnumD,
+		})
+
+		newState, err := fsm.Transition(CustomStateEnumB, nil)
+
+		var aErr ActionError[CustomStateEnum]
+		if !errors.As(err, &aErr) || !errors.Is(err, errReserve) || aErr.Attempts != 2 {
+			t.Errorf("Policy %d: Transition returned %v, expected an ActionError after 2 attempts", test.policy, err)
+		}
+
+		if newState != test.expected || fsm.CurrentState() != test.expected {
+			t.Errorf("Policy %d: FSM is in state %v, expected %v", test.policy, fsm.CurrentState(), test.expected)
+		}
+
+		transitions := fsm.Transitions()
+		if len(transitions) != test.history {
+			t.Errorf("Policy %d: history has %d entries, expected %d", test.policy, len(transitions), test.history)
+		}
+
+		if test.history > 1 && transitions[1].Metadata["action_error"] == "" {
+			t.Errorf("Policy %d: compensating transition does not record the action error", test.policy)
+		}
+	}
+}
+
+func Test_exitActions(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	var order []string
+	fsm.SetExitAction(CustomStateEnumA, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		order = append(order, "exit A")
+		return nil
+	}, time.Second)
+	fsm.SetEntryAction(CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		order = append(order, "enter B")
+		return nil
+	}, ActionPolicy[CustomStateEnum]{})
+
+	fsm.Transition(CustomStateEnumB, nil)
+
+	if len(order) != 2 || order[0] != "exit A" || order[1] != "enter B" {
+		t.Errorf("Actions ran in order %v, expected [exit A enter B]", order)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+
+	fsm.SetExitAction(CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		<-release
+		return nil
+	}, 10*time.Millisecond)
+
+	newState, err := fsm.Transition(CustomStateEnumC, nil)
+
+	var aErr ActionError[CustomStateEnum]
+	if !errors.As(err, &aErr) || aErr.Action != "exit" || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Transition with a slow exit action returned %v, expected an exit ActionError wrapping context.DeadlineExceeded", err)
+	}
+
+	if newState != CustomStateEnumC {
+		t.Errorf("Failed exit action should not change the new state. Got %v, expected %v", newState, CustomStateEnumC)
+	}
+}
@@ -0,0 +1,27 @@
+package statetrooper
+
+import "context"
+
+// actorKey is the context key under which the actor of a transition is stored
+type actorKey struct{}
+
+// Actor identifies who requested a transition
+type Actor struct {
+	// ID identifies the actor, for example a user name or service account, and is recorded on the transition
+	ID string
+	// Roles are the roles held by the actor
+	Roles []string
+	// Claims carries further attributes of the actor, such as the claims of a validated token, for an Authorizer
+	Claims map[string]any
+}
+
+// WithActor returns a copy of ctx that makes transitions attempted with it on behalf of actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx by WithActor
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
@@ -0,0 +1,28 @@
+package statetrooper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_actorFromContext(t *testing.T) {
+	if _, ok := ActorFromContext(context.Background()); ok {
+		t.Errorf("ActorFromContext() found an actor in an empty context")
+	}
+
+	actor := Actor{ID: "alice", Roles: []string{"warehouse"}}
+	got, ok := ActorFromContext(WithActor(context.Background(), actor))
+	if !ok || !reflect.DeepEqual(got, actor) {
+		t.Errorf("ActorFromContext() returned %+v, %v, expected %+v", got, ok, actor)
+	}
+
+	// Rejected attempts record the actor too
+	fsm := newPingPongFSM()
+	fsm.SetRecordFailures(true)
+	fsm.TransitionCtx(WithActor(context.Background(), actor), CustomStateEnumC, nil)
+
+	if trs := fsm.Transitions(); len(trs) != 1 || !trs[0].Failed || trs[0].Actor != "alice" {
+		t.Errorf("History is %+v, expected a failed attempt by alice", trs)
+	}
+}
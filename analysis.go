@@ -0,0 +1,108 @@
+package statetrooper
+
+import "sort"
+
+// EquivalentRulesets reports whether rulesets a and b allow the same sequences of transitions
+// starting from each of the given states
+// Since a transition always moves the FSM to its target, two rulesets are equivalent exactly when
+// every reachable state allows the same targets in both
+// If they differ, the distinguishing sequence is returned: a start state followed by targets that
+// are allowed by both rulesets, except for the last, which is allowed by only one of them
+// Rules inherited from composite states are not considered
+func EquivalentRulesets[T comparable](a, b map[T][]T, states []T) (bool, []T) {
+	for _, start := range states {
+		// paths holds the shortest sequence reaching each visited state
+		paths := map[T][]T{start: {start}}
+		queue := []T{start}
+
+		for len(queue) > 0 {
+			state := queue[0]
+			queue = queue[1:]
+
+			if target, ok := distinguishingTarget(a[state], b[state]); ok {
+				return false, appendPath(paths[state], target)
+			}
+
+			for _, target := range a[state] {
+				if _, seen := paths[target]; !seen {
+					paths[target] = appendPath(paths[state], target)
+					queue = append(queue, target)
+				}
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// distinguishingTarget returns a target allowed by exactly one of the two target lists
+func distinguishingTarget[T comparable](a, b []T) (T, bool) {
+	for _, target := range a {
+		if !contains(b, target) {
+			return target, true
+		}
+	}
+
+	for _, target := range b {
+		if !contains(a, target) {
+			return target, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// appendPath returns a copy of path with state appended
+func appendPath[T comparable](path []T, state T) []T {
+	p := make([]T, len(path), len(path)+1)
+	copy(p, path)
+
+	return append(p, state)
+}
+
+// EquivalentStates groups the states of ruleset that allow exactly the same targets
+// Each group is a candidate for merging into a single state, since the FSM behaves the same in any of them
+// States without rules are not reported, as they trivially match every other terminal state
+// Groups, and the states within them, are ordered by their string form
+func EquivalentStates[T comparable](ruleset map[T][]T) [][]T {
+	states := make([]T, 0, len(ruleset))
+	for state, targets := range ruleset {
+		if len(targets) > 0 {
+			states = append(states, state)
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return toString(states[i]) < toString(states[j])
+	})
+
+	var groups [][]T
+	grouped := make(map[T]bool)
+
+	for i, state := range states {
+		if grouped[state] {
+			continue
+		}
+
+		group := []T{state}
+		for _, other := range states[i+1:] {
+			if !grouped[other] && sameTargets(ruleset[state], ruleset[other]) {
+				group = append(group, other)
+				grouped[other] = true
+			}
+		}
+
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// sameTargets reports whether a and b contain the same targets, ignoring order and duplicates
+func sameTargets[T comparable](a, b []T) bool {
+	_, differ := distinguishingTarget(a, b)
+	return !differ
+}
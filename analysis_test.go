@@ -0,0 +1,70 @@
+package statetrooper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_equivalentRulesets(t *testing.T) {
+	a := map[string][]string{
+		"created":  {"picked", "canceled"},
+		"picked":   {"packed", "canceled"},
+		"packed":   {"shipped"},
+		"canceled": {},
+		"orphan":   {"created"},
+	}
+
+	// Same reachable behavior with a different target order and without the unreachable state
+	b := map[string][]string{
+		"created": {"canceled", "picked"},
+		"picked":  {"canceled", "packed"},
+		"packed":  {"shipped"},
+	}
+
+	if equivalent, seq := EquivalentRulesets(a, b, []string{"created"}); !equivalent {
+		t.Errorf("EquivalentRulesets() returned false with sequence %v, expected true", seq)
+	}
+
+	// The unreachable state distinguishes them when used as a start state
+	if equivalent, seq := EquivalentRulesets(a, b, []string{"orphan"}); equivalent || !reflect.DeepEqual(seq, []string{"orphan", "created"}) {
+		t.Errorf("EquivalentRulesets() returned %v, %v, expected false, [orphan created]", equivalent, seq)
+	}
+
+	b["packed"] = []string{"shipped", "canceled"}
+
+	equivalent, seq := EquivalentRulesets(a, b, []string{"created"})
+	if equivalent {
+		t.Fatalf("EquivalentRulesets() returned true, expected false")
+	}
+
+	expected := []string{"created", "picked", "packed", "canceled"}
+	if !reflect.DeepEqual(seq, expected) {
+		t.Errorf("EquivalentRulesets() returned sequence %v, expected %v", seq, expected)
+	}
+}
+
+func Test_equivalentStates(t *testing.T) {
+	ruleset := map[string][]string{
+		"created":      {"picked", "canceled"},
+		"reinstated":   {"canceled", "picked"},
+		"picked":       {"packed", "canceled"},
+		"packed":       {"shipped"},
+		"relabeled":    {"shipped"},
+		"awaiting_box": {"shipped", "shipped"},
+		"shipped":      {},
+		"canceled":     {},
+	}
+
+	expected := [][]string{
+		{"awaiting_box", "packed", "relabeled"},
+		{"created", "reinstated"},
+	}
+
+	if groups := EquivalentStates(ruleset); !reflect.DeepEqual(groups, expected) {
+		t.Errorf("EquivalentStates() returned %v, expected %v", groups, expected)
+	}
+
+	if groups := EquivalentStates(map[string][]string{"a": {"b"}, "b": {"a"}}); groups != nil {
+		t.Errorf("EquivalentStates() returned %v, expected no groups", groups)
+	}
+}
@@ -0,0 +1,119 @@
+package statetrooper
+
+import (
+	"fmt"
+	"time"
+)
+
+// Amendment records a change made to the metadata of a recorded transition
+type Amendment struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Metadata holds the keys that were set and their new values
+	Metadata map[string]string `json:"metadata"`
+	// Previous holds the values that were replaced. Keys that were added are not included
+	Previous map[string]string `json:"previous,omitempty"`
+}
+
+// AnnotateLastTransition adds a metadata key to the most recent transition, such as a tracking number
+// that only becomes known after the transition. The annotation is recorded as a timestamped amendment
+// ErrHistoryUnavailable is returned if there is no history and ErrMetadataExists if key is already set
+func (fsm *FSM[T]) AnnotateLastTransition(key string, value string) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if len(fsm.transitions) == 0 {
+		return fmt.Errorf("%w: no transitions recorded", ErrHistoryUnavailable)
+	}
+
+	return fsm.annotate(len(fsm.transitions)-1, key, value)
+}
+
+// AnnotateTransition is like AnnotateLastTransition for the transition with the given ID
+// ErrTransitionNotFound is returned if it is no longer retained in the history
+func (fsm *FSM[T]) AnnotateTransition(id uint64, key string, value string) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	i, err := fsm.transitionIndex(id)
+	if err != nil {
+		return err
+	}
+
+	return fsm.annotate(i, key, value)
+}
+
+// AmendTransition corrects the metadata of the transition with the given ID by setting the keys of patch,
+// for example to fix a data-entry mistake. The original values are never lost: the amendment records
+// both the replaced and the new values with a timestamp. An empty patch does nothing
+// ErrTransitionNotFound is returned if the transition is no longer retained in the history
+func (fsm *FSM[T]) AmendTransition(id uint64, patch map[string]string) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	i, err := fsm.transitionIndex(id)
+	if err != nil {
+		return err
+	}
+
+	if len(patch) > 0 {
+		fsm.amend(&fsm.transitions[i], cloneMap(patch))
+	}
+
+	return nil
+}
+
+// annotate adds a metadata key to the i-th transition. The caller must hold the lock
+func (fsm *FSM[T]) annotate(i int, key string, value string) error {
+	tr := &fsm.transitions[i]
+	if _, ok := tr.Metadata[key]; ok {
+		return fmt.Errorf("%w: %q on transition %d", ErrMetadataExists, key, tr.ID)
+	}
+
+	fsm.amend(tr, map[string]string{key: value})
+
+	return nil
+}
+
+// amend applies patch to the metadata of tr and records it as an amendment. The caller must hold the lock
+// Metadata maps and amendment slices may be shared with callers, so they are replaced rather than modified
+func (fsm *FSM[T]) amend(tr *Transition[T], patch map[string]string) {
+	metadata := cloneMap(tr.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string, len(patch))
+	}
+
+	var previous map[string]string
+	for k, v := range patch {
+		if old, ok := metadata[k]; ok {
+			if previous == nil {
+				previous = make(map[string]string)
+			}
+			previous[k] = old
+		}
+		metadata[k] = v
+	}
+
+	tr.Metadata = metadata
+	tr.Amendments = append(append([]Amendment(nil), tr.Amendments...), Amendment{
+		Timestamp: fsm.timeNow(),
+		Metadata:  patch,
+		Previous:  previous,
+	})
+}
+
+// transitionIndex returns the position of the transition with the given ID in the history
+// The caller must hold the lock
+func (fsm *FSM[T]) transitionIndex(id uint64) (int, error) {
+	// IDs increase along the history, so they can be searched for from the end, where recent ones are
+	for i := len(fsm.transitions) - 1; i >= 0; i-- {
+		if fsm.transitions[i].ID == id {
+			return i, nil
+		}
+
+		if fsm.transitions[i].ID < id {
+			break
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %d", ErrTransitionNotFound, id)
+}
@@ -0,0 +1,95 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_annotateTransition(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	if err := fsm.AnnotateLastTransition("tracking", "1Z999"); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Errorf("AnnotateLastTransition() returned %v without history, expected ErrHistoryUnavailable", err)
+	}
+
+	shippedAt := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	fsm.SetClock(func() time.Time { return shippedAt })
+
+	metadata := map[string]string{"carrier": "ups"}
+	fsm.Transition(CustomStateEnumB, metadata)
+	fsm.Transition(CustomStateEnumA, nil)
+
+	first := fsm.Transitions()[0]
+	if first.ID != 1 || fsm.Transitions()[1].ID != 2 {
+		t.Fatalf("Transitions have IDs %d and %d, expected 1 and 2", first.ID, fsm.Transitions()[1].ID)
+	}
+
+	annotatedAt := shippedAt.Add(5 * time.Second)
+	fsm.SetClock(func() time.Time { return annotatedAt })
+
+	if err := fsm.AnnotateTransition(first.ID, "tracking", "1Z999"); err != nil {
+		t.Fatalf("AnnotateTransition() returned an error: %v", err)
+	}
+
+	annotated := fsm.Transitions()[0]
+	if annotated.Metadata["tracking"] != "1Z999" || annotated.Metadata["carrier"] != "ups" {
+		t.Errorf("Annotated metadata is %v", annotated.Metadata)
+	}
+
+	if len(annotated.Amendments) != 1 || !annotated.Amendments[0].Timestamp.Equal(annotatedAt) ||
+		annotated.Amendments[0].Metadata["tracking"] != "1Z999" {
+		t.Errorf("Amendments are %v, expected the timestamped annotation", annotated.Amendments)
+	}
+
+	if !annotated.Timestamp.Equal(shippedAt) {
+		t.Errorf("Annotation changed the transition timestamp to %v", annotated.Timestamp)
+	}
+
+	if len(metadata) != 1 || len(first.Metadata) != 1 {
+		t.Errorf("Annotation modified metadata shared with callers")
+	}
+
+	if err := fsm.AnnotateTransition(first.ID, "carrier", "fedex"); !errors.Is(err, ErrMetadataExists) {
+		t.Errorf("AnnotateTransition() returned %v for an existing key, expected ErrMetadataExists", err)
+	}
+
+	if err := fsm.AnnotateTransition(42, "tracking", "1Z999"); !errors.Is(err, ErrTransitionNotFound) {
+		t.Errorf("AnnotateTransition() returned %v for an unknown ID, expected ErrTransitionNotFound", err)
+	}
+
+	if err := fsm.AnnotateLastTransition("note", "left at door"); err != nil || fsm.Transitions()[1].Metadata["note"] != "left at door" {
+		t.Errorf("AnnotateLastTransition() returned %v with history %v", err, fsm.Transitions())
+	}
+}
+
+func Test_amendTransition(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.Transition(CustomStateEnumB, map[string]string{"operator": "jnae", "desk": "3"})
+
+	id := fsm.Transitions()[0].ID
+	if err := fsm.AmendTransition(id, map[string]string{"operator": "jane", "reason": "typo"}); err != nil {
+		t.Fatalf("AmendTransition() returned an error: %v", err)
+	}
+
+	tr := fsm.Transitions()[0]
+	expected := map[string]string{"operator": "jane", "desk": "3", "reason": "typo"}
+	if !reflect.DeepEqual(tr.Metadata, expected) {
+		t.Errorf("Amended metadata is %v, expected %v", tr.Metadata, expected)
+	}
+
+	if len(tr.Amendments) != 1 || !reflect.DeepEqual(tr.Amendments[0].Previous, map[string]string{"operator": "jnae"}) {
+		t.Errorf("Amendments are %v, expected the original operator to be kept", tr.Amendments)
+	}
+
+	// A second correction keeps the first one
+	fsm.AmendTransition(id, map[string]string{"desk": "4"})
+	if tr := fsm.Transitions()[0]; len(tr.Amendments) != 2 || tr.Amendments[1].Previous["desk"] != "3" {
+		t.Errorf("Amendments are %v after a second correction", tr.Amendments)
+	}
+
+	if err := fsm.AmendTransition(99, map[string]string{"desk": "5"}); !errors.Is(err, ErrTransitionNotFound) {
+		t.Errorf("AmendTransition() returned %v for an unknown ID, expected ErrTransitionNotFound", err)
+	}
+}
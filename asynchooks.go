@@ -0,0 +1,167 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// asyncHooks runs post-commit hooks on a bounded worker pool
+type asyncHooks[T comparable] struct {
+	jobs    chan hookJob[T]
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+
+	mu     sync.Mutex
+	errs   []error
+	closed bool
+}
+
+// hookJob is the set of post-commit hooks to run for a single transition
+type hookJob[T comparable] struct {
+	ctx     context.Context
+	timeout time.Duration
+	hooks   []*registeredHook[T]
+	tr      Transition[T]
+}
+
+// SetAsyncHooks switches post-commit hooks to async mode, running them on a pool of workers
+// with room for queueSize pending transitions. Hooks for one transition still run in priority order
+// If the queue is full, the hooks for that transition are skipped and ErrHookQueueFull is reported by FlushHooks
+// Calling SetAsyncHooks again replaces the pool after flushing the previous one
+func (fsm *FSM[T]) SetAsyncHooks(workers int, queueSize int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	a := &asyncHooks[T]{jobs: make(chan hookJob[T], queueSize)}
+	for i := 0; i < workers; i++ {
+		a.workers.Add(1)
+		go a.work()
+	}
+
+	fsm.mu.Lock()
+	previous := fsm.asyncHooks
+	fsm.asyncHooks = a
+	fsm.mu.Unlock()
+
+	if previous != nil {
+		a.addErr(previous.stop())
+	}
+}
+
+// FlushHooks waits for all queued asynchronous hooks to finish and returns the errors
+// they reported since the last flush, joined into a single error
+func (fsm *FSM[T]) FlushHooks() error {
+	fsm.mu.Lock()
+	a := fsm.asyncHooks
+	fsm.mu.Unlock()
+
+	if a == nil {
+		return nil
+	}
+
+	a.pending.Wait()
+
+	return a.takeErrs()
+}
+
+// StopAsyncHooks flushes the asynchronous hooks, stops the worker pool and switches
+// post-commit hooks back to running inline. It returns the errors reported since the last flush
+func (fsm *FSM[T]) StopAsyncHooks() error {
+	fsm.mu.Lock()
+	a := fsm.asyncHooks
+	fsm.asyncHooks = nil
+	fsm.mu.Unlock()
+
+	if a == nil {
+		return nil
+	}
+
+	return a.stop()
+}
+
+// submit queues the hooks for a transition without blocking
+// It returns false if the pool has been stopped and the hooks were not handled
+func (a *asyncHooks[T]) submit(ctx context.Context, timeout time.Duration, hooks []*registeredHook[T], tr Transition[T]) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return false
+	}
+
+	a.pending.Add(1)
+
+	select {
+	case a.jobs <- hookJob[T]{ctx: ctx, timeout: timeout, hooks: hooks, tr: tr}:
+	default:
+		a.pending.Done()
+		a.errs = append(a.errs, fmt.Errorf("%w: transition from %v to %v", ErrHookQueueFull, tr.FromState, tr.ToState))
+	}
+
+	return true
+}
+
+// work runs queued hook jobs until the job channel is closed
+func (a *asyncHooks[T]) work() {
+	defer a.workers.Done()
+
+	for job := range a.jobs {
+		for _, h := range job.hooks {
+			a.addErr(a.run(job.ctx, job.timeout, h.hook, job.tr))
+		}
+		a.pending.Done()
+	}
+}
+
+// run runs a single hook, converting a panic into an error
+func (a *asyncHooks[T]) run(ctx context.Context, timeout time.Duration, hook Hook[T], tr Transition[T]) error {
+	return callWithTimeout(ctx, timeout, tr, func(ctx context.Context, tr Transition[T]) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%w: %v", ErrHookPanic, r)
+			}
+		}()
+
+		return hook(ctx, tr)
+	})
+}
+
+// stop waits for queued jobs, stops the workers and returns the outstanding errors
+func (a *asyncHooks[T]) stop() error {
+	a.mu.Lock()
+	a.closed = true
+	a.mu.Unlock()
+
+	a.pending.Wait()
+	close(a.jobs)
+	a.workers.Wait()
+
+	return a.takeErrs()
+}
+
+// addErr records an error reported by a hook
+func (a *asyncHooks[T]) addErr(err error) {
+	if err == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.errs = append(a.errs, err)
+}
+
+// takeErrs returns the recorded errors joined together and clears them
+func (a *asyncHooks[T]) takeErrs() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	err := errors.Join(a.errs...)
+	a.errs = nil
+
+	return err
+}
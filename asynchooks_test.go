@@ -0,0 +1,97 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func Test_asyncHooks(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.SetAsyncHooks(2, 100)
+
+	errNotify := errors.New("notification failed")
+
+	var mu sync.Mutex
+	calls := 0
+
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return nil
+	})
+	removeFailing := fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		if tr.ToState == CustomStateEnumB {
+			panic("boom")
+		}
+		return errNotify
+	})
+
+	fsm.Transition(CustomStateEnumB, nil)
+	fsm.Transition(CustomStateEnumA, nil)
+
+	err := fsm.FlushHooks()
+	if !errors.Is(err, ErrHookPanic) || !errors.Is(err, errNotify) {
+		t.Errorf("FlushHooks() returned %v, expected errors wrapping ErrHookPanic and %v", err, errNotify)
+	}
+
+	mu.Lock()
+	if calls != 2 {
+		t.Errorf("Async hook ran %d times, expected 2", calls)
+	}
+	mu.Unlock()
+
+	if err := fsm.FlushHooks(); err != nil {
+		t.Errorf("FlushHooks() returned %v after errors were already reported", err)
+	}
+
+	if err := fsm.StopAsyncHooks(); err != nil {
+		t.Errorf("StopAsyncHooks() returned an error: %v", err)
+	}
+
+	// Hooks run inline once async mode is stopped
+	removeFailing()
+	fsm.Transition(CustomStateEnumB, nil)
+
+	mu.Lock()
+	if calls != 3 {
+		t.Errorf("Hook ran %d times after stopping async mode, expected 3", calls)
+	}
+	mu.Unlock()
+}
+
+func Test_asyncHooksQueueFull(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.SetAsyncHooks(1, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	})
+
+	fsm.Transition(CustomStateEnumB, nil)
+	<-started
+
+	// The only worker is busy and the queue fills up, so the last transition's hooks are skipped
+	fsm.Transition(CustomStateEnumA, nil)
+	fsm.Transition(CustomStateEnumB, nil)
+
+	close(release)
+
+	if err := fsm.StopAsyncHooks(); !errors.Is(err, ErrHookQueueFull) {
+		t.Errorf("StopAsyncHooks() returned %v, expected ErrHookQueueFull", err)
+	}
+}
@@ -0,0 +1,123 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AutoMetadataKey is the metadata key set on transitions taken automatically
+const AutoMetadataKey = "automatic"
+
+// autoTransition is an eventless transition taken as soon as its from state is entered and its condition holds
+type autoTransition[T comparable] struct {
+	toState   T
+	condition func(tr Transition[T]) bool
+}
+
+// SetAutoTransition makes the rule from fromState to toState an eventless transition, like a statechart's
+// "always" transition for a pass-through state: when fromState is entered and condition holds for the
+// transition that entered it, the FSM immediately moves on to toState within the same critical section
+// The automatic transition goes through the same checks as any other, and is not taken if they reject it
+// Automatic transitions from the same state are tried in the order they were set. Chaining into a state
+// already entered in the same chain stops with ErrAutoTransitionLoop. condition is called while the FSM is
+// locked and must not call back into the FSM. A nil condition removes the automatic transition
+func (fsm *FSM[T]) SetAutoTransition(fromState T, toState T, condition func(tr Transition[T]) bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	autos := make([]autoTransition[T], 0, len(fsm.autoTransitions[fromState])+1)
+	for _, a := range fsm.autoTransitions[fromState] {
+		if a.toState != toState {
+			autos = append(autos, a)
+		}
+	}
+
+	if condition != nil {
+		autos = append(autos, autoTransition[T]{toState: toState, condition: condition})
+	}
+
+	if len(autos) == 0 {
+		delete(fsm.autoTransitions, fromState)
+		return
+	}
+
+	if fsm.autoTransitions == nil {
+		fsm.autoTransitions = make(map[T][]autoTransition[T])
+	}
+
+	fsm.autoTransitions[fromState] = autos
+}
+
+// commitChain commits tr followed by any automatic transitions it leads to and returns them in order
+// A touch or duplicate entry is only recorded and returns no transitions. The caller must hold the lock
+func (fsm *FSM[T]) commitChain(ctx context.Context, tr *Transition[T]) ([]*Transition[T], error) {
+	if tr.noOp() {
+		fsm.commitNoOp(tr)
+		return nil, nil
+	}
+
+	fsm.commit(tr)
+
+	chain := []*Transition[T]{tr}
+	if len(fsm.autoTransitions) == 0 {
+		return chain, nil
+	}
+
+	entered := map[T]bool{tr.ToState: true}
+	for {
+		last := chain[len(chain)-1]
+
+		next, ok := fsm.nextAuto(last)
+		if !ok {
+			return chain, nil
+		}
+
+		tr, err := fsm.prepare(ctx, next, fsm.tag(ctx, map[string]string{AutoMetadataKey: "true"}))
+		if err != nil || tr == nil {
+			return chain, nil
+		}
+
+		if tr.noOp() {
+			fsm.commitNoOp(tr)
+			return chain, nil
+		}
+
+		if entered[tr.ToState] {
+			return chain, fmt.Errorf("%w: %v -> %v", ErrAutoTransitionLoop, last.ToState, tr.ToState)
+		}
+
+		fsm.commit(tr)
+		entered[tr.ToState] = true
+		chain = append(chain, tr)
+	}
+}
+
+// nextAuto returns the target of the first automatic transition out of the state tr entered whose condition holds
+func (fsm *FSM[T]) nextAuto(tr *Transition[T]) (T, bool) {
+	for _, a := range fsm.autoTransitions[tr.ToState] {
+		if a.condition(*tr) {
+			return a.toState, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// afterCommitChain runs afterCommit for each transition of a committed chain in order
+// It returns the final state and the errors of all of them
+func (fsm *FSM[T]) afterCommitChain(ctx context.Context, chain []*Transition[T]) (T, error) {
+	var state T
+	var errs []error
+
+	for _, tr := range chain {
+		var err error
+		state, err = fsm.afterCommit(ctx, tr)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return state, errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_autoTransition(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC, CustomStateEnumD)
+
+	// B passes through to C for small orders and to D otherwise
+	fsm.SetAutoTransition(CustomStateEnumB, CustomStateEnumC, func(tr Transition[CustomStateEnum]) bool {
+		return tr.Metadata["size"] == "small"
+	})
+	fsm.SetAutoTransition(CustomStateEnumB, CustomStateEnumD, func(tr Transition[CustomStateEnum]) bool {
+		return true
+	})
+
+	sub, _ := fsm.Subscribe(SubscriptionOptions{Buffer: 5})
+
+	state, err := fsm.Transition(CustomStateEnumB, map[string]string{"size": "small"})
+	if err != nil || state != CustomStateEnumC {
+		t.Fatalf("Transition() returned %v, %v, expected C", state, err)
+	}
+
+	trs := fsm.Transitions()
+	if len(trs) != 2 || trs[1].FromState != CustomStateEnumB || trs[1].Metadata[AutoMetadataKey] != "true" {
+		t.Errorf("History is %v, expected the automatic transition from B to C", trs)
+	}
+
+	// Subscribers see both transitions in order
+	if states := received(sub); !reflect.DeepEqual(states, []CustomStateEnum{CustomStateEnumB, CustomStateEnumC}) {
+		t.Errorf("Subscription received %v, expected [B C]", states)
+	}
+
+	// Removing an automatic transition falls through to the next one
+	fsm = NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC, CustomStateEnumD)
+	fsm.SetAutoTransition(CustomStateEnumB, CustomStateEnumC, func(tr Transition[CustomStateEnum]) bool { return true })
+	fsm.SetAutoTransition(CustomStateEnumB, CustomStateEnumD, func(tr Transition[CustomStateEnum]) bool { return true })
+	fsm.SetAutoTransition(CustomStateEnumB, CustomStateEnumC, nil)
+
+	if state, err := fsm.Transition(CustomStateEnumB, nil); err != nil || state != CustomStateEnumD {
+		t.Errorf("Transition() returned %v, %v, expected D", state, err)
+	}
+}
+
+func Test_autoTransitionLoop(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+	fsm.AddRule(CustomStateEnumC, CustomStateEnumB)
+
+	always := func(tr Transition[CustomStateEnum]) bool { return true }
+	fsm.SetAutoTransition(CustomStateEnumB, CustomStateEnumC, always)
+	fsm.SetAutoTransition(CustomStateEnumC, CustomStateEnumB, always)
+
+	// The chain stops before re-entering B, leaving the committed transitions in place
+	state, err := fsm.Transition(CustomStateEnumB, nil)
+	if !errors.Is(err, ErrAutoTransitionLoop) || state != CustomStateEnumC {
+		t.Errorf("Transition() returned %v, %v, expected C with ErrAutoTransitionLoop", state, err)
+	}
+
+	if n := len(fsm.Transitions()); n != 2 {
+		t.Errorf("History has %d transitions, expected 2", n)
+	}
+
+	fsm.SetAutoTransition(CustomStateEnumA, CustomStateEnumD, always)
+	if err := fsm.Validate(); err == nil || !strings.Contains(err.Error(), "automatic transition on A -> D") {
+		t.Errorf("Validate() returned %v, expected it to report the automatic transition without a rule", err)
+	}
+}
@@ -0,0 +1,178 @@
+package statetrooper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DurationStats aggregates the durations observed for a state or edge
+type DurationStats struct {
+	Count int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Mean returns the average observed duration
+func (s DurationStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return s.Total / time.Duration(s.Count)
+}
+
+func (s *DurationStats) add(d time.Duration) {
+	s.Count++
+	s.Total += d
+	if d > s.Max {
+		s.Max = d
+	}
+}
+
+// StateStats is the time spent in a state before leaving it
+type StateStats[T comparable] struct {
+	State T
+	DurationStats
+}
+
+// EdgeStats is the time spent in the source state of an edge before taking it
+type EdgeStats[T comparable] struct {
+	FromState T
+	ToState   T
+	DurationStats
+}
+
+// BottleneckReport summarizes where time is spent across one or many histories
+// States and Edges are ordered by total time spent, most time-consuming first
+type BottleneckReport[T comparable] struct {
+	States []StateStats[T]
+	Edges  []EdgeStats[T]
+
+	// CriticalPath is the observed sequence of states to a terminal state with the longest mean duration
+	CriticalPath         []T
+	CriticalPathDuration time.Duration
+}
+
+// AnalyzeBottlenecks computes the time spent in each state and before each edge across histories,
+// and the critical path among the histories that end in one of the terminal states
+// If no terminal states are given, every history counts towards the critical path
+// Transitions without a timestamp and failed attempts are ignored
+func AnalyzeBottlenecks[T comparable](histories [][]Transition[T], terminals ...T) BottleneckReport[T] {
+	states := make(map[T]*DurationStats)
+	edges := make(map[edge[T]]*DurationStats)
+	paths := make(map[string]*pathStats[T])
+
+	for _, history := range histories {
+		var timed []Transition[T]
+		for _, tr := range history {
+			if tr.Timestamp != nil && !tr.Failed {
+				timed = append(timed, tr)
+			}
+		}
+
+		for i := 1; i < len(timed); i++ {
+			d := timed[i].Timestamp.Sub(*timed[i-1].Timestamp)
+
+			state := timed[i].FromState
+			if states[state] == nil {
+				states[state] = &DurationStats{}
+			}
+			states[state].add(d)
+
+			e := edge[T]{from: timed[i].FromState, to: timed[i].ToState}
+			if edges[e] == nil {
+				edges[e] = &DurationStats{}
+			}
+			edges[e].add(d)
+		}
+
+		if len(timed) < 2 || (len(terminals) > 0 && !contains(terminals, timed[len(timed)-1].ToState)) {
+			continue
+		}
+
+		path := []T{timed[0].FromState}
+		for _, tr := range timed {
+			path = append(path, tr.ToState)
+		}
+
+		key := fmt.Sprint(path)
+		if paths[key] == nil {
+			paths[key] = &pathStats[T]{path: path}
+		}
+		paths[key].add(timed[len(timed)-1].Timestamp.Sub(*timed[0].Timestamp))
+	}
+
+	var report BottleneckReport[T]
+
+	for state, stats := range states {
+		report.States = append(report.States, StateStats[T]{State: state, DurationStats: *stats})
+	}
+	sort.Slice(report.States, func(i, j int) bool {
+		a, b := report.States[i], report.States[j]
+		return a.Total > b.Total || (a.Total == b.Total && toString(a.State) < toString(b.State))
+	})
+
+	for e, stats := range edges {
+		report.Edges = append(report.Edges, EdgeStats[T]{FromState: e.from, ToState: e.to, DurationStats: *stats})
+	}
+	sort.Slice(report.Edges, func(i, j int) bool {
+		a, b := report.Edges[i], report.Edges[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return toString(a.FromState)+"\x00"+toString(a.ToState) < toString(b.FromState)+"\x00"+toString(b.ToState)
+	})
+
+	var keys []string
+	for key := range paths {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if mean := paths[key].Mean(); report.CriticalPath == nil || mean > report.CriticalPathDuration {
+			report.CriticalPath = paths[key].path
+			report.CriticalPathDuration = mean
+		}
+	}
+
+	return report
+}
+
+// pathStats aggregates the durations of histories that followed the same path
+type pathStats[T comparable] struct {
+	path []T
+	DurationStats
+}
+
+// MermaidDiagram renders the observed edges as a Mermaid.js diagram labeled with their mean durations
+// Edges on the critical path are drawn as thick arrows
+// In order to generate a diagram, T must be a string or have a String() method
+func (r BottleneckReport[T]) MermaidDiagram() (string, error) {
+	if len(r.Edges) == 0 {
+		return "", fmt.Errorf("no edges observed")
+	}
+
+	if !stringable(r.Edges[0].FromState) {
+		return "", fmt.Errorf("type T is not a string or does not have a String() method")
+	}
+
+	critical := make(map[edge[T]]bool)
+	for i := 1; i < len(r.CriticalPath); i++ {
+		critical[edge[T]{from: r.CriticalPath[i-1], to: r.CriticalPath[i]}] = true
+	}
+
+	var lines []string
+	for _, e := range r.Edges {
+		arrow := "-->"
+		if critical[edge[T]{from: e.FromState, to: e.ToState}] {
+			arrow = "==>"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s|%v| %s;\n", toString(e.FromState), arrow, e.Mean(), toString(e.ToState)))
+	}
+	sort.Strings(lines)
+
+	return "graph LR;\n" + strings.Join(lines, ""), nil
+}
@@ -0,0 +1,54 @@
+package statetrooper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// timedHistory builds a history visiting states at the given minute offsets
+func timedHistory(start time.Time, states []string, minutes []int) []Transition[string] {
+	var history []Transition[string]
+	for i := 1; i < len(states); i++ {
+		ts := start.Add(time.Duration(minutes[i-1]) * time.Minute)
+		history = append(history, Transition[string]{FromState: states[i-1], ToState: states[i], Timestamp: &ts})
+	}
+	return history
+}
+
+func Test_analyzeBottlenecks(t *testing.T) {
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	histories := [][]Transition[string]{
+		timedHistory(start, []string{"created", "picked", "packed", "shipped"}, []int{0, 10, 70}),
+		timedHistory(start, []string{"created", "picked", "packed", "shipped"}, []int{0, 20, 60}),
+		timedHistory(start, []string{"created", "picked", "canceled"}, []int{0, 5}),
+		timedHistory(start, []string{"created", "picked", "packed"}, []int{0, 200}),
+	}
+
+	report := AnalyzeBottlenecks(histories, "shipped", "canceled")
+
+	if top := report.States[0]; top.State != "picked" || top.Total != 235*time.Minute || top.Count != 4 {
+		t.Errorf("Top state is %+v, expected picked with 235m over 4 visits", top)
+	}
+
+	if top := report.Edges[0]; top.FromState != "picked" || top.ToState != "packed" || top.Max != 200*time.Minute {
+		t.Errorf("Top edge is %+v, expected picked -> packed with a 200m maximum", top)
+	}
+
+	expectedPath := []string{"created", "picked", "packed", "shipped"}
+	if !reflect.DeepEqual(report.CriticalPath, expectedPath) || report.CriticalPathDuration != 65*time.Minute {
+		t.Errorf("Critical path is %v taking %v, expected %v taking 65m",
+			report.CriticalPath, report.CriticalPathDuration, expectedPath)
+	}
+
+	diagram, err := report.MermaidDiagram()
+	if err != nil {
+		t.Fatalf("MermaidDiagram() returned an error: %v", err)
+	}
+
+	if !strings.Contains(diagram, "packed ==>|50m0s| shipped;") || !strings.Contains(diagram, "picked -->|5m0s| canceled;") {
+		t.Errorf("MermaidDiagram() returned %q", diagram)
+	}
+}
@@ -0,0 +1,156 @@
+package statetrooper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a Breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets all calls through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects all calls with ErrBreakerOpen until the open period has elapsed
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe call through to test whether the target has recovered
+	BreakerHalfOpen
+)
+
+// BreakerStats are the counters of a Breaker, for exporting as metrics
+type BreakerStats struct {
+	State     BreakerState
+	Successes uint64
+	Failures  uint64
+	// Rejected counts calls rejected without being made while the breaker was open
+	Rejected uint64
+	// Trips counts how many times the breaker has opened
+	Trips uint64
+}
+
+// Breaker is a circuit breaker for calls to a downstream target such as a webhook
+// After threshold consecutive failures it opens and rejects calls for the open period,
+// so transitions don't each pay the cost of a timeout while the target is down
+// It then lets a single probe through: success closes it again and failure reopens it
+// A Breaker is safe for concurrent use and may be shared by several hooks calling the same target
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	openFor   time.Duration
+	now       func() time.Time
+
+	failures int
+	openedAt time.Time
+	probing  bool
+	stats    BreakerStats
+}
+
+// NewBreaker creates a closed breaker that opens after threshold consecutive failures for openFor
+func NewBreaker(threshold int, openFor time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &Breaker{
+		threshold: threshold,
+		openFor:   openFor,
+		now:       time.Now,
+	}
+}
+
+// BreakerHook wraps hook so that it is called through b
+// While b is open the hook is not called and ErrBreakerOpen is returned instead
+func BreakerHook[T comparable](b *Breaker, hook Hook[T]) Hook[T] {
+	return func(ctx context.Context, tr Transition[T]) error {
+		return b.Call(func() error {
+			return hook(ctx, tr)
+		})
+	}
+}
+
+// Call calls fn unless the breaker is open, in which case it returns ErrBreakerOpen
+func (b *Breaker) Call(fn func() error) error {
+	if !b.allow() {
+		return ErrBreakerOpen
+	}
+
+	err := fn()
+	b.done(err == nil)
+
+	return err
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state()
+}
+
+// Stats returns the counters of the breaker
+func (b *Breaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.State = b.state()
+
+	return stats
+}
+
+// state returns the current state. The caller must hold the lock
+func (b *Breaker) state() BreakerState {
+	switch {
+	case b.openedAt.IsZero():
+		return BreakerClosed
+	case b.probing || b.now().Sub(b.openedAt) >= b.openFor:
+		return BreakerHalfOpen
+	default:
+		return BreakerOpen
+	}
+}
+
+// allow reports whether a call may be made, claiming the probe if the breaker is half-open
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state() {
+	case BreakerClosed:
+		return true
+	case BreakerHalfOpen:
+		if !b.probing {
+			b.probing = true
+			return true
+		}
+	}
+
+	b.stats.Rejected++
+	return false
+}
+
+// done records the outcome of a call
+func (b *Breaker) done(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	probe := b.probing
+	b.probing = false
+
+	if ok {
+		b.stats.Successes++
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	b.stats.Failures++
+	b.failures++
+
+	if probe || (b.openedAt.IsZero() && b.failures >= b.threshold) {
+		b.openedAt = b.now()
+		b.stats.Trips++
+	}
+}
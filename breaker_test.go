@@ -0,0 +1,66 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_breaker(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	errDown := errors.New("webhook down")
+	down := true
+	calls := 0
+
+	var hookErrs []error
+	fsm := newPingPongFSM()
+	fsm.SetHookErrorHandler(func(tr Transition[CustomStateEnum], err error) {
+		hookErrs = append(hookErrs, err)
+	})
+	fsm.AddHook(PostCommit, 0, BreakerHook(b, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		calls++
+		if down {
+			return errDown
+		}
+		return nil
+	}))
+
+	// Two failures trip the breaker and further calls are rejected without reaching the hook
+	pingPong(fsm, 4)
+	if calls != 2 || b.State() != BreakerOpen {
+		t.Fatalf("Hook was called %d times and the breaker is %v, expected 2 calls and an open breaker", calls, b.State())
+	}
+
+	if !errors.Is(hookErrs[0], errDown) || !errors.Is(hookErrs[3], ErrBreakerOpen) {
+		t.Errorf("Hook errors are %v, expected the hook's error and then ErrBreakerOpen", hookErrs)
+	}
+
+	// After the open period a failing probe reopens the breaker
+	now = now.Add(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Errorf("Breaker is %v after the open period, expected half-open", b.State())
+	}
+
+	pingPong(fsm, 2)
+	if calls != 3 || b.State() != BreakerOpen {
+		t.Errorf("Hook was called %d times and the breaker is %v, expected a single probe to reopen it", calls, b.State())
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	down = false
+	pingPong(fsm, 2)
+	if calls != 5 || b.State() != BreakerClosed {
+		t.Errorf("Hook was called %d times and the breaker is %v, expected a successful probe to close it", calls, b.State())
+	}
+
+	stats := b.Stats()
+	expected := BreakerStats{State: BreakerClosed, Successes: 2, Failures: 3, Rejected: 3, Trips: 2}
+	if stats != expected {
+		t.Errorf("Stats() returned %+v, expected %+v", stats, expected)
+	}
+}
@@ -0,0 +1,88 @@
+package statetrooper
+
+// SetMaxTransitions sets the maximum number of transitions the FSM may perform in total
+// Zero means unlimited
+func (fsm *FSM[T]) SetMaxTransitions(n int) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.maxTransitions = n
+}
+
+// SetEdgeMaxTransitions sets the maximum number of times the transition from fromState to toState may be performed
+// Zero means unlimited
+func (fsm *FSM[T]) SetEdgeMaxTransitions(fromState T, toState T, n int) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	e := edge[T]{from: fromState, to: toState}
+
+	if n <= 0 {
+		delete(fsm.edgeMaxTransitions, e)
+		return
+	}
+
+	if fsm.edgeMaxTransitions == nil {
+		fsm.edgeMaxTransitions = make(map[edge[T]]int)
+	}
+
+	fsm.edgeMaxTransitions[e] = n
+}
+
+// TransitionCount returns the total number of transitions performed by the FSM
+func (fsm *FSM[T]) TransitionCount() int {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.transitionCount
+}
+
+// EdgeCount returns the number of times the transition from fromState to toState has been performed
+func (fsm *FSM[T]) EdgeCount(fromState T, toState T) int {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.edgeCounts[edge[T]{from: fromState, to: toState}]
+}
+
+// checkBudget returns a BudgetError if the transition would exceed the total or per-edge budget
+func (fsm *FSM[T]) checkBudget(fromState *T, toState *T) error {
+	if fsm.maxTransitions > 0 && fsm.transitionCount >= fsm.maxTransitions {
+		return BudgetError[T]{
+			FromState: *fromState,
+			ToState:   *toState,
+			Limit:     fsm.maxTransitions,
+		}
+	}
+
+	for _, e := range fsm.configuredEdges(len(fsm.edgeMaxTransitions), *fromState, *toState) {
+		if limit, ok := fsm.edgeMaxTransitions[e]; ok && fsm.edgeCounts[e] >= limit {
+			return BudgetError[T]{
+				FromState: *fromState,
+				ToState:   *toState,
+				Limit:     limit,
+			}
+		}
+	}
+
+	return nil
+}
+
+// countTransition increments the total and per-edge transition counters
+// Rules from or to composite states that apply to the transition are only counted if they have a budget
+func (fsm *FSM[T]) countTransition(tr *Transition[T]) {
+	fsm.transitionCount++
+
+	if fsm.edgeCounts == nil {
+		fsm.edgeCounts = make(map[edge[T]]int)
+	}
+
+	actual := edge[T]{from: tr.FromState, to: tr.ToState}
+	fsm.edgeCounts[actual]++
+
+	for _, e := range fsm.configuredEdges(len(fsm.edgeMaxTransitions), tr.FromState, tr.ToState) {
+		if _, ok := fsm.edgeMaxTransitions[e]; ok && e != actual {
+			fsm.edgeCounts[e]++
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_budget(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA, CustomStateEnumC)
+	fsm.SetEdgeMaxTransitions(CustomStateEnumB, CustomStateEnumA, 2)
+	fsm.SetMaxTransitions(6)
+
+	// A -> B -> A -> B -> A -> B uses up the B -> A budget
+	for i := 0; i < 2; i++ {
+		fsm.Transition(CustomStateEnumB, nil)
+		fsm.Transition(CustomStateEnumA, nil)
+	}
+	fsm.Transition(CustomStateEnumB, nil)
+
+	_, err := fsm.Transition(CustomStateEnumA, nil)
+
+	var bErr BudgetError[CustomStateEnum]
+	if !errors.Is(err, ErrBudgetExceeded) || !errors.As(err, &bErr) || bErr.Limit != 2 {
+		t.Errorf("Transition over edge budget returned %v, expected a BudgetError with limit 2", err)
+	}
+
+	if fsm.EdgeCount(CustomStateEnumB, CustomStateEnumA) != 2 {
+		t.Errorf("EdgeCount(%v, %v) = %d, expected 2", CustomStateEnumB, CustomStateEnumA, fsm.EdgeCount(CustomStateEnumB, CustomStateEnumA))
+	}
+
+	// Other transitions are still allowed until the total budget is used up
+	if _, err := fsm.Transition(CustomStateEnumC, nil); err != nil {
+		t.Errorf("Transition(%v) returned an error: %v", CustomStateEnumC, err)
+	}
+
+	if fsm.TransitionCount() != 6 {
+		t.Errorf("TransitionCount() = %d, expected 6", fsm.TransitionCount())
+	}
+
+	fsm.AllowSelfLoops(true)
+	fsm.AddRule(CustomStateEnumC, CustomStateEnumC)
+
+	if _, err := fsm.Transition(CustomStateEnumC, nil); !errors.As(err, &bErr) || bErr.Limit != 6 {
+		t.Errorf("Transition over total budget returned %v, expected a BudgetError with limit 6", err)
+	}
+}
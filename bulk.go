@@ -0,0 +1,63 @@
+package statetrooper
+
+import (
+	"errors"
+	"sort"
+)
+
+// AddRules adds all rules of an adjacency map, such as one loaded from configuration
+// Every rule is validated as by AddRule. If any is invalid, an error joining the problems
+// of all invalid rules is returned and no rules are added
+func (fsm *FSM[T]) AddRules(rules map[T][]T) error {
+	from := make([]T, 0, len(rules))
+	for state := range rules {
+		from = append(from, state)
+	}
+
+	// Map iteration order is random, so sort for a stable insertion order and error message
+	sort.Slice(from, func(i, j int) bool {
+		return toString(from[i]) < toString(from[j])
+	})
+
+	return fsm.addRules(from, rules)
+}
+
+// AddRulesFromPairs adds the rules given as from, to pairs, keeping their order
+// It validates the rules like AddRules, so a pair listed twice is reported as a duplicate
+func (fsm *FSM[T]) AddRulesFromPairs(pairs [][2]T) error {
+	var from []T
+	rules := make(map[T][]T)
+
+	for _, pair := range pairs {
+		if _, ok := rules[pair[0]]; !ok {
+			from = append(from, pair[0])
+		}
+		rules[pair[0]] = append(rules[pair[0]], pair[1])
+	}
+
+	return fsm.addRules(from, rules)
+}
+
+// addRules validates and adds the rules from each state in from, in order
+func (fsm *FSM[T]) addRules(from []T, rules map[T][]T) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	var errs []error
+	for i := range from {
+		if err := fsm.checkRule(&from[i], rules[from[i]]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	fsm.ownRules()
+	for _, state := range from {
+		fsm.ruleset[state] = append(fsm.ruleset[state], rules[state]...)
+	}
+
+	return nil
+}
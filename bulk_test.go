@@ -0,0 +1,66 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_addRules(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+
+	err := fsm.AddRules(map[CustomStateEnum][]CustomStateEnum{
+		CustomStateEnumA: {CustomStateEnumB, CustomStateEnumC},
+		CustomStateEnumB: {CustomStateEnumC},
+	})
+	if err != nil {
+		t.Fatalf("AddRules() returned an error: %v", err)
+	}
+
+	expected := map[CustomStateEnum][]CustomStateEnum{
+		CustomStateEnumA: {CustomStateEnumB, CustomStateEnumC},
+		CustomStateEnumB: {CustomStateEnumC},
+	}
+	if !reflect.DeepEqual(fsm.Rules(), expected) {
+		t.Errorf("Rules() returned %v, expected %v", fsm.Rules(), expected)
+	}
+
+	// An invalid rule rejects the whole batch, and all problems are reported
+	err = fsm.AddRules(map[CustomStateEnum][]CustomStateEnum{
+		CustomStateEnumA: {CustomStateEnumB},
+		CustomStateEnumC: {CustomStateEnumC},
+		CustomStateEnumD: {CustomStateEnumA},
+	})
+	if !errors.Is(err, ErrDuplicateRule) || !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("AddRules() returned %v, expected ErrDuplicateRule and ErrSelfLoop", err)
+	}
+
+	if _, ok := fsm.Rules()[CustomStateEnumD]; ok {
+		t.Errorf("AddRules() added rules from a rejected batch")
+	}
+}
+
+func Test_addRulesFromPairs(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+
+	err := fsm.AddRulesFromPairs([][2]CustomStateEnum{
+		{CustomStateEnumA, CustomStateEnumC},
+		{CustomStateEnumB, CustomStateEnumA},
+		{CustomStateEnumA, CustomStateEnumB},
+	})
+	if err != nil {
+		t.Fatalf("AddRulesFromPairs() returned an error: %v", err)
+	}
+
+	if rules := fsm.Rules(); !reflect.DeepEqual(rules[CustomStateEnumA], []CustomStateEnum{CustomStateEnumC, CustomStateEnumB}) {
+		t.Errorf("Rules from A are %v, expected the order of the pairs", rules[CustomStateEnumA])
+	}
+
+	err = fsm.AddRulesFromPairs([][2]CustomStateEnum{
+		{CustomStateEnumC, CustomStateEnumD},
+		{CustomStateEnumC, CustomStateEnumD},
+	})
+	if !errors.Is(err, ErrDuplicateRule) {
+		t.Errorf("AddRulesFromPairs() returned %v for a repeated pair, expected ErrDuplicateRule", err)
+	}
+}
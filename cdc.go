@@ -0,0 +1,99 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// CDC operations, as used by Debezium
+const (
+	// CDCCreate is the operation of the transition into the initial state
+	CDCCreate = "c"
+	// CDCUpdate is the operation of every other transition
+	CDCUpdate = "u"
+)
+
+// CDCEvent is a transition in the change event envelope used by Debezium, so it can be consumed by
+// existing change-data-capture pipelines
+type CDCEvent[T comparable] struct {
+	Op     string     `json:"op"`
+	Before *CDCRow[T] `json:"before"`
+	After  *CDCRow[T] `json:"after"`
+	Source CDCSource  `json:"source"`
+	// TsMs is the time of the transition in milliseconds since the Unix epoch
+	TsMs int64 `json:"ts_ms"`
+}
+
+// CDCRow is the state of an entity before or after a change
+type CDCRow[T comparable] struct {
+	State    T                 `json:"state"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// CDCSource describes where a change event came from
+type CDCSource struct {
+	Connector string `json:"connector"`
+	// Name identifies the entity whose FSM made the change
+	Name string `json:"name"`
+	// Sequence is the transition's ID, which orders the entity's events
+	Sequence uint64 `json:"sequence"`
+	TsMs     int64  `json:"ts_ms"`
+}
+
+// CDCConnector is the connector name set on the source of change events
+const CDCConnector = "statetrooper"
+
+// NewCDCEvent converts a transition of the FSM identified by name into a change event
+// It can be used with Subscribe to stream live transitions
+func NewCDCEvent[T comparable](name string, tr Transition[T]) CDCEvent[T] {
+	var ts int64
+	if tr.Timestamp != nil {
+		ts = tr.Timestamp.UnixMilli()
+	}
+
+	event := CDCEvent[T]{
+		Op:    CDCUpdate,
+		After: &CDCRow[T]{State: tr.ToState, Metadata: tr.Metadata},
+		Source: CDCSource{
+			Connector: CDCConnector,
+			Name:      name,
+			Sequence:  tr.ID,
+			TsMs:      ts,
+		},
+		TsMs: ts,
+	}
+
+	if tr.Initial {
+		event.Op = CDCCreate
+	} else {
+		event.Before = &CDCRow[T]{State: tr.FromState}
+	}
+
+	if tr.Cycles > 0 {
+		event.After.State = tr.FromState
+	}
+
+	return event
+}
+
+// ExportCDC writes the history to w as NDJSON change events, one per line, for the FSM identified by name
+// An empty name identifies the FSM by its machine name and entity ID as name/entity
+// Failed attempts are skipped since they changed nothing. A compacted loop is a single event that ends where it started
+// Metadata is redacted according to the rules set with SetRedactionRules
+func (fsm *FSM[T]) ExportCDC(w io.Writer, name string) error {
+	fsm.mu.Lock()
+	history := fsm.redact(succeeded(fsm.transitions))
+	if name == "" {
+		name = identity(fsm.name, fsm.entityID)
+	}
+	fsm.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	for _, tr := range history {
+		if err := enc.Encode(NewCDCEvent(name, tr)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
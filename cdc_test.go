@@ -0,0 +1,52 @@
+package statetrooper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_exportCDC(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	fsm := NewUnstartedFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.SetClock(func() time.Time { return now })
+	fsm.SetRecordFailures(true)
+	fsm.Start(context.Background())
+	fsm.Transition(CustomStateEnumC, nil)
+	fsm.Transition(CustomStateEnumB, map[string]string{"by": "alice"})
+
+	var buf bytes.Buffer
+	if err := fsm.ExportCDC(&buf, "order-1"); err != nil {
+		t.Fatalf("ExportCDC() returned an error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ExportCDC() wrote %d events, expected 2 without the failed attempt: %s", len(lines), buf.String())
+	}
+
+	expected := []string{
+		`{"op":"c","before":null,"after":{"state":"A"},"source":{"connector":"statetrooper","name":"order-1","sequence":1,"ts_ms":1688212800000},"ts_ms":1688212800000}`,
+		`{"op":"u","before":{"state":"A"},"after":{"state":"B","metadata":{"by":"alice"}},"source":{"connector":"statetrooper","name":"order-1","sequence":3,"ts_ms":1688212800000},"ts_ms":1688212800000}`,
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("ExportCDC() wrote %s, expected %s", lines[i], expected[i])
+		}
+	}
+
+	var event CDCEvent[CustomStateEnum]
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil || event.Before.State != CustomStateEnumA {
+		t.Errorf("Event %s does not decode into a CDCEvent: %v", lines[1], err)
+	}
+
+	loop := NewCDCEvent("order-1", Transition[CustomStateEnum]{FromState: CustomStateEnumA, ToState: CustomStateEnumB, Cycles: 2})
+	if loop.Before.State != CustomStateEnumA || loop.After.State != CustomStateEnumA {
+		t.Errorf("Compacted loop event is %+v, expected it to end where it started", loop)
+	}
+}
@@ -0,0 +1,63 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TransitionFirstValid transitions to the first of candidates that passes the rules, guards and other checks,
+// such as trying "auto_approved" before falling back to "manual_review"
+// The candidates are tried in order under a single lock, so the choice cannot be invalidated by a concurrent
+// transition. If none is valid, an error wrapping ErrNoValidCandidate and each candidate's error is returned
+func (fsm *FSM[T]) TransitionFirstValid(candidates []T, metadata map[string]string) (T, error) {
+	return fsm.TransitionFirstValidCtx(context.Background(), candidates, metadata)
+}
+
+// TransitionFirstValidCtx is like TransitionFirstValid but passes ctx to guards and hooks
+func (fsm *FSM[T]) TransitionFirstValidCtx(ctx context.Context, candidates []T, metadata map[string]string) (T, error) {
+	state, committed, err := fsm.transitionFirstValid(ctx, candidates, metadata)
+	if committed != nil {
+		var actionErr error
+		state, actionErr = fsm.afterCommitChain(ctx, committed)
+		err = errors.Join(err, actionErr)
+	}
+
+	return state, err
+}
+
+// transitionFirstValid commits the first valid candidate, and any automatic transitions that follow, under the lock
+func (fsm *FSM[T]) transitionFirstValid(ctx context.Context, candidates []T, metadata map[string]string) (T, []*Transition[T], error) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	metadata = fsm.tag(ctx, metadata)
+
+	errs := make([]error, 0, len(candidates))
+	for _, candidate := range candidates {
+		tr, err := fsm.prepare(ctx, candidate, metadata)
+		if err != nil {
+			// No other candidate can succeed once the context is done
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return fsm.currentState, nil, ctxErr
+			}
+
+			errs = append(errs, fmt.Errorf("%v: %w", candidate, err))
+			continue
+		}
+
+		if tr == nil {
+			return fsm.currentState, nil, nil
+		}
+
+		chain, err := fsm.commitChain(ctx, tr)
+
+		return fsm.currentState, chain, err
+	}
+
+	if len(errs) == 0 {
+		return fsm.currentState, nil, ErrNoValidCandidate
+	}
+
+	return fsm.currentState, nil, fmt.Errorf("%w: %w", ErrNoValidCandidate, errors.Join(errs...))
+}
@@ -0,0 +1,48 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_transitionFirstValid(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+
+	rejected := errors.New("score too low")
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return rejected
+	})
+
+	// D has no rule and B is rejected by its guard, so C is chosen
+	state, err := fsm.TransitionFirstValid([]CustomStateEnum{CustomStateEnumD, CustomStateEnumB, CustomStateEnumC}, nil)
+	if err != nil || state != CustomStateEnumC {
+		t.Errorf("TransitionFirstValid() returned %v, %v, expected C", state, err)
+	}
+
+	if trs := fsm.Transitions(); len(trs) != 1 || trs[0].ToState != CustomStateEnumC {
+		t.Errorf("History is %v, expected only the transition to C", trs)
+	}
+
+	// With no valid candidate, every candidate's error is reported
+	fsm = NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return rejected
+	})
+
+	var transitionErr TransitionError[CustomStateEnum]
+	_, err = fsm.TransitionFirstValid([]CustomStateEnum{CustomStateEnumB, CustomStateEnumC}, nil)
+	if !errors.Is(err, ErrNoValidCandidate) || !errors.Is(err, rejected) || !errors.As(err, &transitionErr) {
+		t.Errorf("TransitionFirstValid() returned %v, expected ErrNoValidCandidate with the guard and rule errors", err)
+	}
+
+	if _, err := fsm.TransitionFirstValid(nil, nil); !errors.Is(err, ErrNoValidCandidate) {
+		t.Errorf("TransitionFirstValid() returned %v without candidates, expected ErrNoValidCandidate", err)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumA {
+		t.Errorf("CurrentState() returned %v, expected A to be unchanged", fsm.CurrentState())
+	}
+}
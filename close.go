@@ -0,0 +1,115 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+)
+
+// Close shuts the FSM down for a clean service shutdown
+// New transitions are rejected with ErrClosed, pending retries are cancelled, queued asynchronous hooks are
+// drained and subscriptions are closed, so subscribers receive what is buffered and then see their channel closed
+// If ctx is done before the hooks are drained, ctx.Err() is returned and the remaining hooks finish in the
+// background. Otherwise the errors reported by asynchronous hooks are returned. Closing a closed FSM does nothing
+func (fsm *FSM[T]) Close(ctx context.Context) error {
+	fsm.mu.Lock()
+	if fsm.closed {
+		fsm.mu.Unlock()
+		return nil
+	}
+
+	fsm.closed = true
+
+	retries := make([]*Retry[T], 0, len(fsm.retries))
+	for r := range fsm.retries {
+		retries = append(retries, r)
+	}
+
+	a := fsm.asyncHooks
+	fsm.asyncHooks = nil
+	subs := fsm.subscribers
+	fsm.mu.Unlock()
+
+	for _, r := range retries {
+		r.Cancel()
+	}
+
+	var err error
+	if a != nil {
+		err = waitCtx(ctx, a.stop)
+	}
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+
+	return err
+}
+
+// Closed reports whether Close has been called
+func (fsm *FSM[T]) Closed() bool {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.closed
+}
+
+// Close closes the FSMs of all entities, including those of tenants, and rejects further Adds with ErrClosed
+// The FSMs are closed concurrently, bounded by ctx, and their errors are joined
+func (m *Manager[K, T]) Close(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+
+	fsms := make([]*FSM[T], 0, len(m.fsms))
+	for _, fsm := range m.fsms {
+		fsms = append(fsms, fsm)
+	}
+
+	tenants := make([]*Manager[K, T], 0, len(m.tenants))
+	for _, tenant := range m.tenants {
+		tenants = append(tenants, tenant)
+	}
+	m.mu.Unlock()
+
+	errs := make(chan error, len(fsms)+len(tenants))
+	for _, fsm := range fsms {
+		go func(fsm *FSM[T]) { errs <- fsm.Close(ctx) }(fsm)
+	}
+	for _, tenant := range tenants {
+		go func(tenant *Manager[K, T]) { errs <- tenant.Close(ctx) }(tenant)
+	}
+
+	var joined []error
+	for i := 0; i < cap(errs); i++ {
+		joined = append(joined, <-errs)
+	}
+
+	return errors.Join(joined...)
+}
+
+// Close stops accepting commands and waits, bounded by ctx, for the queued commands to be processed
+// It does not close the FSM. If ctx is done first, ctx.Err() is returned and processing continues in the background
+func (m *Mailbox[T]) Close(ctx context.Context) error {
+	return waitCtx(ctx, func() error {
+		m.Stop()
+		return nil
+	})
+}
+
+// waitCtx runs fn and waits for it to return or for ctx to be done, whichever happens first
+func waitCtx(ctx context.Context, fn func() error) error {
+	if ctx.Done() == nil {
+		return fn()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- fn()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
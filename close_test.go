@@ -0,0 +1,124 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_close(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetAsyncHooks(1, 10)
+
+	release := make(chan struct{})
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		<-release
+		return nil
+	})
+
+	sub, _ := fsm.Subscribe(SubscriptionOptions{Buffer: 5})
+	fsm.AddGuard(CustomStateEnumB, CustomStateEnumA, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errors.New("not yet")
+	})
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Fatalf("Transition() returned an error: %v", err)
+	}
+
+	retry := fsm.RetryTransition(CustomStateEnumA, nil, RetryPolicy{InitialBackoff: time.Hour})
+
+	// Close waits for the queued hook, bounded by the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := fsm.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() returned %v, expected context.DeadlineExceeded while a hook is running", err)
+	}
+
+	if !fsm.Closed() {
+		t.Errorf("Closed() returned false after Close()")
+	}
+
+	select {
+	case <-retry.Done():
+		if _, err := retry.Result(); !errors.Is(err, ErrRetryCancelled) {
+			t.Errorf("Retry ended with %v, expected ErrRetryCancelled", err)
+		}
+	default:
+		t.Errorf("Retry is still pending after Close()")
+	}
+
+	// Buffered transitions are delivered before the channel is closed
+	if tr, ok := <-sub.C(); !ok || tr.ToState != CustomStateEnumB {
+		t.Errorf("Subscription received %v, %v, expected the transition to B", tr, ok)
+	}
+	if _, ok := <-sub.C(); ok {
+		t.Errorf("Subscription channel is still open after Close()")
+	}
+
+	if _, err := fsm.Transition(CustomStateEnumA, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Transition() returned %v after Close(), expected ErrClosed", err)
+	}
+
+	if ex := fsm.Explain(CustomStateEnumA); ex.Reason != RejectClosed {
+		t.Errorf("Explain() returned reason %v after Close(), expected RejectClosed", ex.Reason)
+	}
+
+	close(release)
+	if err := fsm.Close(context.Background()); err != nil {
+		t.Errorf("Close() returned %v when already closed, expected nil", err)
+	}
+
+	// Without a deadline Close reports the errors of the drained hooks
+	other := newPingPongFSM()
+	other.SetAsyncHooks(1, 10)
+	other.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errors.New("hook failed")
+	})
+	other.Transition(CustomStateEnumB, nil)
+
+	if err := other.Close(context.Background()); err == nil || err.Error() != "hook failed" {
+		t.Errorf("Close() returned %v, expected the hook's error", err)
+	}
+}
+
+func Test_managerClose(t *testing.T) {
+	m := NewManager[string, CustomStateEnum]()
+	m.Add("a", newPingPongFSM())
+	m.Tenant("acme").Add("b", newPingPongFSM())
+
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatalf("Close() returned an error: %v", err)
+	}
+
+	for _, fsm := range []*FSM[CustomStateEnum]{mustGet(m, "a"), mustGet(m.Tenant("acme"), "b")} {
+		if !fsm.Closed() {
+			t.Errorf("FSM is not closed after closing the Manager")
+		}
+	}
+
+	if err := m.Add("c", newPingPongFSM()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Add() returned %v after Close(), expected ErrClosed", err)
+	}
+
+	if err := m.Tenant("new").Add("c", newPingPongFSM()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Add() to a new tenant returned %v after Close(), expected ErrClosed", err)
+	}
+
+	fsm := newPingPongFSM()
+	mailbox := NewMailbox(fsm)
+	result := mailbox.Send(CustomStateEnumB, nil, 0)
+
+	if err := mailbox.Close(context.Background()); err != nil {
+		t.Errorf("Mailbox Close() returned an error: %v", err)
+	}
+	if r := <-result; r.Err != nil || fsm.CurrentState() != CustomStateEnumB {
+		t.Errorf("Queued command returned %+v, expected it to be processed before closing", r)
+	}
+}
+
+func mustGet(m *Manager[string, CustomStateEnum], id string) *FSM[CustomStateEnum] {
+	fsm, _ := m.Get(id)
+	return fsm
+}
@@ -0,0 +1,208 @@
+// Command statetrooper-gen generates a state enum type, its constants and the FSM rule wiring
+// from a JSON machine definition, so the definition file is the single source of truth
+//
+// Usage:
+//
+//	statetrooper-gen -in order.json -out order_fsm.go
+//
+// A definition looks like
+//
+//	{
+//		"package": "order",
+//		"type": "OrderStatus",
+//		"states": ["created", "picked", "canceled"],
+//		"rules": {"created": ["picked", "canceled"], "picked": ["canceled"]}
+//	}
+//
+// It can be wired up with go:generate:
+//
+//	//go:generate go run github.com/hishamk/statetrooper/cmd/statetrooper-gen -in order.json -out order_fsm.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Definition describes a state machine to generate code for
+type Definition struct {
+	Package string              `json:"package"`
+	Type    string              `json:"type"`
+	States  []string            `json:"states"`
+	Rules   map[string][]string `json:"rules"`
+}
+
+func main() {
+	in := flag.String("in", "", "path of the JSON machine definition")
+	out := flag.String("out", "", "path of the generated Go file (default stdout)")
+	flag.Parse()
+
+	if err := run(*in, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "statetrooper-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out string) error {
+	if in == "" {
+		return errors.New("-in is required")
+	}
+
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+
+	var def Definition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return fmt.Errorf("parsing %s: %w", in, err)
+	}
+
+	src, err := generate(def)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(out, src, 0o644)
+}
+
+// generate returns the formatted Go source for def
+func generate(def Definition) ([]byte, error) {
+	if err := validate(def); err != nil {
+		return nil, err
+	}
+
+	idents := make(map[string]string, len(def.States))
+	for _, state := range def.States {
+		idents[state] = def.Type + exportedName(state)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by statetrooper-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", def.Package)
+	fmt.Fprintf(&b, "import \"github.com/hishamk/statetrooper\"\n\n")
+
+	fmt.Fprintf(&b, "// %s is a state of the %s state machine\n", def.Type, def.Type)
+	fmt.Fprintf(&b, "type %s string\n\n", def.Type)
+
+	fmt.Fprintf(&b, "const (\n")
+	for _, state := range def.States {
+		fmt.Fprintf(&b, "%s %s = %q\n", idents[state], def.Type, state)
+	}
+	fmt.Fprintf(&b, ")\n\n")
+
+	fmt.Fprintf(&b, "func (s %s) String() string {\nreturn string(s)\n}\n\n", def.Type)
+
+	fmt.Fprintf(&b, "// %sStates lists all %s states in definition order\n", def.Type, def.Type)
+	fmt.Fprintf(&b, "var %sStates = []%s{\n", def.Type, def.Type)
+	for _, state := range def.States {
+		fmt.Fprintf(&b, "%s,\n", idents[state])
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "// New%sFSM creates an FSM with the %s states registered and rules added\n", def.Type, def.Type)
+	fmt.Fprintf(&b, "func New%sFSM(initialState %s, maxHistory int) (*statetrooper.FSM[%s], error) {\n", def.Type, def.Type, def.Type)
+	fmt.Fprintf(&b, "fsm := statetrooper.NewFSM[%s](initialState, maxHistory)\n", def.Type)
+	fmt.Fprintf(&b, "fsm.RegisterStates(%sStates...)\n\n", def.Type)
+	for _, state := range def.States {
+		targets := def.Rules[state]
+		if len(targets) == 0 {
+			continue
+		}
+
+		names := make([]string, len(targets))
+		for i, target := range targets {
+			names[i] = idents[target]
+		}
+
+		fmt.Fprintf(&b, "if err := fsm.AddRule(%s, %s); err != nil {\nreturn nil, err\n}\n", idents[state], strings.Join(names, ", "))
+	}
+	fmt.Fprintf(&b, "\nreturn fsm, nil\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// validate checks that def is complete and that its rules only reference declared states
+func validate(def Definition) error {
+	if !token.IsIdentifier(def.Package) {
+		return fmt.Errorf("invalid package name %q", def.Package)
+	}
+
+	if !token.IsIdentifier(def.Type) || !token.IsExported(def.Type) {
+		return fmt.Errorf("invalid type name %q, expected an exported identifier", def.Type)
+	}
+
+	if len(def.States) == 0 {
+		return errors.New("no states defined")
+	}
+
+	declared := make(map[string]bool, len(def.States))
+	idents := make(map[string]string, len(def.States))
+	for _, state := range def.States {
+		if declared[state] {
+			return fmt.Errorf("duplicate state %q", state)
+		}
+		declared[state] = true
+
+		name := exportedName(state)
+		if name == "" {
+			return fmt.Errorf("state %q has no usable identifier characters", state)
+		}
+		if other, ok := idents[name]; ok {
+			return fmt.Errorf("states %q and %q map to the same constant name", other, state)
+		}
+		idents[name] = state
+	}
+
+	for from, targets := range def.Rules {
+		if !declared[from] {
+			return fmt.Errorf("rule from undeclared state %q", from)
+		}
+
+		for _, to := range targets {
+			if !declared[to] {
+				return fmt.Errorf("rule from %q to undeclared state %q", from, to)
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportedName converts a state name such as "in_transit" or "on-hold" to "InTransit" or "OnHold"
+func exportedName(state string) string {
+	var b strings.Builder
+	upper := true
+
+	for _, r := range state {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteByte('S')
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_generate(t *testing.T) {
+	def := Definition{
+		Package: "order",
+		Type:    "OrderStatus",
+		States:  []string{"created", "in_transit", "canceled"},
+		Rules: map[string][]string{
+			"created":    {"in_transit", "canceled"},
+			"in_transit": {"canceled"},
+		},
+	}
+
+	src, err := generate(def)
+	if err != nil {
+		t.Fatalf("generate() returned an error: %v", err)
+	}
+
+	for _, want := range []string{
+		"package order",
+		"type OrderStatus string",
+		`OrderStatusInTransit OrderStatus = "in_transit"`,
+		"fsm.RegisterStates(OrderStatusStates...)",
+		"fsm.AddRule(OrderStatusCreated, OrderStatusInTransit, OrderStatusCanceled)",
+		"fsm.AddRule(OrderStatusInTransit, OrderStatusCanceled)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Generated source does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func Test_generateInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		def  Definition
+	}{
+		{"bad package", Definition{Package: "my-pkg", Type: "S", States: []string{"a"}}},
+		{"unexported type", Definition{Package: "p", Type: "state", States: []string{"a"}}},
+		{"no states", Definition{Package: "p", Type: "S"}},
+		{"duplicate state", Definition{Package: "p", Type: "S", States: []string{"a", "a"}}},
+		{"colliding names", Definition{Package: "p", Type: "S", States: []string{"on_hold", "on-hold"}}},
+		{"undeclared target", Definition{Package: "p", Type: "S", States: []string{"a"}, Rules: map[string][]string{"a": {"b"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generate(tt.def); err == nil {
+				t.Errorf("generate() returned no error")
+			}
+		})
+	}
+}
+
+func Test_exportedName(t *testing.T) {
+	tests := map[string]string{
+		"created":    "Created",
+		"in_transit": "InTransit",
+		"on-hold":    "OnHold",
+		"2fa":        "S2fa",
+	}
+
+	for in, want := range tests {
+		if got := exportedName(in); got != want {
+			t.Errorf("exportedName(%q) = %q, expected %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Package inspector is a terminal UI for browsing the entities of a statetrooper Manager, their states and
+// histories, and triggering transitions interactively, for example during an incident
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hishamk/statetrooper"
+)
+
+// SourceMetadataKey is the metadata key marking transitions triggered from the inspector
+const SourceMetadataKey = "source"
+
+// historyRows is the number of most recent transitions shown for an entity
+const historyRows = 15
+
+// screen is a view of the inspector
+type screen int
+
+const (
+	entitiesScreen screen = iota
+	entityScreen
+	confirmScreen
+)
+
+// Model is the bubbletea model of the inspector
+type Model[K comparable, T comparable] struct {
+	manager *statetrooper.Manager[K, T]
+	actor   string
+
+	screen screen
+	ids    []K
+	cursor int
+
+	fsm     *statetrooper.FSM[T]
+	targets []T
+	target  int
+
+	status string
+}
+
+// New creates an inspector for the entities of m. Transitions triggered from it are attributed to actor
+func New[K comparable, T comparable](m *statetrooper.Manager[K, T], actor string) *Model[K, T] {
+	model := &Model[K, T]{manager: m, actor: actor}
+	model.refresh()
+
+	return model
+}
+
+// Run runs the inspector for the entities of m in the terminal until the operator quits
+func Run[K comparable, T comparable](m *statetrooper.Manager[K, T], actor string) error {
+	_, err := tea.NewProgram(New(m, actor), tea.WithAltScreen()).Run()
+	return err
+}
+
+// Init implements tea.Model
+func (m *Model[K, T]) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *Model[K, T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "r":
+		m.refresh()
+		return m, nil
+	}
+
+	switch m.screen {
+	case entitiesScreen:
+		m.updateEntities(key.String())
+	case entityScreen:
+		m.updateEntity(key.String())
+	case confirmScreen:
+		m.updateConfirm(key.String())
+	}
+
+	return m, nil
+}
+
+func (m *Model[K, T]) updateEntities(key string) {
+	switch key {
+	case "up", "k":
+		m.cursor = clamp(m.cursor-1, len(m.ids))
+	case "down", "j":
+		m.cursor = clamp(m.cursor+1, len(m.ids))
+	case "enter":
+		if len(m.ids) == 0 {
+			return
+		}
+
+		fsm, ok := m.manager.Get(m.ids[m.cursor])
+		if !ok {
+			m.status = fmt.Sprintf("%v was removed", m.ids[m.cursor])
+			m.refresh()
+			return
+		}
+
+		m.fsm = fsm
+		m.screen = entityScreen
+		m.status = ""
+		m.refresh()
+	}
+}
+
+func (m *Model[K, T]) updateEntity(key string) {
+	switch key {
+	case "esc", "backspace":
+		m.screen = entitiesScreen
+		m.fsm = nil
+		m.status = ""
+		m.refresh()
+	case "up", "k":
+		m.target = clamp(m.target-1, len(m.targets))
+	case "down", "j":
+		m.target = clamp(m.target+1, len(m.targets))
+	case "enter":
+		if len(m.targets) > 0 {
+			m.screen = confirmScreen
+		}
+	}
+}
+
+func (m *Model[K, T]) updateConfirm(key string) {
+	switch key {
+	case "y":
+		m.transition()
+		m.screen = entityScreen
+		m.refresh()
+	case "n", "esc":
+		m.screen = entityScreen
+	}
+}
+
+// transition applies the selected transition and reports its outcome in the status line
+func (m *Model[K, T]) transition() {
+	target := m.targets[m.target]
+
+	state, err := m.fsm.TransitionCtx(m.context(), target, map[string]string{SourceMetadataKey: "inspector"})
+	if err != nil {
+		m.status = fmt.Sprintf("transition to %s failed: %v", statetrooper.DisplayName(target, ""), err)
+		return
+	}
+
+	m.status = fmt.Sprintf("moved to %s", statetrooper.DisplayName(state, ""))
+}
+
+// context returns the context transitions are checked and triggered with, carrying the operator as actor
+func (m *Model[K, T]) context() context.Context {
+	ctx := context.Background()
+	if m.actor != "" {
+		ctx = statetrooper.WithActor(ctx, statetrooper.Actor{ID: m.actor})
+	}
+
+	return ctx
+}
+
+// refresh reloads the entity IDs and the targets of the selected entity
+func (m *Model[K, T]) refresh() {
+	m.ids = m.manager.IDs()
+	sort.Slice(m.ids, func(i, j int) bool {
+		return fmt.Sprint(m.ids[i]) < fmt.Sprint(m.ids[j])
+	})
+	m.cursor = clamp(m.cursor, len(m.ids))
+
+	if m.fsm == nil {
+		return
+	}
+
+	m.targets = m.fsm.Rules()[m.fsm.CurrentState()]
+	sort.Slice(m.targets, func(i, j int) bool {
+		return fmt.Sprint(m.targets[i]) < fmt.Sprint(m.targets[j])
+	})
+	m.target = clamp(m.target, len(m.targets))
+}
+
+// View implements tea.Model
+func (m *Model[K, T]) View() string {
+	var b strings.Builder
+
+	switch m.screen {
+	case entitiesScreen:
+		m.viewEntities(&b)
+	default:
+		m.viewEntity(&b)
+	}
+
+	if m.status != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.status)
+	}
+
+	return b.String()
+}
+
+func (m *Model[K, T]) viewEntities(b *strings.Builder) {
+	counts := m.manager.Counts()
+	states := make([]string, 0, len(counts))
+	for state, n := range counts {
+		states = append(states, fmt.Sprintf("%s: %d", statetrooper.DisplayName(state, ""), n))
+	}
+	sort.Strings(states)
+
+	fmt.Fprintf(b, "%d entities  %s\n\n", len(m.ids), strings.Join(states, "  "))
+
+	for i, id := range m.ids {
+		state := "?"
+		if fsm, ok := m.manager.Get(id); ok {
+			state = statetrooper.DisplayName(fsm.CurrentState(), "")
+		}
+		fmt.Fprintf(b, "%s %v  %s\n", cursor(i == m.cursor), id, state)
+	}
+
+	b.WriteString("\nenter: inspect  r: refresh  q: quit\n")
+}
+
+func (m *Model[K, T]) viewEntity(b *strings.Builder) {
+	fmt.Fprintf(b, "%v in %s\n\n", m.ids[m.cursor], statetrooper.DisplayName(m.fsm.CurrentState(), ""))
+
+	history := m.fsm.Transitions()
+	if len(history) > historyRows {
+		history = history[len(history)-historyRows:]
+	}
+
+	b.WriteString("History\n")
+	if len(history) == 0 {
+		b.WriteString("  no transitions retained\n")
+	}
+	for _, tr := range history {
+		fmt.Fprintf(b, "  %s\n", formatTransition(tr))
+	}
+
+	b.WriteString("\nTransitions\n")
+	if len(m.targets) == 0 {
+		b.WriteString("  none from this state\n")
+	}
+	for i, target := range m.targets {
+		note := "allowed"
+		if ex := m.fsm.ExplainCtx(m.context(), target); !ex.Allowed {
+			note = fmt.Sprint(ex.Err)
+		}
+		fmt.Fprintf(b, "%s %s  (%s)\n", cursor(i == m.target), statetrooper.DisplayName(target, ""), note)
+	}
+
+	if m.screen == confirmScreen {
+		fmt.Fprintf(b, "\nMove to %s? y/n\n", statetrooper.DisplayName(m.targets[m.target], ""))
+		return
+	}
+
+	b.WriteString("\nenter: transition  esc: back  r: refresh  q: quit\n")
+}
+
+// formatTransition renders a history entry on a single line
+func formatTransition[T comparable](tr statetrooper.Transition[T]) string {
+	var at string
+	if tr.Timestamp != nil {
+		at = tr.Timestamp.Format(time.RFC3339)
+	}
+
+	line := fmt.Sprintf("%s  %s -> %s", at, statetrooper.DisplayName(tr.FromState, ""), statetrooper.DisplayName(tr.ToState, ""))
+
+	switch {
+	case tr.Failed:
+		line += "  failed: " + tr.Error
+	case tr.Touch:
+		line += "  touch"
+	case tr.Duplicate:
+		line += "  duplicate"
+	}
+
+	if tr.Actor != "" {
+		line += "  by " + tr.Actor
+	}
+
+	if len(tr.Metadata) > 0 {
+		keys := make([]string, 0, len(tr.Metadata))
+		for k := range tr.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = k + "=" + tr.Metadata[k]
+		}
+		line += "  " + strings.Join(pairs, " ")
+	}
+
+	return line
+}
+
+// cursor returns the marker of the selected row
+func cursor(selected bool) string {
+	if selected {
+		return ">"
+	}
+
+	return " "
+}
+
+// clamp limits i to the indexes of a list of n rows, or 0 if the list is empty
+func clamp(i int, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+
+	return i
+}
@@ -0,0 +1,127 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hishamk/statetrooper"
+)
+
+func newOrderManager(t *testing.T) *statetrooper.Manager[string, string] {
+	manager := statetrooper.NewManager[string, string]()
+
+	for _, id := range []string{"order-2", "order-1"} {
+		fsm := statetrooper.NewFSM[string]("created", 10)
+		fsm.AddRule("created", "picked", "canceled")
+		fsm.AddRule("picked", "packed")
+		if err := manager.Add(id, fsm); err != nil {
+			t.Fatalf("Add() returned an error: %v", err)
+		}
+	}
+
+	return manager
+}
+
+// press sends the keys to model in order
+func press(model *Model[string, string], keys ...string) {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		model.Update(msg)
+	}
+}
+
+func Test_inspectorEntities(t *testing.T) {
+	model := New(newOrderManager(t), "")
+
+	view := model.View()
+	for _, want := range []string{"2 entities", "created: 2", "> order-1  created", "  order-2  created"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q:\n%s", want, view)
+		}
+	}
+
+	press(model, "down", "down")
+	if !strings.Contains(model.View(), "> order-2") {
+		t.Errorf("The cursor did not stop at the last entity:\n%s", model.View())
+	}
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Errorf("Update() did not quit on q")
+	}
+}
+
+func Test_inspectorTransition(t *testing.T) {
+	manager := newOrderManager(t)
+	model := New(manager, "alice")
+
+	press(model, "enter")
+	view := model.View()
+	for _, want := range []string{"order-1 in created", "no transitions retained", "> canceled  (allowed)", "  picked  (allowed)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q:\n%s", want, view)
+		}
+	}
+
+	// Declining the confirmation leaves the entity alone
+	press(model, "down", "enter", "n")
+	fsm, _ := manager.Get("order-1")
+	if fsm.CurrentState() != "created" {
+		t.Fatalf("Entity moved to %v without confirmation", fsm.CurrentState())
+	}
+
+	press(model, "enter", "y")
+	if fsm.CurrentState() != "picked" {
+		t.Fatalf("Entity is in %v, expected picked", fsm.CurrentState())
+	}
+
+	tr := fsm.Transitions()[0]
+	if tr.Actor != "alice" || tr.Metadata[SourceMetadataKey] != "inspector" {
+		t.Errorf("Transition has actor %q and metadata %v, expected alice and source=inspector", tr.Actor, tr.Metadata)
+	}
+
+	view = model.View()
+	for _, want := range []string{"order-1 in picked", "created -> picked  by alice  source=inspector", "> packed", "moved to picked"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q:\n%s", want, view)
+		}
+	}
+
+	press(model, "esc")
+	if view := model.View(); !strings.Contains(view, "created: 1") || !strings.Contains(view, "picked: 1") {
+		t.Errorf("Entity list was not refreshed:\n%s", view)
+	}
+}
+
+func Test_inspectorRejectedTransition(t *testing.T) {
+	manager := newOrderManager(t)
+	fsm, _ := manager.Get("order-1")
+	fsm.AddGuard("created", "picked", func(ctx context.Context, tr statetrooper.Transition[string]) error {
+		return errors.New("not in stock")
+	})
+
+	model := New(manager, "")
+	press(model, "enter", "down", "enter", "y")
+
+	if fsm.CurrentState() != "created" {
+		t.Fatalf("Entity moved to %v despite its guard", fsm.CurrentState())
+	}
+
+	if view := model.View(); !strings.Contains(view, "transition to picked failed") {
+		t.Errorf("View() does not report the rejection:\n%s", view)
+	}
+}
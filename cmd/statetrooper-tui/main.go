@@ -0,0 +1,117 @@
+// Command statetrooper-tui is a terminal inspector for the entities of a Manager export. It lists the
+// entities and their states, shows each entity's history and lets an operator trigger transitions
+//
+// Usage:
+//
+//	statetrooper-tui -in entities.ndjson -actor alice
+//
+// The input is the NDJSON written by Manager.Export, with the rulesets included through the
+// IncludeRuleset marshal option. Entities exported without their rules can be browsed but not moved
+// Transitions are applied to the loaded copies and recorded with the source=inspector metadata
+//
+// The command is a module of its own, so the bubbletea dependency is not added to statetrooper itself
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hishamk/statetrooper"
+	"github.com/hishamk/statetrooper/cmd/statetrooper-tui/inspector"
+)
+
+// ErrNoEntities is returned when the input contains no entities that could be loaded
+var ErrNoEntities = errors.New("no entities loaded")
+
+// entity is the part of an exported entity needed to recreate its FSM before loading it
+type entity struct {
+	ID  string `json:"id"`
+	FSM struct {
+		CurrentState string                            `json:"current_state"`
+		Rules        []statetrooper.RuleExport[string] `json:"ruleset"`
+	} `json:"fsm"`
+}
+
+func main() {
+	in := flag.String("in", "", "path of the NDJSON written by Manager.Export")
+	actor := flag.String("actor", "", "actor ID recorded on transitions triggered from the inspector")
+	history := flag.Int("history", 100, "maximum number of transitions kept per entity")
+	flag.Parse()
+
+	if err := run(*in, *actor, *history); err != nil {
+		fmt.Fprintln(os.Stderr, "statetrooper-tui:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, actor string, history int) error {
+	if in == "" {
+		return errors.New("-in is required")
+	}
+
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+
+	m, err := load(data, history)
+	if err != nil {
+		return err
+	}
+
+	return inspector.Run(m, actor)
+}
+
+// load recovers a Manager from an export, creating each entity's FSM with the rules exported with it
+func load(data []byte, history int) (*statetrooper.Manager[string, string], error) {
+	rules := make(map[string]statetrooper.RuleSet[string])
+	initial := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var e entity
+		if err := json.Unmarshal(line, &e); err != nil {
+			// Recover reports the entities it cannot load
+			continue
+		}
+
+		rs := make(statetrooper.RuleSet[string], len(e.FSM.Rules))
+		for _, rule := range e.FSM.Rules {
+			rs[rule.From] = append(rs[rule.From], rule.To...)
+		}
+		rules[e.ID] = rs
+		initial[e.ID] = e.FSM.CurrentState
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	m := statetrooper.NewManager[string, string]()
+	report, err := m.Recover(bytes.NewReader(data), nil, func(id string) *statetrooper.FSM[string] {
+		return statetrooper.NewFSMWithRuleSet(rules[id], initial[id], history)
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, err := range report.Failed {
+		fmt.Fprintf(os.Stderr, "statetrooper-tui: %s not loaded: %v\n", id, err)
+	}
+
+	if len(report.Recovered) == 0 {
+		return nil, ErrNoEntities
+	}
+
+	return m, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hishamk/statetrooper"
+)
+
+func Test_load(t *testing.T) {
+	manager := statetrooper.NewManager[string, string]()
+	for _, id := range []string{"order-1", "order-2"} {
+		fsm := statetrooper.NewFSM[string]("created", 10)
+		fsm.AddRule("created", "picked")
+		fsm.AddRule("picked", "packed")
+		fsm.SetMarshalOptions(statetrooper.MarshalOptions{IncludeRuleset: true})
+		if err := manager.Add(id, fsm); err != nil {
+			t.Fatalf("Add() returned an error: %v", err)
+		}
+	}
+
+	fsm, _ := manager.Get("order-2")
+	if _, err := fsm.Transition("picked", nil); err != nil {
+		t.Fatalf("Transition() returned an error: %v", err)
+	}
+
+	var export bytes.Buffer
+	if err := manager.Export(&export); err != nil {
+		t.Fatalf("Export() returned an error: %v", err)
+	}
+
+	loaded, err := load(export.Bytes(), 10)
+	if err != nil {
+		t.Fatalf("load() returned an error: %v", err)
+	}
+
+	fsm, ok := loaded.Get("order-2")
+	if !ok {
+		t.Fatalf("load() did not load order-2")
+	}
+
+	if fsm.CurrentState() != "picked" || len(fsm.Transitions()) != 1 {
+		t.Errorf("order-2 is in %v with %d transitions, expected picked with 1", fsm.CurrentState(), len(fsm.Transitions()))
+	}
+
+	if !fsm.CanTransition("packed") {
+		t.Errorf("order-2 was loaded without its rules")
+	}
+}
+
+func Test_loadEmpty(t *testing.T) {
+	if _, err := load([]byte("\n"), 10); !errors.Is(err, ErrNoEntities) {
+		t.Errorf("load() returned %v, expected ErrNoEntities", err)
+	}
+}
@@ -0,0 +1,78 @@
+package statetrooper
+
+// CompactHistory collapses repeated loops between two states, such as A -> B -> A -> B -> A, into single entries
+// recording the number of cycles and the time span, so retry-heavy workflows keep a short but auditable history
+// The metadata of the first transition of a loop is kept
+func (fsm *FSM[T]) CompactHistory() {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.transitions = compactLoops(fsm.transitions)
+}
+
+// SetHistoryCompaction sets whether the history is compacted automatically as transitions are recorded
+func (fsm *FSM[T]) SetHistoryCompaction(auto bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.compactHistory = auto
+	if auto {
+		fsm.transitions = compactLoops(fsm.transitions)
+	}
+}
+
+// compactLoops returns history with runs of at least two A -> B -> A loops collapsed into one entry each
+// Loops directly following a compacted entry for the same states are merged into it
+func compactLoops[T comparable](history []Transition[T]) []Transition[T] {
+	out := make([]Transition[T], 0, len(history))
+
+	for i := 0; i < len(history); {
+		tr := history[i]
+		if tr.Cycles > 0 || !isLoop(history, i) {
+			out = append(out, tr)
+			i++
+			continue
+		}
+
+		// Count the consecutive repetitions of the loop starting at i
+		cycles := 1
+		for j := i + 2; isLoop(history, j) && sameEdge(history[j], tr); j += 2 {
+			cycles++
+		}
+		end := history[i+2*cycles-1]
+
+		if n := len(out); n > 0 && out[n-1].Cycles > 0 && sameEdge(out[n-1], tr) {
+			out[n-1].Cycles += cycles
+			out[n-1].Until = end.Timestamp
+		} else if cycles >= 2 {
+			tr.Cycles = cycles
+			tr.Until = end.Timestamp
+			out = append(out, tr)
+		} else {
+			out = append(out, tr)
+			i++
+			continue
+		}
+
+		i += 2 * cycles
+	}
+
+	return out
+}
+
+// isLoop reports whether history[i] and history[i+1] form an A -> B -> A loop of ordinary transitions
+func isLoop[T comparable](history []Transition[T], i int) bool {
+	if i+1 >= len(history) {
+		return false
+	}
+
+	a, b := history[i], history[i+1]
+
+	return a.FromState != a.ToState && a.Cycles == 0 && b.Cycles == 0 && !a.Duplicate && !b.Duplicate && !a.Failed && !b.Failed &&
+		b.FromState == a.ToState && b.ToState == a.FromState
+}
+
+// sameEdge reports whether two transitions go between the same states in the same direction
+func sameEdge[T comparable](a, b Transition[T]) bool {
+	return a.FromState == b.FromState && a.ToState == b.ToState
+}
@@ -0,0 +1,64 @@
+package statetrooper
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_compactHistory(t *testing.T) {
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	fsm := NewFSM[string]("created", 100)
+	fsm.SetClock(func() time.Time { return now })
+	fsm.AddRule("created", "pending")
+	fsm.AddRule("pending", "failed", "done")
+	fsm.AddRule("failed", "pending")
+
+	step := func(state string) {
+		now = now.Add(time.Minute)
+		if _, err := fsm.Transition(state, nil); err != nil {
+			t.Fatalf("Transition() returned an error: %v", err)
+		}
+	}
+
+	step("pending")
+	for i := 0; i < 3; i++ {
+		step("failed")
+		step("pending")
+	}
+	step("done")
+
+	fsm.CompactHistory()
+
+	history := fsm.Transitions()
+	if len(history) != 3 {
+		t.Fatalf("Compacted history has %d entries, expected 3: %v", len(history), history)
+	}
+
+	loop := history[1]
+	if loop.FromState != "pending" || loop.ToState != "failed" || loop.Cycles != 3 ||
+		!loop.Timestamp.Equal(start.Add(2*time.Minute)) || !loop.Until.Equal(start.Add(7*time.Minute)) {
+		t.Errorf("Compacted loop is %+v", loop)
+	}
+
+	if state, _ := fsm.ReplayTo(2); state != "pending" {
+		t.Errorf("ReplayTo(2) returned %v, expected pending after the loop", state)
+	}
+}
+
+func Test_autoCompactHistory(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetHistoryCompaction(true)
+
+	pingPong(fsm, 2)
+	if history := fsm.Transitions(); len(history) != 2 {
+		t.Errorf("A single loop was compacted: %v", history)
+	}
+
+	pingPong(fsm, 7)
+	history := fsm.Transitions()
+	if len(history) != 2 || history[0].Cycles != 4 || history[1].ToState != CustomStateEnumB {
+		t.Errorf("Automatically compacted history is %v, expected 4 cycles followed by A -> B", history)
+	}
+}
@@ -0,0 +1,149 @@
+package statetrooper
+
+import "fmt"
+
+// HistoryMode determines which substate is entered when a composite state is re-entered
+type HistoryMode int
+
+const (
+	// NoHistory always enters the composite state's initial substate
+	NoHistory HistoryMode = iota
+	// ShallowHistory resumes the substate that was last active directly within the composite state
+	// Nested composite substates are entered according to their own history mode
+	ShallowHistory
+	// DeepHistory resumes the innermost substate that was last active anywhere within the composite state
+	DeepHistory
+)
+
+// composite describes a composite state
+type composite[T comparable] struct {
+	initial T
+	history HistoryMode
+}
+
+// AddCompositeState declares parent as a composite state containing the given substates
+// The FSM is never in a composite state itself: a transition targeting parent enters initial,
+// or the remembered substate according to history. Rules from parent apply to all of its substates,
+// so for example a single rule from parent to canceled allows canceling from any substate
+// Substates may themselves be composite states, but a state can only have one parent
+func (fsm *FSM[T]) AddCompositeState(parent T, initial T, history HistoryMode, substates ...T) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if _, ok := fsm.composites[parent]; ok {
+		return fmt.Errorf("%w: %v is already a composite state", ErrInvalidComposite, parent)
+	}
+
+	if !contains(substates, initial) {
+		return fmt.Errorf("%w: initial state %v is not a substate of %v", ErrInvalidComposite, initial, parent)
+	}
+
+	for _, state := range substates {
+		if p, ok := fsm.parents[state]; ok {
+			return fmt.Errorf("%w: %v is already a substate of %v", ErrInvalidComposite, state, p)
+		}
+
+		// Prevent cycles such as a composite state containing one of its ancestors
+		for ancestor, ok := parent, true; ok; ancestor, ok = fsm.parents[ancestor] {
+			if ancestor == state {
+				return fmt.Errorf("%w: %v cannot contain itself", ErrInvalidComposite, state)
+			}
+		}
+	}
+
+	if fsm.composites == nil {
+		fsm.composites = make(map[T]composite[T])
+		fsm.parents = make(map[T]T)
+		fsm.lastActive = make(map[T]T)
+	}
+
+	fsm.composites[parent] = composite[T]{initial: initial, history: history}
+	for _, state := range substates {
+		fsm.parents[state] = parent
+	}
+
+	return nil
+}
+
+// IsIn reports whether the FSM is in state, either directly or within it as a composite state
+func (fsm *FSM[T]) IsIn(state T) bool {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.isWithin(fsm.currentState, state)
+}
+
+// resolveTarget returns the non-composite state entered when targeting state
+func (fsm *FSM[T]) resolveTarget(state T) T {
+	deep := false
+
+	for {
+		c, ok := fsm.composites[state]
+		if !ok {
+			return state
+		}
+
+		deep = deep || c.history == DeepHistory
+
+		next := c.initial
+		if deep || c.history == ShallowHistory {
+			if last, ok := fsm.lastActive[state]; ok {
+				next = last
+			}
+		}
+
+		state = next
+	}
+}
+
+// rememberActive records state as the last active substate of each of its ancestors
+func (fsm *FSM[T]) rememberActive(state T) {
+	for parent, ok := fsm.parents[state]; ok; parent, ok = fsm.parents[state] {
+		fsm.lastActive[parent] = state
+		state = parent
+	}
+}
+
+// ruleEdges returns the edges whose guards, cooldowns and budgets apply to a transition from fromState to
+// toState: those from fromState or any of its ancestors, whose rules apply to it, to toState or any of the
+// ancestors the transition enters, so that configuration on a rule targeting a composite state applies
+// to the substate it resolves to
+func (fsm *FSM[T]) ruleEdges(fromState T, toState T) []edge[T] {
+	if len(fsm.parents) == 0 {
+		return []edge[T]{{from: fromState, to: toState}}
+	}
+
+	var edges []edge[T]
+	for to, ok := toState, true; ok; to, ok = fsm.parents[to] {
+		if fsm.isWithin(fromState, to) {
+			break
+		}
+
+		for from, ok := fromState, true; ok; from, ok = fsm.parents[from] {
+			edges = append(edges, edge[T]{from: from, to: to})
+		}
+	}
+
+	return edges
+}
+
+// configuredEdges returns the ruleEdges of a transition, or none if the per-edge setting they are looked
+// up in has n entries, which spares transitions the allocation when the setting is unused
+func (fsm *FSM[T]) configuredEdges(n int, fromState T, toState T) []edge[T] {
+	if n == 0 {
+		return nil
+	}
+
+	return fsm.ruleEdges(fromState, toState)
+}
+
+// isWithin reports whether state is ancestor or one of its substates, at any depth
+func (fsm *FSM[T]) isWithin(state T, ancestor T) bool {
+	for s, ok := state, true; ok; s, ok = fsm.parents[s] {
+		if s == ancestor {
+			return true
+		}
+	}
+
+	return false
+}
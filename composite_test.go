@@ -0,0 +1,138 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_compositeHistory(t *testing.T) {
+	tests := []struct {
+		history  HistoryMode
+		expected string
+	}{
+		{NoHistory, "picking"},
+		{ShallowHistory, "boxing"}, // packing is resumed, but enters its own initial substate
+		{DeepHistory, "sealing"},
+	}
+
+	for _, test := range tests {
+		fsm := NewFSM[string]("picking", 10)
+		fsm.AddCompositeState("fulfillment", "picking", test.history, "picking", "packing")
+		fsm.AddCompositeState("packing", "boxing", NoHistory, "boxing", "sealing")
+		fsm.AddRule("picking", "packing")
+		fsm.AddRule("boxing", "sealing")
+		fsm.AddRule("fulfillment", "on_hold")
+		fsm.AddRule("on_hold", "fulfillment")
+
+		// Entering packing enters its initial substate
+		if state, err := fsm.Transition("packing", nil); err != nil || state != "boxing" {
+			t.Fatalf("Transition(packing) returned (%v, %v), expected (boxing, nil)", state, err)
+		}
+
+		fsm.Transition("sealing", nil)
+
+		if !fsm.IsIn("fulfillment") || !fsm.IsIn("packing") || fsm.IsIn("picking") {
+			t.Errorf("IsIn reports incorrect ancestors for %v", fsm.CurrentState())
+		}
+
+		// The rule from fulfillment applies to its nested substates
+		if _, err := fsm.Transition("on_hold", nil); err != nil {
+			t.Fatalf("Transition(on_hold) from a nested substate returned an error: %v", err)
+		}
+
+		state, err := fsm.Transition("fulfillment", nil)
+		if err != nil || state != test.expected {
+			t.Errorf("History mode %d: re-entering fulfillment returned (%v, %v), expected (%v, nil)", test.history, state, err, test.expected)
+		}
+	}
+}
+
+func Test_compositeValidation(t *testing.T) {
+	fsm := NewFSM[string]("picking", 10)
+
+	if err := fsm.AddCompositeState("fulfillment", "shipping", NoHistory, "picking", "packing"); !errors.Is(err, ErrInvalidComposite) {
+		t.Errorf("Initial state outside the substates returned %v, expected ErrInvalidComposite", err)
+	}
+
+	fsm.AddCompositeState("fulfillment", "picking", NoHistory, "picking", "packing")
+
+	if err := fsm.AddCompositeState("warehouse", "picking", NoHistory, "picking"); !errors.Is(err, ErrInvalidComposite) {
+		t.Errorf("Substate with two parents returned %v, expected ErrInvalidComposite", err)
+	}
+
+	if err := fsm.AddCompositeState("packing", "fulfillment", NoHistory, "fulfillment"); !errors.Is(err, ErrInvalidComposite) {
+		t.Errorf("Cyclic composite returned %v, expected ErrInvalidComposite", err)
+	}
+}
+
+func newFulfillmentFSM() *FSM[string] {
+	fsm := NewFSM[string]("on_hold", 10)
+	fsm.AddCompositeState("fulfillment", "picking", NoHistory, "picking", "packing")
+	fsm.AddRule("on_hold", "fulfillment")
+	fsm.AddRule("picking", "packing")
+	fsm.AddRule("fulfillment", "on_hold")
+
+	return fsm
+}
+
+func Test_compositeGuards(t *testing.T) {
+	errHeld := errors.New("held")
+	reject := func(ctx context.Context, tr Transition[string]) error { return errHeld }
+
+	// A guard on a rule targeting a composite state applies to the substate it resolves to
+	fsm := newFulfillmentFSM()
+	fsm.AddGuard("on_hold", "fulfillment", reject)
+	if _, err := fsm.Transition("fulfillment", nil); !errors.Is(err, errHeld) {
+		t.Errorf("Guard on the rule into fulfillment returned %v, expected it to reject", err)
+	}
+
+	if ex := fsm.Explain("fulfillment"); ex.Reason != RejectGuard {
+		t.Errorf("Explain() returned reason %d, expected RejectGuard", ex.Reason)
+	}
+
+	// A guard on a rule from a composite state applies to its substates
+	fsm = newFulfillmentFSM()
+	fsm.AddGuard("fulfillment", "on_hold", reject)
+	fsm.Transition("fulfillment", nil)
+	if _, err := fsm.Transition("on_hold", nil); !errors.Is(err, errHeld) {
+		t.Errorf("Guard on the rule from fulfillment returned %v, expected it to reject", err)
+	}
+
+	// Moving within a composite state does not enter it, so guards on rules into it do not apply
+	fsm = newFulfillmentFSM()
+	fsm.Transition("fulfillment", nil)
+	fsm.AddGuard("on_hold", "fulfillment", reject)
+	if _, err := fsm.Transition("packing", nil); err != nil {
+		t.Errorf("Transition(packing) within fulfillment returned an error: %v", err)
+	}
+}
+
+func Test_compositeCooldownAndBudget(t *testing.T) {
+	fsm := newFulfillmentFSM()
+	fsm.SetEdgeCooldown("fulfillment", "on_hold", time.Hour)
+	fsm.SetEdgeMaxTransitions("on_hold", "fulfillment", 1)
+
+	fsm.Transition("fulfillment", nil)
+	if got := fsm.EdgeCount("on_hold", "fulfillment"); got != 1 {
+		t.Errorf("EdgeCount(on_hold, fulfillment) returned %d, expected 1", got)
+	}
+
+	if _, err := fsm.Transition("on_hold", nil); err != nil {
+		t.Fatalf("Transition(on_hold) returned an error: %v", err)
+	}
+
+	if _, err := fsm.Transition("fulfillment", nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Re-entering fulfillment returned %v, expected ErrBudgetExceeded", err)
+	}
+
+	fsm = newFulfillmentFSM()
+	fsm.SetEdgeCooldown("fulfillment", "on_hold", time.Hour)
+	fsm.Transition("fulfillment", nil)
+	fsm.Transition("on_hold", nil)
+	fsm.Transition("fulfillment", nil)
+	if _, err := fsm.Transition("on_hold", nil); !errors.Is(err, ErrTooSoon) {
+		t.Errorf("Leaving fulfillment twice within the cooldown returned %v, expected ErrTooSoon", err)
+	}
+}
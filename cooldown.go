@@ -0,0 +1,79 @@
+package statetrooper
+
+import "time"
+
+// SetCooldown sets the minimum interval between any two transitions of the FSM
+// A zero duration disables the cooldown
+func (fsm *FSM[T]) SetCooldown(d time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.cooldown = d
+}
+
+// SetEdgeCooldown sets the minimum interval between two transitions from fromState to toState
+// A zero duration disables the cooldown for that transition
+func (fsm *FSM[T]) SetEdgeCooldown(fromState T, toState T, d time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	e := edge[T]{from: fromState, to: toState}
+
+	if d <= 0 {
+		delete(fsm.edgeCooldowns, e)
+		return
+	}
+
+	if fsm.edgeCooldowns == nil {
+		fsm.edgeCooldowns = make(map[edge[T]]time.Duration)
+	}
+
+	fsm.edgeCooldowns[e] = d
+}
+
+// checkCooldown returns a CooldownError if the FSM-wide or per-edge cooldown has not elapsed at now
+func (fsm *FSM[T]) checkCooldown(fromState *T, toState *T, now time.Time) error {
+	var wait time.Duration
+
+	if fsm.cooldown > 0 && !fsm.lastTransitionAt.IsZero() {
+		wait = fsm.lastTransitionAt.Add(fsm.cooldown).Sub(now)
+	}
+
+	for _, e := range fsm.configuredEdges(len(fsm.edgeCooldowns), *fromState, *toState) {
+		if d, ok := fsm.edgeCooldowns[e]; ok {
+			if last, ok := fsm.edgeLastAt[e]; ok {
+				if w := last.Add(d).Sub(now); w > wait {
+					wait = w
+				}
+			}
+		}
+	}
+
+	if wait > 0 {
+		return CooldownError[T]{
+			FromState:  *fromState,
+			ToState:    *toState,
+			RetryAfter: wait,
+		}
+	}
+
+	return nil
+}
+
+// markCooldown records the time of the given transition for cooldown tracking
+// The edges of rules from or to composite states that apply to it are marked too, see ruleEdges
+func (fsm *FSM[T]) markCooldown(tr *Transition[T]) {
+	fsm.lastTransitionAt = *tr.Timestamp
+
+	for _, e := range fsm.configuredEdges(len(fsm.edgeCooldowns), tr.FromState, tr.ToState) {
+		if _, ok := fsm.edgeCooldowns[e]; !ok {
+			continue
+		}
+
+		if fsm.edgeLastAt == nil {
+			fsm.edgeLastAt = make(map[edge[T]]time.Time)
+		}
+
+		fsm.edgeLastAt[e] = *tr.Timestamp
+	}
+}
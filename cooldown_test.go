@@ -0,0 +1,63 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_cooldown(t *testing.T) {
+	now := time.Date(2023, 6, 18, 12, 0, 0, 0, time.UTC)
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.now = func() time.Time { return now }
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.SetCooldown(time.Second)
+	fsm.SetEdgeCooldown(CustomStateEnumB, CustomStateEnumA, time.Minute)
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Fatalf("First transition returned an error: %v", err)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+
+	_, err := fsm.Transition(CustomStateEnumA, nil)
+
+	var cErr CooldownError[CustomStateEnum]
+	if !errors.Is(err, ErrTooSoon) || !errors.As(err, &cErr) {
+		t.Fatalf("Transition within cooldown returned %v, expected a CooldownError", err)
+	}
+
+	if cErr.RetryAfter != 500*time.Millisecond {
+		t.Errorf("CooldownError has incorrect RetryAfter. Got %v, expected %v", cErr.RetryAfter, 500*time.Millisecond)
+	}
+
+	now = now.Add(time.Second)
+
+	if _, err := fsm.Transition(CustomStateEnumA, nil); err != nil {
+		t.Fatalf("Transition after cooldown returned an error: %v", err)
+	}
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); !errors.Is(err, ErrTooSoon) {
+		t.Errorf("Transition within FSM cooldown returned %v, expected ErrTooSoon", err)
+	}
+
+	now = now.Add(2 * time.Second)
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Fatalf("Transition after FSM cooldown returned an error: %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+
+	// The B -> A edge cooldown is still active even though the FSM cooldown has elapsed
+	_, err = fsm.Transition(CustomStateEnumA, nil)
+	if !errors.As(err, &cErr) {
+		t.Fatalf("Transition within edge cooldown returned %v, expected a CooldownError", err)
+	}
+
+	if expected := time.Minute - 2*time.Second - 2*time.Second; cErr.RetryAfter != expected {
+		t.Errorf("CooldownError has incorrect RetryAfter. Got %v, expected %v", cErr.RetryAfter, expected)
+	}
+}
@@ -0,0 +1,104 @@
+package statetrooper
+
+import (
+	"sync"
+	"time"
+)
+
+// DeadLetter describes a transition attempt that was permanently rejected
+type DeadLetter[T comparable] struct {
+	FromState T
+	ToState   T
+	Metadata  map[string]string
+	Err       error
+	Attempts  int
+	Timestamp time.Time
+}
+
+// DeadLetterSink receives permanently rejected transition attempts
+// Send is called outside the FSM's lock and may call back into the FSM
+type DeadLetterSink[T comparable] interface {
+	Send(dl DeadLetter[T])
+}
+
+// SetDeadLetterSink sets the sink that receives rejected transition attempts
+// A nil sink disables dead-lettering
+func (fsm *FSM[T]) SetDeadLetterSink(sink DeadLetterSink[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.deadLetterSink = sink
+}
+
+// Redrive retries the transition captured by a dead letter against the FSM's current state
+func (fsm *FSM[T]) Redrive(dl DeadLetter[T]) (T, error) {
+	return fsm.Transition(dl.ToState, dl.Metadata)
+}
+
+// deadLetter sends a rejected transition attempt to the dead-letter sink, if one is configured
+func (fsm *FSM[T]) deadLetter(fromState T, toState T, metadata map[string]string, err error, attempts int) {
+	fsm.mu.Lock()
+	sink := fsm.deadLetterSink
+	tn := fsm.timeNow()
+	fsm.mu.Unlock()
+
+	if sink == nil {
+		return
+	}
+
+	sink.Send(DeadLetter[T]{
+		FromState: fromState,
+		ToState:   toState,
+		Metadata:  metadata,
+		Err:       err,
+		Attempts:  attempts,
+		Timestamp: tn,
+	})
+}
+
+// DeadLetterQueue is an in-memory DeadLetterSink that keeps dead letters for inspection
+type DeadLetterQueue[T comparable] struct {
+	mu       sync.Mutex
+	letters  []DeadLetter[T]
+	capacity int
+}
+
+// NewDeadLetterQueue creates a DeadLetterQueue holding up to capacity dead letters
+// When full, the oldest dead letter is dropped. Zero capacity means unbounded
+func NewDeadLetterQueue[T comparable](capacity int) *DeadLetterQueue[T] {
+	return &DeadLetterQueue[T]{capacity: capacity}
+}
+
+// Send adds a dead letter to the queue
+func (q *DeadLetterQueue[T]) Send(dl DeadLetter[T]) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.capacity > 0 && len(q.letters) >= q.capacity {
+		q.letters = q.letters[1:]
+	}
+
+	q.letters = append(q.letters, dl)
+}
+
+// Letters returns a copy of the dead letters in the queue, oldest first
+func (q *DeadLetterQueue[T]) Letters() []DeadLetter[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters := make([]DeadLetter[T], len(q.letters))
+	copy(letters, q.letters)
+
+	return letters
+}
+
+// Drain removes and returns all dead letters in the queue, oldest first
+func (q *DeadLetterQueue[T]) Drain() []DeadLetter[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters := q.letters
+	q.letters = nil
+
+	return letters
+}
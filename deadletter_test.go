@@ -0,0 +1,85 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_deadLetters(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	ready := false
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		if !ready {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+
+	dlq := NewDeadLetterQueue[CustomStateEnum](2)
+	fsm.SetDeadLetterSink(dlq)
+
+	fsm.Transition(CustomStateEnumC, nil)
+	fsm.Transition(CustomStateEnumB, map[string]string{"requested_by": "Mahmoud"})
+
+	r := fsm.RetryTransition(CustomStateEnumB, nil, RetryPolicy{InitialBackoff: time.Millisecond, Timeout: 5 * time.Millisecond})
+	<-r.Done()
+
+	letters := dlq.Letters()
+	if len(letters) != 2 {
+		t.Fatalf("DeadLetterQueue holds %d letters, expected 2", len(letters))
+	}
+
+	if letters[0].ToState != CustomStateEnumB || letters[0].Metadata["requested_by"] != "Mahmoud" || letters[0].Attempts != 1 {
+		t.Errorf("Unexpected dead letter for rejected transition: %+v", letters[0])
+	}
+
+	if !errors.Is(letters[1].Err, ErrRetryExpired) || letters[1].Attempts < 2 {
+		t.Errorf("Unexpected dead letter for expired retry: %+v", letters[1])
+	}
+
+	ready = true
+
+	drained := dlq.Drain()
+	if _, err := fsm.Redrive(drained[0]); err != nil {
+		t.Errorf("Redrive(%+v) returned an error: %v", drained[0], err)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumB {
+		t.Errorf("Redrive did not transition to %v", CustomStateEnumB)
+	}
+
+	if len(dlq.Letters()) != 0 {
+		t.Errorf("Drain did not empty the queue")
+	}
+}
+
+func Test_actionErrorsNotDeadLettered(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetEntryAction(CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errors.New("inventory service unavailable")
+	}, ActionPolicy[CustomStateEnum]{})
+
+	dlq := NewDeadLetterQueue[CustomStateEnum](10)
+	fsm.SetDeadLetterSink(dlq)
+
+	state, err := fsm.Transition(CustomStateEnumB, nil)
+	var actionErr ActionError[CustomStateEnum]
+	if state != CustomStateEnumB || !errors.As(err, &actionErr) || !errors.Is(err, ErrActionFailed) {
+		t.Errorf("Transition returned (%v, %v), expected B with an ActionError", state, err)
+	}
+
+	fsm.Transition(CustomStateEnumA, nil)
+	r := fsm.RetryTransition(CustomStateEnumB, nil, RetryPolicy{InitialBackoff: time.Millisecond, Timeout: time.Second})
+	<-r.Done()
+	if state, err := r.Result(); state != CustomStateEnumB || !errors.Is(err, ErrActionFailed) || r.Attempts() != 1 {
+		t.Errorf("Retry finished with (%v, %v) after %d attempts, expected B with the action error after 1", state, err, r.Attempts())
+	}
+
+	if letters := dlq.Letters(); len(letters) != 0 {
+		t.Errorf("DeadLetterQueue holds %v, expected committed transitions with failed actions to be left out", letters)
+	}
+}
@@ -0,0 +1,44 @@
+package statetrooper
+
+import "time"
+
+// SetDebounce makes a repeated request for the current state within window of entering it
+// succeed as a no-op instead of returning an error, matching at-least-once delivery semantics
+// If recordDuplicates is true, each debounced request is recorded in the history with Duplicate set
+// A zero window disables debouncing
+func (fsm *FSM[T]) SetDebounce(window time.Duration, recordDuplicates bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.debounceWindow = window
+	fsm.recordDuplicates = recordDuplicates
+}
+
+// debounced reports whether a request for targetState at tn is a duplicate that should be treated as a no-op
+// If duplicates are recorded, it also returns the entry to record once the request is committed
+func (fsm *FSM[T]) debounced(targetState *T, metadata map[string]string, tn time.Time) (*Transition[T], bool) {
+	if !fsm.duplicate(targetState, tn) {
+		return nil, false
+	}
+
+	if !fsm.recordDuplicates {
+		return nil, true
+	}
+
+	return &Transition[T]{
+		FromState: fsm.currentState,
+		ToState:   fsm.currentState,
+		Timestamp: &tn,
+		Metadata:  metadata,
+		Duplicate: true,
+	}, true
+}
+
+// duplicate reports whether a request for targetState at tn falls within the debounce window
+func (fsm *FSM[T]) duplicate(targetState *T, tn time.Time) bool {
+	if fsm.debounceWindow <= 0 || *targetState != fsm.currentState || fsm.lastTransitionAt.IsZero() {
+		return false
+	}
+
+	return tn.Sub(fsm.lastTransitionAt) <= fsm.debounceWindow
+}
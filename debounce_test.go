@@ -0,0 +1,44 @@
+package statetrooper
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_debounce(t *testing.T) {
+	now := time.Date(2023, 6, 18, 12, 0, 0, 0, time.UTC)
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.now = func() time.Time { return now }
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.SetDebounce(time.Minute, true)
+
+	// No transition has happened yet, so there is nothing to debounce
+	if _, err := fsm.Transition(CustomStateEnumA, nil); err == nil {
+		t.Errorf("Transition to the initial state should not be debounced")
+	}
+
+	fsm.Transition(CustomStateEnumB, nil)
+
+	now = now.Add(30 * time.Second)
+
+	newState, err := fsm.Transition(CustomStateEnumB, map[string]string{"delivery": "2"})
+	if err != nil || newState != CustomStateEnumB {
+		t.Errorf("Duplicate Transition(%v) returned (%v, %v), expected (%v, nil)", CustomStateEnumB, newState, err, CustomStateEnumB)
+	}
+
+	transitions := fsm.Transitions()
+	if len(transitions) != 2 || !transitions[1].Duplicate || transitions[1].FromState != CustomStateEnumB {
+		t.Errorf("Duplicate transition was not recorded as expected: %v", transitions)
+	}
+
+	if fsm.TransitionCount() != 1 {
+		t.Errorf("Duplicate transition should not be counted. TransitionCount() = %d", fsm.TransitionCount())
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err == nil {
+		t.Errorf("Transition(%v) outside the debounce window should return an error", CustomStateEnumB)
+	}
+}
@@ -0,0 +1,104 @@
+package statetrooper
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// displayNames maps each state type to its display names by locale
+var displayNames struct {
+	sync.RWMutex
+	types map[reflect.Type]map[string]map[any]string
+}
+
+// RegisterDisplayNames registers user-facing names for states of type T in locale
+// The empty locale is the default, used by String, diagrams, errors and health reports,
+// so raw state values don't leak into user-facing output. Names are merged into those
+// already registered for the locale
+func RegisterDisplayNames[T comparable](locale string, names map[T]string) {
+	displayNames.Lock()
+	defer displayNames.Unlock()
+
+	if displayNames.types == nil {
+		displayNames.types = make(map[reflect.Type]map[string]map[any]string)
+	}
+
+	t := stateType[T]()
+	if displayNames.types[t] == nil {
+		displayNames.types[t] = make(map[string]map[any]string)
+	}
+
+	locales := displayNames.types[t]
+	if locales[locale] == nil {
+		locales[locale] = make(map[any]string, len(names))
+	}
+
+	for state, name := range names {
+		locales[locale][state] = name
+	}
+}
+
+// DisplayName returns the display name of state in locale, falling back to the default locale
+// and then to the string form of state
+func DisplayName[T comparable](state T, locale string) string {
+	if name, ok := lookupDisplayName(state, locale); ok {
+		return name
+	}
+
+	return toString(state)
+}
+
+// lookupDisplayName returns the registered display name of state in locale or the default locale
+func lookupDisplayName[T comparable](state T, locale string) (string, bool) {
+	displayNames.RLock()
+	defer displayNames.RUnlock()
+
+	locales := displayNames.types[stateType[T]()]
+	if locales == nil {
+		return "", false
+	}
+
+	if name, ok := locales[locale][state]; ok {
+		return name, true
+	}
+
+	name, ok := locales[""][state]
+
+	return name, ok
+}
+
+// display returns the default display name of state if one is registered, or state itself
+// so that formatting with %v is unchanged for states without display names
+func display[T comparable](state T) any {
+	if name, ok := lookupDisplayName(state, ""); ok {
+		return name
+	}
+
+	return state
+}
+
+// displayAll is like display for a list of states
+func displayAll[T comparable](states []T) []any {
+	displayed := make([]any, len(states))
+	for i, state := range states {
+		displayed[i] = display(state)
+	}
+
+	return displayed
+}
+
+// mermaidLabel returns the Mermaid node for state, labelled with its default display name if one is registered
+func mermaidLabel[T comparable](state T) string {
+	name, ok := lookupDisplayName(state, "")
+	if !ok {
+		return toString(state)
+	}
+
+	return toString(state) + `["` + strings.ReplaceAll(name, `"`, "#quot;") + `"]`
+}
+
+// stateType returns the reflect.Type of T, which also works for interface types
+func stateType[T comparable]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,63 @@
+package statetrooper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// displayState is used only by this test, as display names are registered per state type
+type displayState string
+
+func (s displayState) String() string {
+	return string(s)
+}
+
+func Test_displayNames(t *testing.T) {
+	RegisterDisplayNames("", map[displayState]string{
+		"pending_payment": "Pending payment",
+		"shipped":         "Shipped",
+	})
+	RegisterDisplayNames("de", map[displayState]string{
+		"pending_payment": "Zahlung ausstehend",
+	})
+
+	if name := DisplayName[displayState]("pending_payment", "de"); name != "Zahlung ausstehend" {
+		t.Errorf("DisplayName() returned %q for de", name)
+	}
+
+	if name := DisplayName[displayState]("shipped", "de"); name != "Shipped" {
+		t.Errorf("DisplayName() returned %q, expected the default locale as fallback", name)
+	}
+
+	if name := DisplayName[displayState]("canceled", "de"); name != "canceled" {
+		t.Errorf("DisplayName() returned %q, expected the state itself as fallback", name)
+	}
+
+	// Other state types are unaffected
+	if name := DisplayName[string]("shipped", ""); name != "shipped" {
+		t.Errorf("DisplayName() returned %q for a string state", name)
+	}
+
+	fsm := NewFSM[displayState]("pending_payment", 10)
+	fsm.AddRule("pending_payment", "shipped")
+
+	var trErr TransitionError[displayState]
+	_, err := fsm.Transition("canceled", nil)
+	if !errors.As(err, &trErr) || err.Error() != "invalid state transition from Pending payment to canceled, allowed: [Shipped]" {
+		t.Errorf("Transition() returned %q, expected display names in the error", err)
+	}
+
+	if s := fsm.String(); !strings.Contains(s, "Current State: Pending payment") {
+		t.Errorf("String() returned %q, expected the display name of the current state", s)
+	}
+
+	diagram, _ := fsm.GenerateMermaidRulesDiagram()
+	if !strings.Contains(diagram, `pending_payment["Pending payment"]`) || !strings.Contains(diagram, "pending_payment --> shipped;") {
+		t.Errorf("GenerateMermaidRulesDiagram() returned %q, expected labelled nodes and raw edges", diagram)
+	}
+
+	if report := fsm.HealthCheck(); report.State != "Pending payment" {
+		t.Errorf("HealthCheck() reported state %q", report.State)
+	}
+}
@@ -0,0 +1,298 @@
+package statetrooper
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// RulesFromDOT builds a ruleset from a Graphviz DOT digraph, with an edge a -> b becoming a rule from a to b
+// Edge chains (a -> b -> c), node groups (a -> {b c}) and subgraphs are supported; attributes, ports
+// and node declarations without edges are ignored
+func RulesFromDOT[T ~string](src string) (map[T][]T, error) {
+	p := &dotParser{tokens: tokenizeDOT(src)}
+	if err := p.parseGraph(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDiagram, err)
+	}
+
+	rules := make(map[T][]T)
+	for _, e := range p.edges {
+		from, to := T(e.from), T(e.to)
+		if !contains(rules[from], to) {
+			rules[from] = append(rules[from], to)
+		}
+	}
+
+	return rules, nil
+}
+
+// dotToken is a lexical token of a DOT graph
+// Identifiers, including quoted strings, have ident set
+type dotToken struct {
+	text  string
+	ident bool
+}
+
+// tokenizeDOT splits a DOT graph into tokens, dropping comments
+func tokenizeDOT(src string) []dotToken {
+	var tokens []dotToken
+	r := []rune(src)
+
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '#' && (i == 0 || r[i-1] == '\n'), c == '/' && i+1 < len(r) && r[i+1] == '/':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(r) && r[i+1] == '*':
+			i += 2
+			for i < len(r) && !(r[i] == '*' && i+1 < len(r) && r[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case c == '"':
+			var b strings.Builder
+			i++
+			for i < len(r) && r[i] != '"' {
+				if r[i] == '\\' && i+1 < len(r) && r[i+1] == '"' {
+					i++
+				}
+				b.WriteRune(r[i])
+				i++
+			}
+			i++
+			tokens = append(tokens, dotToken{text: b.String(), ident: true})
+		case c == '-' && i+1 < len(r) && (r[i+1] == '>' || r[i+1] == '-'):
+			tokens = append(tokens, dotToken{text: string(r[i : i+2])})
+			i += 2
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.' || c == '-':
+			start := i
+			for i < len(r) && (unicode.IsLetter(r[i]) || unicode.IsDigit(r[i]) || r[i] == '_' || r[i] == '.' ||
+				(r[i] == '-' && !(i+1 < len(r) && (r[i+1] == '>' || r[i+1] == '-')))) {
+				i++
+			}
+			tokens = append(tokens, dotToken{text: string(r[start:i]), ident: true})
+		default:
+			tokens = append(tokens, dotToken{text: string(c)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// dotParser parses the subset of the DOT grammar needed to extract edges
+type dotParser struct {
+	tokens []dotToken
+	pos    int
+	edges  []edge[string]
+}
+
+func (p *dotParser) peek() dotToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return dotToken{}
+}
+
+func (p *dotParser) next() dotToken {
+	t := p.peek()
+	p.pos++
+
+	return t
+}
+
+func (p *dotParser) expect(text string) error {
+	if t := p.next(); t.text != text || t.ident {
+		return fmt.Errorf("expected %q, got %q", text, t.text)
+	}
+
+	return nil
+}
+
+// isKeyword reports whether t is the given DOT keyword, which is case-insensitive
+func isKeyword(t dotToken, keyword string) bool {
+	return t.ident && strings.EqualFold(t.text, keyword)
+}
+
+// isDOTKeyword reports whether t is a keyword that can start a statement
+func isDOTKeyword(t dotToken) bool {
+	return isKeyword(t, "graph") || isKeyword(t, "node") || isKeyword(t, "edge") || isKeyword(t, "subgraph")
+}
+
+// parseGraph parses: [strict] digraph [ID] '{' stmt_list '}'
+func (p *dotParser) parseGraph() error {
+	if isKeyword(p.peek(), "strict") {
+		p.next()
+	}
+
+	if t := p.next(); !isKeyword(t, "digraph") {
+		return fmt.Errorf("expected digraph, got %q", t.text)
+	}
+
+	if p.peek().ident {
+		p.next()
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	if err := p.parseStatements(); err != nil {
+		return err
+	}
+
+	if p.pos < len(p.tokens) {
+		return fmt.Errorf("unexpected %q after graph", p.peek().text)
+	}
+
+	return nil
+}
+
+// parseStatements parses statements up to and including the closing brace of the current block
+func (p *dotParser) parseStatements() error {
+	for {
+		t := p.peek()
+		switch {
+		case p.pos >= len(p.tokens):
+			return fmt.Errorf("unexpected end of graph")
+		case t.text == "}" && !t.ident:
+			p.next()
+			return nil
+		case t.text == ";" && !t.ident:
+			p.next()
+		case isKeyword(t, "graph"), isKeyword(t, "node"), isKeyword(t, "edge"):
+			p.next()
+			if err := p.skipAttributes(); err != nil {
+				return err
+			}
+		default:
+			if err := p.parseStatement(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseStatement parses an assignment, a node statement or an edge statement
+func (p *dotParser) parseStatement() error {
+	from, err := p.parseOperand()
+	if err != nil {
+		return err
+	}
+
+	if t := p.peek(); t.text == "=" && !t.ident {
+		p.next()
+		if t := p.next(); !t.ident {
+			return fmt.Errorf("expected a value after =, got %q", t.text)
+		}
+		return nil
+	}
+
+	for {
+		t := p.peek()
+		if t.ident || (t.text != "->" && t.text != "--") {
+			break
+		}
+
+		if t.text == "--" {
+			return fmt.Errorf("undirected edge in digraph")
+		}
+
+		p.next()
+		to, err := p.parseOperand()
+		if err != nil {
+			return err
+		}
+
+		for _, f := range from {
+			for _, s := range to {
+				p.edges = append(p.edges, edge[string]{from: f, to: s})
+			}
+		}
+		from = to
+	}
+
+	return p.skipAttributes()
+}
+
+// parseOperand parses a node ID with an optional port, a node group or a subgraph
+// and returns the nodes it refers to
+func (p *dotParser) parseOperand() ([]string, error) {
+	t := p.peek()
+
+	if isKeyword(t, "subgraph") || (t.text == "{" && !t.ident) {
+		if isKeyword(t, "subgraph") {
+			p.next()
+			if p.peek().ident {
+				p.next()
+			}
+		}
+
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+
+		// Edges within the group are kept; the group refers to every node it mentions
+		start := p.pos
+		if err := p.parseStatements(); err != nil {
+			return nil, err
+		}
+
+		return groupNodes(p.tokens[start:p.pos]), nil
+	}
+
+	if !t.ident {
+		return nil, fmt.Errorf("expected a node, got %q", t.text)
+	}
+	p.next()
+
+	// Ports such as node:port:compass do not affect rules
+	for p.peek().text == ":" && !p.peek().ident {
+		p.next()
+		p.next()
+	}
+
+	return []string{t.text}, nil
+}
+
+// groupNodes returns the distinct node IDs mentioned in the statements of a group
+func groupNodes(tokens []dotToken) []string {
+	var nodes []string
+	depth := 0
+
+	for i, t := range tokens {
+		switch {
+		case !t.ident && t.text == "[":
+			depth++
+		case !t.ident && t.text == "]":
+			depth--
+		case t.ident && depth == 0:
+			isValue := i > 0 && !tokens[i-1].ident && (tokens[i-1].text == "=" || tokens[i-1].text == ":")
+			isAssigned := i+1 < len(tokens) && !tokens[i+1].ident && tokens[i+1].text == "="
+			isName := i > 0 && isKeyword(tokens[i-1], "subgraph")
+			if !isValue && !isAssigned && !isName && !isDOTKeyword(t) && !contains(nodes, t.text) {
+				nodes = append(nodes, t.text)
+			}
+		}
+	}
+
+	return nodes
+}
+
+// skipAttributes skips any attribute lists following a statement
+func (p *dotParser) skipAttributes() error {
+	for p.peek().text == "[" && !p.peek().ident {
+		for t := p.next(); t.text != "]" || t.ident; t = p.next() {
+			if p.pos > len(p.tokens) {
+				return fmt.Errorf("unterminated attribute list")
+			}
+		}
+	}
+
+	return nil
+}
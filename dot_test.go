@@ -0,0 +1,68 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_rulesFromDOT(t *testing.T) {
+	src := `
+	// Order workflow
+	strict digraph "orders" {
+		rankdir=LR
+		node [shape=box, style="rounded"];
+		/* happy path */
+		created -> picked -> packed -> shipped [label="ok"];
+		"picked" -> canceled
+		created:e -> canceled
+		canceled -> {reinstated; archived}
+		subgraph cluster_returns {
+			label = "Returns"
+			node [color=red]
+			delivered -> returned
+		}
+		shipped -> delivered
+		reinstated -> picked
+	}`
+
+	rules, err := RulesFromDOT[string](src)
+	if err != nil {
+		t.Fatalf("RulesFromDOT() returned an error: %v", err)
+	}
+
+	expected := map[string][]string{
+		"created":    {"picked", "canceled"},
+		"picked":     {"packed", "canceled"},
+		"packed":     {"shipped"},
+		"shipped":    {"delivered"},
+		"canceled":   {"reinstated", "archived"},
+		"delivered":  {"returned"},
+		"reinstated": {"picked"},
+	}
+
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("RulesFromDOT() returned %v, expected %v", rules, expected)
+	}
+
+	// State types based on string are supported
+	typed, err := RulesFromDOT[CustomStateEnum]("digraph { A -> B }")
+	if err != nil || !reflect.DeepEqual(typed, map[CustomStateEnum][]CustomStateEnum{CustomStateEnumA: {CustomStateEnumB}}) {
+		t.Errorf("RulesFromDOT() returned %v, %v", typed, err)
+	}
+}
+
+func Test_rulesFromDOTErrors(t *testing.T) {
+	for _, src := range []string{
+		"graph { a -- b }",
+		"digraph { a -- b }",
+		"digraph { a -> }",
+		"digraph { a -> b",
+		"digraph { a -> b } extra",
+		"digraph { a [label=x }",
+	} {
+		if _, err := RulesFromDOT[string](src); !errors.Is(err, ErrInvalidDiagram) {
+			t.Errorf("RulesFromDOT(%q) returned %v, expected ErrInvalidDiagram", src, err)
+		}
+	}
+}
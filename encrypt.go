@@ -0,0 +1,116 @@
+package statetrooper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Keyring supplies AES keys for encrypted snapshots, for example backed by a KMS
+// Keys must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256
+type Keyring interface {
+	// CurrentKey returns the key to encrypt new snapshots with and its ID
+	CurrentKey() (id string, key []byte, err error)
+	// Key returns the key with the given ID, to decrypt snapshots encrypted with older keys
+	Key(id string) ([]byte, error)
+}
+
+// staticKeyring is a Keyring holding a single key
+type staticKeyring []byte
+
+// StaticKey returns a Keyring that always uses key
+func StaticKey(key []byte) Keyring {
+	return staticKeyring(key)
+}
+
+func (k staticKeyring) CurrentKey() (string, []byte, error) {
+	return "static", k, nil
+}
+
+func (k staticKeyring) Key(id string) ([]byte, error) {
+	if id != "static" {
+		return nil, fmt.Errorf("unknown key %q", id)
+	}
+
+	return k, nil
+}
+
+// encryptedSnapshot is the envelope written by ExportEncrypted
+type encryptedSnapshot struct {
+	KeyID      string `json:"key_id"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ExportEncrypted writes the JSON snapshot of the FSM to w encrypted with AES-GCM,
+// using the current key of keys. The key ID is stored alongside so keys can be rotated
+func (fsm *FSM[T]) ExportEncrypted(w io.Writer, keys Keyring) error {
+	plaintext, err := fsm.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	id, key, err := keys.CurrentKey()
+	if err != nil {
+		return err
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(encryptedSnapshot{
+		KeyID:      id,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(id)),
+	})
+}
+
+// ImportEncrypted restores the FSM from a snapshot written by ExportEncrypted
+// The snapshot is rejected if it was tampered with or encrypted with a different key
+func (fsm *FSM[T]) ImportEncrypted(r io.Reader, keys Keyring) error {
+	var snapshot encryptedSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return err
+	}
+
+	key, err := keys.Key(snapshot.KeyID)
+	if err != nil {
+		return err
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshot.Nonce) != aead.NonceSize() {
+		return fmt.Errorf("invalid nonce length %d", len(snapshot.Nonce))
+	}
+
+	plaintext, err := aead.Open(nil, snapshot.Nonce, snapshot.Ciphertext, []byte(snapshot.KeyID))
+	if err != nil {
+		return fmt.Errorf("decrypting snapshot: %w", err)
+	}
+
+	return fsm.UnmarshalJSON(plaintext)
+}
+
+// newGCM returns an AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
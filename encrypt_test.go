@@ -0,0 +1,52 @@
+package statetrooper
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_encryptedSnapshot(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "paid")
+	fsm.Transition("paid", map[string]string{"email": "customer@example.com"})
+
+	key := bytes.Repeat([]byte{7}, 32)
+
+	var buf bytes.Buffer
+	if err := fsm.ExportEncrypted(&buf, StaticKey(key)); err != nil {
+		t.Fatalf("ExportEncrypted() returned an error: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "customer@example.com") {
+		t.Errorf("Encrypted snapshot contains plaintext metadata: %s", buf.String())
+	}
+
+	restored := NewFSM[string]("created", 10)
+	if err := restored.ImportEncrypted(bytes.NewReader(buf.Bytes()), StaticKey(key)); err != nil {
+		t.Fatalf("ImportEncrypted() returned an error: %v", err)
+	}
+
+	if restored.CurrentState() != "paid" || restored.Transitions()[0].Metadata["email"] != "customer@example.com" {
+		t.Errorf("Restored FSM is in %v with history %v", restored.CurrentState(), restored.Transitions())
+	}
+
+	wrongKey := bytes.Repeat([]byte{8}, 32)
+	if err := NewFSM[string]("created", 10).ImportEncrypted(bytes.NewReader(buf.Bytes()), StaticKey(wrongKey)); err == nil {
+		t.Errorf("ImportEncrypted() accepted a snapshot encrypted with a different key")
+	}
+
+	// Tampering with the ciphertext is detected
+	var snapshot encryptedSnapshot
+	json.Unmarshal(buf.Bytes(), &snapshot)
+	snapshot.Ciphertext[0] ^= 1
+	tampered, _ := json.Marshal(snapshot)
+	if err := NewFSM[string]("created", 10).ImportEncrypted(bytes.NewReader(tampered), StaticKey(key)); err == nil {
+		t.Errorf("ImportEncrypted() accepted a tampered snapshot")
+	}
+
+	if err := fsm.ExportEncrypted(&buf, StaticKey([]byte("short"))); err == nil {
+		t.Errorf("ExportEncrypted() accepted an invalid key")
+	}
+}
@@ -0,0 +1,112 @@
+package statetrooper
+
+import "time"
+
+// EqualOptions configures what Equal compares
+type EqualOptions struct {
+	// CompareHistory includes the transition history in the comparison
+	CompareHistory bool
+	// TimestampTolerance is the maximum difference allowed between the timestamps of matching transitions
+	TimestampTolerance time.Duration
+}
+
+// Equal reports whether fsm and other are in the same state with the same rules, ignoring the order
+// in which rules were added. With opts.CompareHistory, their transition histories must also match
+// Each FSM is read under its own lock, so the comparison is not atomic across both FSMs
+func (fsm *FSM[T]) Equal(other *FSM[T], opts EqualOptions) bool {
+	if fsm == other {
+		return true
+	}
+
+	if fsm == nil || other == nil {
+		return false
+	}
+
+	if fsm.CurrentState() != other.CurrentState() {
+		return false
+	}
+
+	if !equalRules(fsm.Rules(), other.Rules()) {
+		return false
+	}
+
+	if !opts.CompareHistory {
+		return true
+	}
+
+	a, b := fsm.Transitions(), other.Transitions()
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !equalTransitions(&a[i], &b[i], opts.TimestampTolerance) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalRules reports whether two rulesets contain the same transitions
+func equalRules[T comparable](a map[T][]T, b map[T][]T) bool {
+	edges := make(map[edge[T]]int)
+
+	for from, toStates := range a {
+		for _, to := range toStates {
+			edges[edge[T]{from: from, to: to}]++
+		}
+	}
+
+	for from, toStates := range b {
+		for _, to := range toStates {
+			e := edge[T]{from: from, to: to}
+			if edges[e] == 0 {
+				return false
+			}
+			edges[e]--
+		}
+	}
+
+	for _, n := range edges {
+		if n != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalTransitions reports whether two transitions match, allowing their timestamps to differ by up to tolerance
+func equalTransitions[T comparable](a *Transition[T], b *Transition[T], tolerance time.Duration) bool {
+	if a.FromState != b.FromState || a.ToState != b.ToState || a.Duplicate != b.Duplicate || a.Cycles != b.Cycles ||
+		a.Failed != b.Failed || a.Error != b.Error || a.Touch != b.Touch || a.Initial != b.Initial || a.Actor != b.Actor {
+		return false
+	}
+
+	if len(a.Metadata) != len(b.Metadata) {
+		return false
+	}
+
+	for k, v := range a.Metadata {
+		if bv, ok := b.Metadata[k]; !ok || bv != v {
+			return false
+		}
+	}
+
+	return equalTimes(a.Timestamp, b.Timestamp, tolerance) && equalTimes(a.Until, b.Until, tolerance)
+}
+
+// equalTimes reports whether two optional times are both unset or within tolerance of each other
+func equalTimes(a *time.Time, b *time.Time, tolerance time.Duration) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	diff := a.Sub(*b)
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff <= tolerance
+}
@@ -0,0 +1,57 @@
+package statetrooper
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_equal(t *testing.T) {
+	newOrderFSM := func(start time.Time) *FSM[CustomStateEnum] {
+		now := start
+		fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+		fsm.now = func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		}
+		return fsm
+	}
+
+	start := time.Date(2023, 6, 18, 12, 0, 0, 0, time.UTC)
+
+	a := newOrderFSM(start)
+	a.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+	a.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	b := newOrderFSM(start.Add(50 * time.Millisecond))
+	b.AddRule(CustomStateEnumB, CustomStateEnumC)
+	b.AddRule(CustomStateEnumA, CustomStateEnumC, CustomStateEnumB)
+
+	if !a.Equal(b, EqualOptions{CompareHistory: true}) {
+		t.Errorf("FSMs with the same rules in a different order are not equal")
+	}
+
+	a.Transition(CustomStateEnumB, map[string]string{"requested_by": "Mahmoud"})
+	b.Transition(CustomStateEnumB, map[string]string{"requested_by": "Mahmoud"})
+
+	if !a.Equal(b, EqualOptions{}) {
+		t.Errorf("FSMs in the same state with the same rules are not equal")
+	}
+
+	if a.Equal(b, EqualOptions{CompareHistory: true}) {
+		t.Errorf("FSMs with timestamps 50ms apart are equal without tolerance")
+	}
+
+	if !a.Equal(b, EqualOptions{CompareHistory: true, TimestampTolerance: 100 * time.Millisecond}) {
+		t.Errorf("FSMs with timestamps 50ms apart are not equal within a 100ms tolerance")
+	}
+
+	b.AddRule(CustomStateEnumC, CustomStateEnumD)
+
+	if a.Equal(b, EqualOptions{}) {
+		t.Errorf("FSMs with different rules are equal")
+	}
+
+	if a.Equal(nil, EqualOptions{}) || !a.Equal(a, EqualOptions{}) {
+		t.Errorf("Equal does not handle nil or identical FSMs")
+	}
+}
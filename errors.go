@@ -1,13 +1,290 @@
 package statetrooper
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStateNotRegistered is returned when a state is used that is not part of
+// the FSM's registered states
+var ErrStateNotRegistered = errors.New("state not registered")
+
+// ErrDuplicateRule is returned when a rule is added for a transition that already exists
+var ErrDuplicateRule = errors.New("duplicate rule")
+
+// ErrSelfLoop is returned when a rule from a state to itself is added without self-loops being allowed
+var ErrSelfLoop = errors.New("self-loop rule not allowed")
+
+// ErrStateExists is returned when a state is renamed to a state that is already in use
+var ErrStateExists = errors.New("state already exists")
+
+// ErrInvalidComposite is returned when a composite state declaration is inconsistent
+var ErrInvalidComposite = errors.New("invalid composite state")
+
+// ErrTooSoon is returned when a transition is attempted before its cooldown has elapsed
+var ErrTooSoon = errors.New("transition attempted too soon")
+
+// ErrBudgetExceeded is returned when a transition would exceed the configured maximum number of transitions
+var ErrBudgetExceeded = errors.New("transition budget exceeded")
+
+// ErrInvalidRuleTag is returned when a struct tag rule declaration cannot be parsed
+var ErrInvalidRuleTag = errors.New("invalid rule tag")
+
+// ErrHistoryUnavailable is returned when a past state is requested that is not covered by the retained history
+var ErrHistoryUnavailable = errors.New("history unavailable")
+
+// ErrUnsupportedVersion is returned when a persisted FSM cannot be migrated to the current ruleset version
+var ErrUnsupportedVersion = errors.New("unsupported ruleset version")
+
+// ErrInvalidDiagram is returned when a diagram cannot be parsed into a ruleset
+var ErrInvalidDiagram = errors.New("invalid diagram")
+
+// ErrEntityNotFound is returned when a Manager has no FSM for an entity ID
+var ErrEntityNotFound = errors.New("entity not found")
+
+// ErrEntityExists is returned when an FSM is added to a Manager under an ID that is already in use
+var ErrEntityExists = errors.New("entity already exists")
+
+// ErrFSMRegistered is returned when an FSM is added to a Manager that already holds it under another ID
+var ErrFSMRegistered = errors.New("fsm already registered")
+
+// ErrQuotaExceeded is returned when an FSM is added to a Manager that already holds its quota of entities
+var ErrQuotaExceeded = errors.New("entity quota exceeded")
+
+// ErrBatchAborted is returned for the entities of an all-or-nothing batch that was not applied
+// because another entity's transition failed
+var ErrBatchAborted = errors.New("batch aborted")
+
+// ErrTriggerLoop is returned when adding a trigger would let triggers fire each other indefinitely
+var ErrTriggerLoop = errors.New("trigger loop")
+
+// ErrMailboxStopped is returned for commands sent to a Mailbox that has been stopped
+var ErrMailboxStopped = errors.New("mailbox stopped")
+
+// ErrHookPanic is reported when an asynchronous hook, or a guard or hook running with a timeout, panics
+var ErrHookPanic = errors.New("hook panicked")
+
+// ErrHookQueueFull is reported when an asynchronous hook could not be queued because the queue was full
+var ErrHookQueueFull = errors.New("hook queue full")
+
+// ErrRetryCancelled is returned by a Retry that was cancelled before its transition succeeded
+var ErrRetryCancelled = errors.New("retry cancelled")
+
+// ErrRetryExpired is returned by a Retry whose timeout elapsed before its transition succeeded
+var ErrRetryExpired = errors.New("retry expired")
+
+// ErrInvalidConfig is returned by Validate for each configuration problem found
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ErrBreakerOpen is returned by calls rejected by an open Breaker
+var ErrBreakerOpen = errors.New("circuit breaker open")
+
+// ErrUnknownVariant is returned when an entity is assigned to a variant that is not set
+var ErrUnknownVariant = errors.New("unknown variant")
+
+// ErrNotStarted is returned for transitions of an FSM created with NewUnstartedFSM before Start is called
+var ErrNotStarted = errors.New("fsm not started")
+
+// ErrAlreadyStarted is returned by Start if the FSM has already been started
+var ErrAlreadyStarted = errors.New("fsm already started")
+
+// ErrTransitionNotFound is returned when a transition ID does not match any retained history entry
+var ErrTransitionNotFound = errors.New("transition not found")
+
+// ErrMetadataExists is returned when annotating a transition with a metadata key it already has
+var ErrMetadataExists = errors.New("metadata key already set")
+
+// ErrThrottled is returned when an actor exceeds its rate limit
+var ErrThrottled = errors.New("actor rate limit exceeded")
+
+// ErrUnauthorized is returned when the actor is not allowed to perform a transition
+var ErrUnauthorized = errors.New("actor not authorized")
+
+// ErrClosed is returned when using an FSM or Manager that has been closed
+var ErrClosed = errors.New("closed")
+
+// ErrConflict is returned when the sources of a recovery disagree about an entity and cannot be reconciled
+var ErrConflict = errors.New("unresolved conflict")
+
+// ErrMinDwell is returned when a transition is attempted before the minimum dwell time of the current state has elapsed
+var ErrMinDwell = errors.New("minimum dwell time not elapsed")
+
+// ErrNoValidCandidate is returned when none of the candidate targets of TransitionFirstValid can be transitioned to
+var ErrNoValidCandidate = errors.New("no valid candidate")
+
+// ErrAutoTransitionLoop is returned when automatic transitions would re-enter a state already entered in the same chain
+var ErrAutoTransitionLoop = errors.New("automatic transition loop")
+
+// ErrSealed is returned when changing the rules of an FSM that has been sealed
+var ErrSealed = errors.New("fsm sealed")
+
+// ErrDuplicateEvent is returned when an event is declared twice for a state with different targets
+var ErrDuplicateEvent = errors.New("duplicate event")
+
+// ErrUnknownEvent is returned when firing an event that is not declared for the current state
+var ErrUnknownEvent = errors.New("unknown event")
+
+// ErrDuplicateStep is returned when a transaction has more than one step for the same FSM
+var ErrDuplicateStep = errors.New("duplicate transaction step")
+
+// ErrActionFailed is matched by the ActionError of an entry or exit action. Actions run after their
+// transition has been committed, so the FSM is in the new state and the transition must not be retried
+var ErrActionFailed = errors.New("state action failed")
 
 // TransitionError represents an error that occurs during a state transition
 type TransitionError[T comparable] struct {
 	FromState T
 	ToState   T
+	// Allowed lists the states that were valid targets from FromState at the time of the attempt
+	Allowed   []T
+	Timestamp time.Time
+	// Machine identifies the FSM as name/entity, empty if it has none. Transitions of such an FSM return
+	// the error wrapped in a MachineError, which adds the identity to the message
+	Machine string
 }
 
 func (err TransitionError[T]) Error() string {
-	return fmt.Sprintf("invalid state transition from %v to %v", err.FromState, err.ToState)
+	return fmt.Sprintf("invalid state transition from %v to %v, allowed: %v", display(err.FromState), display(err.ToState), displayAll(err.Allowed))
+}
+
+// GuardError represents a transition that was rejected by a guard
+type GuardError[T comparable] struct {
+	FromState T
+	ToState   T
+	Err       error
+}
+
+func (err GuardError[T]) Error() string {
+	return fmt.Sprintf("state transition from %v to %v rejected by guard: %v", display(err.FromState), display(err.ToState), err.Err)
+}
+
+func (err GuardError[T]) Unwrap() error {
+	return err.Err
+}
+
+// CooldownError represents a transition rejected because its cooldown has not elapsed
+// It matches ErrTooSoon with errors.Is
+type CooldownError[T comparable] struct {
+	FromState  T
+	ToState    T
+	RetryAfter time.Duration
+}
+
+func (err CooldownError[T]) Error() string {
+	return fmt.Sprintf("state transition from %v to %v attempted too soon, retry after %v", display(err.FromState), display(err.ToState), err.RetryAfter)
+}
+
+func (err CooldownError[T]) Is(target error) bool {
+	return target == ErrTooSoon
+}
+
+// MinDwellError represents a transition rejected because the FSM has not been in FromState for its minimum dwell time
+// It matches ErrMinDwell with errors.Is
+type MinDwellError[T comparable] struct {
+	FromState T
+	ToState   T
+	MinDwell  time.Duration
+	Remaining time.Duration
+}
+
+func (err MinDwellError[T]) Error() string {
+	return fmt.Sprintf("state transition from %v to %v attempted before the minimum dwell of %v, retry after %v", display(err.FromState), display(err.ToState), err.MinDwell, err.Remaining)
+}
+
+func (err MinDwellError[T]) Is(target error) bool {
+	return target == ErrMinDwell
+}
+
+// BudgetError represents a transition rejected because a transition budget has been used up
+// It matches ErrBudgetExceeded with errors.Is
+type BudgetError[T comparable] struct {
+	FromState T
+	ToState   T
+	Limit     int
+}
+
+func (err BudgetError[T]) Error() string {
+	return fmt.Sprintf("state transition from %v to %v exceeds the budget of %d transitions", display(err.FromState), display(err.ToState), err.Limit)
+}
+
+func (err BudgetError[T]) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// ThrottleError represents a transition rejected because the actor has used up its rate limit
+// It matches ErrThrottled with errors.Is
+type ThrottleError[T comparable] struct {
+	FromState  T
+	ToState    T
+	Actor      string
+	Limit      int
+	Window     time.Duration
+	RetryAfter time.Duration
+}
+
+func (err ThrottleError[T]) Error() string {
+	return fmt.Sprintf("state transition from %v to %v by %q exceeds the limit of %d transitions per %v, retry after %v",
+		display(err.FromState), display(err.ToState), err.Actor, err.Limit, err.Window, err.RetryAfter)
+}
+
+func (err ThrottleError[T]) Is(target error) bool {
+	return target == ErrThrottled
+}
+
+// AuthorizationError represents a transition rejected because the actor is not allowed to perform it
+// It matches ErrUnauthorized with errors.Is and unwraps to the reason
+type AuthorizationError[T comparable] struct {
+	FromState T
+	ToState   T
+	Actor     string
+	Err       error
+}
+
+func (err AuthorizationError[T]) Error() string {
+	return fmt.Sprintf("state transition from %v to %v not authorized for actor %q: %v", display(err.FromState), display(err.ToState), err.Actor, err.Err)
+}
+
+func (err AuthorizationError[T]) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+func (err AuthorizationError[T]) Unwrap() error {
+	return err.Err
+}
+
+// ActionError represents a state entry or exit action that failed after all of its attempts
+// It is returned after the transition has been committed and matches ErrActionFailed with errors.Is
+type ActionError[T comparable] struct {
+	State    T
+	Action   string
+	Attempts int
+	Err      error
+}
+
+func (err ActionError[T]) Error() string {
+	return fmt.Sprintf("%s action for state %v failed after %d attempts: %v", err.Action, display(err.State), err.Attempts, err.Err)
+}
+
+func (err ActionError[T]) Is(target error) bool {
+	return target == ErrActionFailed
+}
+
+func (err ActionError[T]) Unwrap() error {
+	return err.Err
+}
+
+// HookError represents a transition aborted by a pre-commit hook
+type HookError[T comparable] struct {
+	FromState T
+	ToState   T
+	Err       error
+}
+
+func (err HookError[T]) Error() string {
+	return fmt.Sprintf("state transition from %v to %v aborted by hook: %v", display(err.FromState), display(err.ToState), err.Err)
+}
+
+func (err HookError[T]) Unwrap() error {
+	return err.Err
 }
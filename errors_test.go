@@ -0,0 +1,33 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_transitionErrorAllowedTargets(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+
+	_, err := fsm.Transition(CustomStateEnumD, nil)
+
+	var tErr TransitionError[CustomStateEnum]
+	if !errors.As(err, &tErr) {
+		t.Fatalf("Transition(%v) returned %v, expected a TransitionError", CustomStateEnumD, err)
+	}
+
+	expectedAllowed := []CustomStateEnum{CustomStateEnumB, CustomStateEnumC}
+	if !reflect.DeepEqual(tErr.Allowed, expectedAllowed) {
+		t.Errorf("TransitionError has incorrect Allowed. Got %v, expected %v", tErr.Allowed, expectedAllowed)
+	}
+
+	if tErr.Timestamp.IsZero() {
+		t.Errorf("TransitionError has zero Timestamp")
+	}
+
+	expectedMsg := "invalid state transition from A to D, allowed: [B C]"
+	if err.Error() != expectedMsg {
+		t.Errorf("TransitionError message = %q, expected %q", err.Error(), expectedMsg)
+	}
+}
@@ -0,0 +1,155 @@
+package statetrooper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// EventMetadataKey is the metadata key recording the event that caused a transition performed by Fire
+const EventMetadataKey = "event"
+
+// eventRule is the source state an event is declared for
+type eventRule[T comparable] struct {
+	event string
+	from  T
+}
+
+// firing carries the event being fired through a transition attempt and the target it resolved to
+// It is scoped to the FSM it is fired on, as the context also reaches transitions of other FSMs started
+// from post-commit hooks, such as triggers
+type firing[T comparable] struct {
+	fsm    *FSM[T]
+	event  string
+	target T
+}
+
+type firingKey struct{}
+
+// AddEvent declares that firing event in fromState transitions to toState, adding the rule from fromState
+// to toState unless it exists. An event declared for a composite state applies to all of its substates
+// unless a substate declares the event itself. The rule is validated as by AddRule and ErrDuplicateEvent is
+// returned if the event is already declared for fromState with another target
+func (fsm *FSM[T]) AddEvent(event string, fromState T, toState T) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.sealed {
+		return ErrSealed
+	}
+
+	key := eventRule[T]{event: event, from: fromState}
+	if target, ok := fsm.events[key]; ok {
+		if target == toState {
+			return nil
+		}
+		return fmt.Errorf("%w: %q from %v already goes to %v", ErrDuplicateEvent, event, fromState, target)
+	}
+
+	if !contains(fsm.ruleset[fromState], toState) {
+		if err := fsm.checkRule(&fromState, []T{toState}); err != nil {
+			return err
+		}
+		fsm.ownRules()
+		fsm.ruleset[fromState] = append(fsm.ruleset[fromState], toState)
+	}
+
+	if fsm.events == nil {
+		fsm.events = make(map[eventRule[T]]T)
+	}
+	fsm.events[key] = toState
+
+	return nil
+}
+
+// Fire transitions to the target that event is declared for in the current state and returns the new state
+// The target is resolved under the same lock as the transition, so a concurrent transition cannot change
+// the state in between. An error wrapping ErrUnknownEvent is returned if the event is not declared
+// for the current state. The event is recorded in the metadata under EventMetadataKey
+func (fsm *FSM[T]) Fire(event string, metadata map[string]string) (T, error) {
+	return fsm.FireCtx(context.Background(), event, metadata)
+}
+
+// FireCtx is like Fire but passes ctx to guards and hooks
+func (fsm *FSM[T]) FireCtx(ctx context.Context, event string, metadata map[string]string) (T, error) {
+	tagged := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		tagged[k] = v
+	}
+	tagged[EventMetadataKey] = event
+
+	f := &firing[T]{fsm: fsm, event: event}
+
+	var target T
+	state, committed, err := fsm.apply(context.WithValue(ctx, firingKey{}, f), target, tagged)
+	if err != nil && !committed {
+		fsm.deadLetter(state, f.target, tagged, err, 1)
+	}
+
+	return state, err
+}
+
+// Events returns the events that can be fired in the current state, sorted
+func (fsm *FSM[T]) Events() []string {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	var events []string
+	for key := range fsm.events {
+		if _, ok := fsm.eventTarget(key.event, fsm.currentState); ok && !contains(events, key.event) {
+			events = append(events, key.event)
+		}
+	}
+
+	sort.Strings(events)
+
+	return events
+}
+
+// eventTarget returns the target of event in state, looking through the parents of composite states
+// The caller must hold the lock
+func (fsm *FSM[T]) eventTarget(event string, state T) (T, bool) {
+	for s, ok := state, true; ok; s, ok = fsm.parents[s] {
+		if target, found := fsm.events[eventRule[T]{event: event, from: s}]; found {
+			return target, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// resolveEvent replaces targetState with the target of the event being fired in ctx, if any
+// The caller must hold the lock
+func (fsm *FSM[T]) resolveEvent(ctx context.Context, targetState T) (T, error) {
+	f := fsm.activeFiring(ctx)
+	if f == nil {
+		return targetState, nil
+	}
+
+	target, ok := fsm.eventTarget(f.event, fsm.currentState)
+	if !ok {
+		return targetState, fmt.Errorf("%w: %q in state %v", ErrUnknownEvent, f.event, display(fsm.currentState))
+	}
+	f.target = target
+
+	return target, nil
+}
+
+// firedTarget returns the target the event being fired in ctx resolved to, or targetState if no event is fired
+func (fsm *FSM[T]) firedTarget(ctx context.Context, targetState T) T {
+	if f := fsm.activeFiring(ctx); f != nil {
+		return f.target
+	}
+
+	return targetState
+}
+
+// activeFiring returns the event being fired on fsm in ctx, or nil if none is
+func (fsm *FSM[T]) activeFiring(ctx context.Context) *firing[T] {
+	if f, ok := ctx.Value(firingKey{}).(*firing[T]); ok && f.fsm == fsm {
+		return f
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_fire(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	if err := fsm.AddEvent("submit", CustomStateEnumA, CustomStateEnumB); err != nil {
+		t.Fatalf("AddEvent returned %v", err)
+	}
+	fsm.AddEvent("approve", CustomStateEnumB, CustomStateEnumC)
+	fsm.AddEvent("reject", CustomStateEnumB, CustomStateEnumA)
+
+	if err := fsm.AddEvent("submit", CustomStateEnumA, CustomStateEnumC); !errors.Is(err, ErrDuplicateEvent) {
+		t.Errorf("AddEvent with another target returned %v, expected ErrDuplicateEvent", err)
+	}
+	if err := fsm.AddEvent("submit", CustomStateEnumA, CustomStateEnumB); err != nil {
+		t.Errorf("AddEvent declaring an event again returned %v", err)
+	}
+
+	if !fsm.CanTransition(CustomStateEnumB) {
+		t.Errorf("AddEvent did not add the rule from A to B")
+	}
+
+	if _, err := fsm.Fire("approve", nil); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("Fire of an event not declared for A returned %v, expected ErrUnknownEvent", err)
+	}
+
+	state, err := fsm.Fire("submit", map[string]string{"by": "alice"})
+	if err != nil || state != CustomStateEnumB {
+		t.Fatalf("Fire returned %v, %v, expected B", state, err)
+	}
+
+	if events := fsm.Events(); len(events) != 2 || events[0] != "approve" || events[1] != "reject" {
+		t.Errorf("Events returned %v, expected approve and reject", events)
+	}
+
+	fsm.Fire("approve", nil)
+
+	history := fsm.Transitions()
+	if len(history) != 2 || history[1].ToState != CustomStateEnumC {
+		t.Fatalf("history is %v, expected transitions to B and C", history)
+	}
+	if md := history[0].Metadata; md[EventMetadataKey] != "submit" || md["by"] != "alice" {
+		t.Errorf("Fire recorded metadata %v, expected the event and the caller's metadata", md)
+	}
+}
+
+func Test_fireComposite(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumB, 10)
+	fsm.AddCompositeState(CustomStateEnumA, CustomStateEnumB, NoHistory, CustomStateEnumB, CustomStateEnumC)
+	fsm.AddEvent("next", CustomStateEnumB, CustomStateEnumC)
+	fsm.AddEvent("cancel", CustomStateEnumA, CustomStateEnumD)
+
+	if state, err := fsm.Fire("next", nil); err != nil || state != CustomStateEnumC {
+		t.Fatalf("Fire returned %v, %v, expected C", state, err)
+	}
+
+	if state, err := fsm.Fire("cancel", nil); err != nil || state != CustomStateEnumD {
+		t.Errorf("Fire of an event declared for the parent returned %v, %v, expected D", state, err)
+	}
+}
+
+func Test_fireRecorded(t *testing.T) {
+	recorder := NewRecorder[CustomStateEnum]()
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddEvent("submit", CustomStateEnumA, CustomStateEnumB)
+	fsm.SetRecorder(recorder)
+
+	fsm.Fire("submit", nil)
+
+	if inputs := recorder.Inputs(); len(inputs) != 1 || inputs[0].Target != CustomStateEnumB {
+		t.Errorf("recorded %+v, expected the target the event resolved to", inputs)
+	}
+}
+
+func Test_fireTriggersOtherFSM(t *testing.T) {
+	manager := NewManager[string, string]()
+
+	shipment := NewFSM[string]("shipped", 10)
+	shipment.AddEvent("deliver", "shipped", "delivered")
+
+	order := NewFSM[string]("shipped", 10)
+	order.AddRule("shipped", "completed")
+
+	manager.Add("shipment-1", shipment)
+	manager.Add("order-1", order)
+	manager.AddTrigger("shipment-1", "delivered", "order-1", "completed")
+
+	var hookErr error
+	shipment.SetHookErrorHandler(func(tr Transition[string], err error) { hookErr = err })
+
+	// The event is resolved by the FSM it is fired on, not by the FSMs its hooks transition
+	if _, err := shipment.Fire("deliver", nil); err != nil {
+		t.Fatalf("Fire() returned an error: %v", err)
+	}
+
+	if hookErr != nil || order.CurrentState() != "completed" {
+		t.Errorf("Trigger left order in %v with hook error %v, expected completed", order.CurrentState(), hookErr)
+	}
+}
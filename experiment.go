@@ -0,0 +1,144 @@
+package statetrooper
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// VariantMetadataKey is the metadata key that transitions of entities created by Manager.Create are tagged with
+const VariantMetadataKey = "variant"
+
+// Variant is one arm of a ruleset experiment
+type Variant[T comparable] struct {
+	Name     string
+	Template *Template[T]
+	// Weight is the relative share of entities assigned to the variant by hash
+	// A zero weight means the variant is only used for entities explicitly assigned to it
+	Weight int
+}
+
+// SetVariants sets the variants that entities created with Create are assigned to
+// Entities are assigned by a hash of their ID, so the same ID always lands in the same variant,
+// unless assigned explicitly with AssignVariant
+func (m *Manager[K, T]) SetVariants(variants ...Variant[T]) error {
+	total := 0
+	names := make(map[string]bool, len(variants))
+
+	for _, v := range variants {
+		switch {
+		case v.Name == "" || names[v.Name]:
+			return fmt.Errorf("%w: variant name %q is empty or repeated", ErrInvalidConfig, v.Name)
+		case v.Template == nil:
+			return fmt.Errorf("%w: variant %q has no template", ErrInvalidConfig, v.Name)
+		case v.Weight < 0:
+			return fmt.Errorf("%w: variant %q has a negative weight", ErrInvalidConfig, v.Name)
+		}
+
+		names[v.Name] = true
+		total += v.Weight
+	}
+
+	if total == 0 {
+		return fmt.Errorf("%w: variants have no weight", ErrInvalidConfig)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.variants = append([]Variant[T](nil), variants...)
+
+	return nil
+}
+
+// AssignVariant assigns the entity to the named variant, overriding the hash-based assignment
+// It takes effect when the entity is created with Create
+func (m *Manager[K, T]) AssignVariant(id K, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.variant(name); !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownVariant, name)
+	}
+
+	if m.assignments == nil {
+		m.assignments = make(map[K]string)
+	}
+
+	m.assignments[id] = name
+
+	return nil
+}
+
+// Create creates the FSM of an entity in initialState from the template of its variant and adds it
+// All of its transitions are tagged with the variant's name under VariantMetadataKey
+func (m *Manager[K, T]) Create(id K, initialState T) (*FSM[T], error) {
+	m.mu.RLock()
+	v, err := m.assign(id)
+	m.mu.RUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fsm := v.Template.New(initialState)
+	fsm.metadataTags = map[string]string{VariantMetadataKey: v.Name}
+
+	if err := m.Add(id, fsm); err != nil {
+		return nil, err
+	}
+
+	return fsm, nil
+}
+
+// Variant returns the name of the variant the entity is or would be assigned to
+func (m *Manager[K, T]) Variant(id K) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, err := m.assign(id)
+
+	return v.Name, err == nil
+}
+
+// assign returns the variant of an entity. The caller must hold the lock
+func (m *Manager[K, T]) assign(id K) (Variant[T], error) {
+	if len(m.variants) == 0 {
+		return Variant[T]{}, fmt.Errorf("%w: no variants set", ErrUnknownVariant)
+	}
+
+	if name, ok := m.assignments[id]; ok {
+		if v, ok := m.variant(name); ok {
+			return v, nil
+		}
+	}
+
+	total := 0
+	for _, v := range m.variants {
+		total += v.Weight
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprint(id)))
+	n := int(h.Sum32() % uint32(total))
+
+	for _, v := range m.variants {
+		if n < v.Weight {
+			return v, nil
+		}
+		n -= v.Weight
+	}
+
+	// Unreachable, as n is less than the total weight
+	return m.variants[len(m.variants)-1], nil
+}
+
+// variant returns the variant with the given name. The caller must hold the lock
+func (m *Manager[K, T]) variant(name string) (Variant[T], bool) {
+	for _, v := range m.variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+
+	return Variant[T]{}, false
+}
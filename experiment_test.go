@@ -0,0 +1,86 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_experimentVariants(t *testing.T) {
+	base := NewFSM[string]("created", 10)
+	base.AddRule("created", "shipped")
+
+	fast := NewFSM[string]("created", 10)
+	fast.AddRule("created", "shipped")
+	fast.AddRule("created", "delivered")
+
+	m := NewManager[int, string]()
+
+	if _, err := m.Create(1, "created"); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("Create() returned %v without variants, expected ErrUnknownVariant", err)
+	}
+
+	if err := m.SetVariants(Variant[string]{Name: "control", Template: NewTemplate(base)}); err == nil {
+		t.Errorf("SetVariants() accepted variants without weight")
+	}
+
+	err := m.SetVariants(
+		Variant[string]{Name: "control", Template: NewTemplate(base), Weight: 1},
+		Variant[string]{Name: "fast", Template: NewTemplate(fast), Weight: 1},
+	)
+	if err != nil {
+		t.Fatalf("SetVariants() returned an error: %v", err)
+	}
+
+	// Hash-based assignment is stable and spreads entities across variants
+	assigned := make(map[string]int)
+	for id := 0; id < 100; id++ {
+		name, ok := m.Variant(id)
+		if again, _ := m.Variant(id); !ok || again != name {
+			t.Fatalf("Variant(%d) returned %q then %q", id, name, again)
+		}
+		assigned[name]++
+	}
+
+	if assigned["control"] == 0 || assigned["fast"] == 0 {
+		t.Errorf("Entities were assigned %v, expected both variants to be used", assigned)
+	}
+
+	if err := m.AssignVariant(1, "missing"); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("AssignVariant() returned %v for a missing variant, expected ErrUnknownVariant", err)
+	}
+
+	m.AssignVariant(1, "fast")
+	m.AssignVariant(2, "control")
+
+	fsm, err := m.Create(1, "created")
+	if err != nil {
+		t.Fatalf("Create() returned an error: %v", err)
+	}
+
+	if _, err := fsm.Transition("delivered", map[string]string{"by": "courier"}); err != nil {
+		t.Errorf("Transition() returned %v, expected the fast variant's rules", err)
+	}
+
+	tr := fsm.Transitions()[0]
+	if tr.Metadata[VariantMetadataKey] != "fast" || tr.Metadata["by"] != "courier" {
+		t.Errorf("Transition metadata is %v, expected it to be tagged with the variant", tr.Metadata)
+	}
+
+	control, _ := m.Create(2, "created")
+	if control.CanTransition("delivered") {
+		t.Errorf("Control variant allows the fast variant's rule")
+	}
+
+	if got, _ := m.Get(2); got != control {
+		t.Errorf("Create() did not add the FSM to the manager")
+	}
+
+	// All-or-nothing batches tag transitions like single ones
+	if _, err := m.TransitionMany([]int{2}, "shipped", nil, AllOrNothing); err != nil {
+		t.Fatalf("TransitionMany() returned an error: %v", err)
+	}
+
+	if tr := control.Transitions()[0]; tr.Metadata[VariantMetadataKey] != "control" {
+		t.Errorf("Batch transition metadata is %v, expected it to be tagged with the variant", tr.Metadata)
+	}
+}
@@ -0,0 +1,89 @@
+package statetrooper
+
+import "context"
+
+// RejectReason identifies why a transition is currently disallowed
+type RejectReason int
+
+const (
+	// NotRejected means the transition is currently allowed
+	NotRejected RejectReason = iota
+	// RejectNotStarted means the FSM was created with NewUnstartedFSM and has not been started
+	RejectNotStarted
+	// RejectNotRegistered means the target state is not registered
+	RejectNotRegistered
+	// RejectNoRule means there is no rule from the current state to the target state
+	RejectNoRule
+	// RejectDisabled means the rule from the current state to the target state is disabled
+	RejectDisabled
+	// RejectTerminal means the FSM is in a terminal state with no rule to the target state
+	RejectTerminal
+	// RejectCooldown means a cooldown has not elapsed yet
+	RejectCooldown
+	// RejectBudget means a transition budget is exhausted
+	RejectBudget
+	// RejectGuard means a guard rejected the transition
+	RejectGuard
+	// RejectClosed means the FSM has been closed
+	RejectClosed
+	// RejectMinDwell means the minimum dwell time of the current state has not elapsed yet
+	RejectMinDwell
+	// RejectUnauthorized means the actor lacks an allowed role or the authorizer rejected the transition
+	RejectUnauthorized
+	// RejectThrottled means the actor has exceeded its rate limit
+	RejectThrottled
+)
+
+// Explanation describes whether a transition is currently allowed and, if not, why
+type Explanation[T comparable] struct {
+	FromState T
+	ToState   T
+	Allowed   bool
+	Reason    RejectReason
+	// Err is the error the transition would currently fail with, such as a CooldownError with its RetryAfter
+	Err error
+	// Guard is the position of the rejecting guard among the guards of the transition, in the order they are evaluated
+	// It is -1 unless Reason is RejectGuard
+	Guard int
+}
+
+// Explain reports whether a transition to target is currently allowed and, if not, why
+// Guards are evaluated as they would be by Transition, but pre-commit hooks are not run and nothing is recorded
+func (fsm *FSM[T]) Explain(target T) Explanation[T] {
+	return fsm.ExplainCtx(context.Background(), target)
+}
+
+// ExplainCtx is like Explain but checks the transition as TransitionCtx would with ctx, including its actor
+func (fsm *FSM[T]) ExplainCtx(ctx context.Context, target T) Explanation[T] {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	ex := Explanation[T]{FromState: fsm.currentState, ToState: target, Guard: -1}
+	tn := fsm.timeNow()
+
+	actor, _ := ActorFromContext(ctx)
+	target, noOp, reason, err := fsm.checkTransition(ctx, &actor, target, tn)
+	ex.ToState = target
+	if err != nil {
+		ex.Reason = reason
+		ex.Err = err
+		return ex
+	}
+
+	// A duplicate request within the debounce window or a same-state request handled by policy succeeds
+	if noOp {
+		ex.Allowed = true
+		return ex
+	}
+
+	tr := Transition[T]{FromState: fsm.currentState, ToState: target, Timestamp: &tn, Actor: actor.ID}
+	if i, err := fsm.evaluateGuards(ctx, &tr, fsm.guardsFor(tr.FromState, tr.ToState)); err != nil {
+		ex.Guard = i
+		ex.Reason = RejectGuard
+		ex.Err = guardError(&tr, err)
+		return ex
+	}
+
+	ex.Allowed = true
+	return ex
+}
@@ -0,0 +1,98 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_explain(t *testing.T) {
+	errNotPaid := errors.New("not paid")
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumC, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return nil
+	})
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumC, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errNotPaid
+	})
+	fsm.SetTerminalState(CustomStateEnumD, nil)
+
+	if ex := fsm.Explain(CustomStateEnumB); !ex.Allowed || ex.Reason != NotRejected || ex.Err != nil {
+		t.Errorf("Explain(B) returned %+v, expected the transition to be allowed", ex)
+	}
+
+	ex := fsm.Explain(CustomStateEnumD)
+	var trErr TransitionError[CustomStateEnum]
+	if ex.Allowed || ex.Reason != RejectNoRule || !errors.As(ex.Err, &trErr) {
+		t.Errorf("Explain(D) returned %+v, expected RejectNoRule", ex)
+	}
+
+	ex = fsm.Explain(CustomStateEnumC)
+	if ex.Allowed || ex.Reason != RejectGuard || ex.Guard != 1 || !errors.Is(ex.Err, errNotPaid) {
+		t.Errorf("Explain(C) returned %+v, expected the second guard to reject it", ex)
+	}
+
+	fsm.SetCooldown(time.Minute)
+	fsm.Transition(CustomStateEnumB, nil)
+
+	ex = fsm.Explain(CustomStateEnumA)
+	var cdErr CooldownError[CustomStateEnum]
+	if ex.Allowed || ex.Reason != RejectCooldown || !errors.As(ex.Err, &cdErr) || cdErr.RetryAfter <= 0 {
+		t.Errorf("Explain(A) returned %+v, expected RejectCooldown", ex)
+	}
+
+	fsm.SetCooldown(0)
+	fsm.SetMaxTransitions(1)
+	if ex := fsm.Explain(CustomStateEnumA); ex.Reason != RejectBudget || !errors.Is(ex.Err, ErrBudgetExceeded) {
+		t.Errorf("Explain(A) returned %+v, expected RejectBudget", ex)
+	}
+
+	// Explaining does not record anything
+	if len(fsm.Transitions()) != 1 {
+		t.Errorf("Explain() changed the history: %v", fsm.Transitions())
+	}
+
+	terminal := NewFSM[CustomStateEnum](CustomStateEnumD, 10)
+	terminal.SetTerminalState(CustomStateEnumD, nil)
+	if ex := terminal.Explain(CustomStateEnumA); ex.Reason != RejectTerminal {
+		t.Errorf("Explain(A) from a terminal state returned %+v, expected RejectTerminal", ex)
+	}
+
+	registered := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	registered.RegisterStates(CustomStateEnumA, CustomStateEnumB)
+	if ex := registered.Explain(CustomStateEnumC); ex.Reason != RejectNotRegistered || !errors.Is(ex.Err, ErrStateNotRegistered) {
+		t.Errorf("Explain(C) returned %+v, expected RejectNotRegistered", ex)
+	}
+}
+
+func Test_explainActor(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetAllowedRoles(CustomStateEnumA, CustomStateEnumB, "admin")
+
+	if ex := fsm.Explain(CustomStateEnumB); ex.Allowed || ex.Reason != RejectUnauthorized || !errors.Is(ex.Err, ErrUnauthorized) {
+		t.Errorf("Explain(B) without an actor returned %+v, expected RejectUnauthorized", ex)
+	}
+
+	admin := WithActor(context.Background(), Actor{ID: "root", Roles: []string{"admin"}})
+	if ex := fsm.ExplainCtx(admin, CustomStateEnumB); !ex.Allowed {
+		t.Errorf("ExplainCtx(B) for an admin returned %+v, expected it to be allowed", ex)
+	}
+
+	fsm.SetAuthorizer(AuthorizerFunc[CustomStateEnum](func(ctx context.Context, actor Actor, from, to CustomStateEnum) error {
+		return errors.New("change freeze")
+	}))
+	if ex := fsm.ExplainCtx(admin, CustomStateEnumB); ex.Reason != RejectUnauthorized {
+		t.Errorf("ExplainCtx(B) returned %+v, expected the authorizer to reject it", ex)
+	}
+
+	fsm.SetAuthorizer(nil)
+	fsm.SetActorRateLimit("root", 1, time.Hour)
+	fsm.TransitionCtx(admin, CustomStateEnumB, nil)
+	if ex := fsm.ExplainCtx(admin, CustomStateEnumA); ex.Reason != RejectThrottled || !errors.Is(ex.Err, ErrThrottled) {
+		t.Errorf("ExplainCtx(A) returned %+v, expected RejectThrottled", ex)
+	}
+}
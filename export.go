@@ -0,0 +1,28 @@
+package statetrooper
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+)
+
+// ExportHistoryCompressed writes the history to w as gzip-compressed NDJSON, one transition per line
+// Transitions are encoded and compressed as they are written rather than buffered in full
+// Metadata is redacted according to the rules set with SetRedactionRules
+func (fsm *FSM[T]) ExportHistoryCompressed(w io.Writer) error {
+	fsm.mu.Lock()
+	history := fsm.redact(append([]Transition[T](nil), fsm.transitions...))
+	fsm.mu.Unlock()
+
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+
+	for i := range history {
+		if err := enc.Encode(&history[i]); err != nil {
+			zw.Close()
+			return err
+		}
+	}
+
+	return zw.Close()
+}
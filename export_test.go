@@ -0,0 +1,38 @@
+package statetrooper
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+)
+
+func Test_exportHistoryCompressed(t *testing.T) {
+	fsm := newPingPongFSM()
+	pingPong(fsm, 3)
+
+	var buf bytes.Buffer
+	if err := fsm.ExportHistoryCompressed(&buf); err != nil {
+		t.Fatalf("ExportHistoryCompressed() returned an error: %v", err)
+	}
+
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() returned an error: %v", err)
+	}
+
+	var history []Transition[CustomStateEnum]
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var tr Transition[CustomStateEnum]
+		if err := json.Unmarshal(scanner.Bytes(), &tr); err != nil {
+			t.Fatalf("Line %q is not a transition: %v", scanner.Text(), err)
+		}
+		history = append(history, tr)
+	}
+
+	if len(history) != 3 || history[2].FromState != CustomStateEnumA || history[2].ToState != CustomStateEnumB {
+		t.Errorf("Exported history is %v", history)
+	}
+}
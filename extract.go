@@ -0,0 +1,40 @@
+package statetrooper
+
+import "context"
+
+// ContextExtractor returns metadata lifted from the context of a transition, such as a request ID,
+// tenant ID or authenticated subject
+type ContextExtractor func(ctx context.Context) map[string]string
+
+// SetContextExtractor sets the extractor whose metadata is added to every transition made with
+// TransitionCtx, so audit fields don't depend on each caller copying them into the metadata
+// Metadata passed by the caller takes precedence. The extractor runs while the FSM is locked
+// and must not call back into the FSM. A nil extractor disables extraction
+func (fsm *FSM[T]) SetContextExtractor(extractor ContextExtractor) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.contextExtractor = extractor
+}
+
+// tag returns metadata with the FSM's metadata tags and the values extracted from ctx added,
+// leaving the caller's map untouched. Keys set by the caller take precedence. The caller must hold the lock
+func (fsm *FSM[T]) tag(ctx context.Context, metadata map[string]string) map[string]string {
+	var extracted map[string]string
+	if fsm.contextExtractor != nil {
+		extracted = fsm.contextExtractor(ctx)
+	}
+
+	if len(fsm.metadataTags) == 0 && len(extracted) == 0 {
+		return metadata
+	}
+
+	tagged := make(map[string]string, len(metadata)+len(fsm.metadataTags)+len(extracted))
+	for _, m := range []map[string]string{fsm.metadataTags, extracted, metadata} {
+		for k, v := range m {
+			tagged[k] = v
+		}
+	}
+
+	return tagged
+}
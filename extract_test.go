@@ -0,0 +1,48 @@
+package statetrooper
+
+import (
+	"context"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func Test_contextExtractor(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetContextExtractor(func(ctx context.Context) map[string]string {
+		id, ok := ctx.Value(requestIDKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return map[string]string{"request_id": id, "tenant": "acme"}
+	})
+
+	var hooked Transition[CustomStateEnum]
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		hooked = tr
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	metadata := map[string]string{"tenant": "override"}
+	fsm.TransitionCtx(ctx, CustomStateEnumB, metadata)
+
+	expected := map[string]string{"request_id": "req-1", "tenant": "override"}
+	if got := fsm.Transitions()[0].Metadata; len(got) != 2 || got["request_id"] != "req-1" || got["tenant"] != "override" {
+		t.Errorf("Recorded metadata is %v, expected %v", got, expected)
+	}
+
+	if hooked.Metadata["request_id"] != "req-1" {
+		t.Errorf("Hook received metadata %v, expected the extracted request ID", hooked.Metadata)
+	}
+
+	if len(metadata) != 1 {
+		t.Errorf("Extraction modified the caller's metadata: %v", metadata)
+	}
+
+	// Nothing is added when the context carries no values
+	fsm.Transition(CustomStateEnumA, nil)
+	if got := fsm.Transitions()[1].Metadata; got != nil {
+		t.Errorf("Recorded metadata is %v, expected none", got)
+	}
+}
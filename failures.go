@@ -0,0 +1,33 @@
+package statetrooper
+
+import "context"
+
+// SetRecordFailures sets whether rejected transition attempts are recorded in the history
+// A recorded attempt has Failed set and the rejection in Error. Its ToState is the requested target,
+// but the FSM stays in FromState
+func (fsm *FSM[T]) SetRecordFailures(record bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.recordFailures = record
+}
+
+// recordFailure records a rejected attempt to transition to targetState if failures are recorded
+// The caller must hold the lock
+func (fsm *FSM[T]) recordFailure(ctx context.Context, targetState T, metadata map[string]string, err error) {
+	if !fsm.recordFailures {
+		return
+	}
+
+	tn := fsm.timeNow()
+	actor, _ := ActorFromContext(ctx)
+	fsm.recordTransition(&Transition[T]{
+		FromState: fsm.currentState,
+		ToState:   targetState,
+		Timestamp: &tn,
+		Metadata:  metadata,
+		Actor:     actor.ID,
+		Failed:    true,
+		Error:     err.Error(),
+	})
+}
@@ -0,0 +1,87 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_recordFailures(t *testing.T) {
+	errNotPaid := errors.New("not paid")
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+	fsm.AddGuard(CustomStateEnumB, CustomStateEnumC, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errNotPaid
+	})
+
+	// Failures are not recorded by default
+	fsm.Transition(CustomStateEnumC, nil)
+	if len(fsm.Transitions()) != 0 {
+		t.Fatalf("Rejected attempt was recorded without SetRecordFailures")
+	}
+
+	fsm.SetRecordFailures(true)
+	fsm.Transition(CustomStateEnumC, map[string]string{"by": "jane"})
+	fsm.Transition(CustomStateEnumB, nil)
+	_, err := fsm.Transition(CustomStateEnumC, nil)
+
+	history := fsm.Transitions()
+	if len(history) != 3 {
+		t.Fatalf("History has %d entries, expected 3: %v", len(history), history)
+	}
+
+	if !history[0].Failed || history[0].ToState != CustomStateEnumC || history[0].Metadata["by"] != "jane" || history[0].Error == "" {
+		t.Errorf("First entry is %+v, expected a failed attempt to C", history[0])
+	}
+
+	if history[1].Failed {
+		t.Errorf("Successful transition is marked as failed")
+	}
+
+	if !history[2].Failed || history[2].Error != err.Error() {
+		t.Errorf("Last entry is %+v, expected the guard rejection %q", history[2], err)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumB {
+		t.Errorf("Current state is %v, expected B", fsm.CurrentState())
+	}
+
+	// A failed attempt does not change the state replayed from the history
+	if state, _ := fsm.ReplayTo(1); state != CustomStateEnumA {
+		t.Errorf("ReplayTo(1) returned %v, expected A", state)
+	}
+}
+
+func Test_recordCancelledTransitions(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetRecordFailures(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	state, err := fsm.TransitionCtx(ctx, CustomStateEnumB, nil)
+	if !errors.Is(err, context.Canceled) || state != CustomStateEnumA {
+		t.Fatalf("TransitionCtx cancelled during a guard returned %v, %v, expected A and context.Canceled", state, err)
+	}
+
+	if _, err := fsm.TransitionCtx(ctx, CustomStateEnumB, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("TransitionCtx with a cancelled context returned %v, expected context.Canceled", err)
+	}
+
+	history := fsm.Transitions()
+	if len(history) != 2 {
+		t.Fatalf("History has %d entries, expected 2 cancelled attempts: %v", len(history), history)
+	}
+
+	for _, tr := range history {
+		if !tr.Failed || tr.ToState != CustomStateEnumB || tr.Error == "" {
+			t.Errorf("History entry is %+v, expected a failed attempt to B", tr)
+		}
+	}
+}
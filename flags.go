@@ -0,0 +1,40 @@
+package statetrooper
+
+// SetRuleEnabled enables or disables the rule from fromState to toState at runtime
+// A disabled rule is kept but treated as missing until it is enabled again, so a workflow
+// feature can be rolled back without redeploying. It replaces any flag set with SetRuleFlag
+func (fsm *FSM[T]) SetRuleEnabled(fromState T, toState T, enabled bool) {
+	if enabled {
+		fsm.SetRuleFlag(fromState, toState, nil)
+		return
+	}
+
+	fsm.SetRuleFlag(fromState, toState, func() bool { return false })
+}
+
+// SetRuleFlag binds the rule from fromState to toState to a flag, such as a lookup in a feature flag provider
+// The rule is only enabled while enabled returns true. It is called on every check of the rule while the FSM
+// is locked, so it must be fast and must not call back into the FSM. A nil flag enables the rule unconditionally
+func (fsm *FSM[T]) SetRuleFlag(fromState T, toState T, enabled func() bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	e := edge[T]{from: fromState, to: toState}
+
+	if enabled == nil {
+		delete(fsm.ruleFlags, e)
+		return
+	}
+
+	if fsm.ruleFlags == nil {
+		fsm.ruleFlags = make(map[edge[T]]func() bool)
+	}
+
+	fsm.ruleFlags[e] = enabled
+}
+
+// ruleEnabled reports whether the rule from fromState to toState is enabled. The caller must hold the lock
+func (fsm *FSM[T]) ruleEnabled(fromState T, toState T) bool {
+	enabled, ok := fsm.ruleFlags[edge[T]{from: fromState, to: toState}]
+	return !ok || enabled()
+}
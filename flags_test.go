@@ -0,0 +1,56 @@
+package statetrooper
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_ruleFlags(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+
+	fsm.SetRuleEnabled(CustomStateEnumA, CustomStateEnumB, false)
+
+	var trErr TransitionError[CustomStateEnum]
+	if _, err := fsm.Transition(CustomStateEnumB, nil); !errors.As(err, &trErr) {
+		t.Fatalf("Transition(B) returned %v, expected a TransitionError for a disabled rule", err)
+	}
+
+	if len(trErr.Allowed) != 1 || trErr.Allowed[0] != CustomStateEnumC {
+		t.Errorf("TransitionError lists %v as allowed, expected only C", trErr.Allowed)
+	}
+
+	if ex := fsm.Explain(CustomStateEnumB); ex.Reason != RejectDisabled {
+		t.Errorf("Explain(B) returned %+v, expected RejectDisabled", ex)
+	}
+
+	// Disabled rules are still configured
+	if err := fsm.Validate(); err != nil {
+		t.Errorf("Validate() returned %v", err)
+	}
+
+	fsm.SetRuleEnabled(CustomStateEnumA, CustomStateEnumB, true)
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Errorf("Transition(B) returned %v after enabling the rule", err)
+	}
+
+	// A flag is evaluated on every check
+	var flag atomic.Bool
+	fsm.SetRuleFlag(CustomStateEnumB, CustomStateEnumA, flag.Load)
+
+	if fsm.CanTransition(CustomStateEnumA) {
+		t.Errorf("CanTransition(A) returned true while the flag is off")
+	}
+
+	flag.Store(true)
+	if !fsm.CanTransition(CustomStateEnumA) {
+		t.Errorf("CanTransition(A) returned false while the flag is on")
+	}
+
+	fsm.SetRuleFlag(CustomStateEnumD, CustomStateEnumA, flag.Load)
+	if err := fsm.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Validate() returned %v for a flag on a missing rule, expected ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,49 @@
+package statetrooper
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// CompactStringTemplate renders the FSM on a single line, suitable for structured logs
+var CompactStringTemplate = template.Must(template.New("compact").Parse(
+	`state={{.CurrentState}} transitions={{len .Transitions}}` +
+		`{{with .LastTransition}} last={{.FromState}}->{{.ToState}}{{end}}`))
+
+// StringData is the data a String template is executed with
+type StringData[T comparable] struct {
+	CurrentState   T
+	Rules          map[T][]T
+	Transitions    []Transition[T]
+	LastTransition *Transition[T]
+}
+
+// SetStringTemplate sets the template used by String, such as CompactStringTemplate
+// The template is executed with a StringData. Passing nil restores the default multi-line format
+func (fsm *FSM[T]) SetStringTemplate(tmpl *template.Template) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.stringTemplate = tmpl
+}
+
+// formatString renders the FSM with its string template. The caller must hold the lock
+func (fsm *FSM[T]) formatString() string {
+	data := StringData[T]{
+		CurrentState: fsm.currentState,
+		Rules:        fsm.ruleset,
+		Transitions:  fsm.transitions,
+	}
+
+	if n := len(fsm.transitions); n > 0 {
+		data.LastTransition = &fsm.transitions[n-1]
+	}
+
+	var b strings.Builder
+	if err := fsm.stringTemplate.Execute(&b, data); err != nil {
+		return fmt.Sprintf("%%!v(statetrooper: %v)", err)
+	}
+
+	return b.String()
+}
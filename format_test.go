@@ -0,0 +1,32 @@
+package statetrooper
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func Test_stringTemplate(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	fsm.SetStringTemplate(CompactStringTemplate)
+	if s := fsm.String(); s != "state=A transitions=0" {
+		t.Errorf("String() returned %q", s)
+	}
+
+	fsm.Transition(CustomStateEnumB, nil)
+	if s := fsm.String(); s != "state=B transitions=1 last=A->B" {
+		t.Errorf("String() returned %q", s)
+	}
+
+	fsm.SetStringTemplate(template.Must(template.New("custom").Parse(`{{.CurrentState}} {{.Missing}}`)))
+	if s := fsm.String(); !strings.HasPrefix(s, "%!v(statetrooper:") {
+		t.Errorf("String() returned %q for a failing template", s)
+	}
+
+	fsm.SetStringTemplate(nil)
+	if s := fsm.String(); !strings.HasPrefix(s, "Current State: B\n") {
+		t.Errorf("String() returned %q after restoring the default format", s)
+	}
+}
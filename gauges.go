@@ -0,0 +1,79 @@
+package statetrooper
+
+import "context"
+
+// trackedEntity is the last known state of an entity counted by a Manager
+type trackedEntity[T comparable] struct {
+	fsm        *FSM[T]
+	state      T
+	removeHook func()
+}
+
+// Counts returns the number of registered entities in each state
+// Counts are maintained by post-commit hooks rather than by scanning every FSM, so with async hooks they may lag
+// briefly. Changes of state that bypass transitions, such as unmarshaling, are picked up by the next transition
+func (m *Manager[K, T]) Counts() map[T]int {
+	m.countsMu.Lock()
+	defer m.countsMu.Unlock()
+
+	return cloneMap(m.counts)
+}
+
+// track starts counting the state of an entity's FSM
+func (m *Manager[K, T]) track(id K, fsm *FSM[T]) {
+	// Each hook records the FSM's current state rather than the transition's target, so hooks that run
+	// late or out of order still leave the count correct
+	removeHook := fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[T]) error {
+		m.countsMu.Lock()
+		defer m.countsMu.Unlock()
+
+		if e, ok := m.tracked[id]; ok && e.fsm == fsm {
+			m.move(e, fsm.CurrentState())
+		}
+
+		return nil
+	})
+
+	m.countsMu.Lock()
+	defer m.countsMu.Unlock()
+
+	e := &trackedEntity[T]{fsm: fsm, state: fsm.CurrentState(), removeHook: removeHook}
+	m.tracked[id] = e
+	m.counts[e.state]++
+}
+
+// untrack stops counting the state of an entity's FSM
+func (m *Manager[K, T]) untrack(id K) {
+	m.countsMu.Lock()
+	e, ok := m.tracked[id]
+	if ok {
+		m.uncount(e.state)
+		delete(m.tracked, id)
+	}
+	m.countsMu.Unlock()
+
+	if ok {
+		e.removeHook()
+	}
+}
+
+// move updates the counts for an entity that is now in state. The caller must hold m.countsMu
+func (m *Manager[K, T]) move(e *trackedEntity[T], state T) {
+	if e.state == state {
+		return
+	}
+
+	m.uncount(e.state)
+	m.counts[state]++
+	e.state = state
+}
+
+// uncount decrements the count of a state, removing it once it reaches zero. The caller must hold m.countsMu
+func (m *Manager[K, T]) uncount(state T) {
+	if m.counts[state] <= 1 {
+		delete(m.counts, state)
+		return
+	}
+
+	m.counts[state]--
+}
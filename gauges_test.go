@@ -0,0 +1,51 @@
+package statetrooper
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_managerCounts(t *testing.T) {
+	manager := newOrderManager(t)
+
+	if counts := manager.Counts(); !reflect.DeepEqual(counts, map[string]int{"created": 3}) {
+		t.Errorf("Counts() returned %v, expected 3 created", counts)
+	}
+
+	manager.TransitionMany([]int{1, 2}, "picked", nil, AllOrNothing)
+	fsm, _ := manager.Get(1)
+	fsm.Transition("packed", nil)
+
+	if counts := manager.Counts(); !reflect.DeepEqual(counts, map[string]int{"created": 1, "picked": 1, "packed": 1}) {
+		t.Errorf("Counts() returned %v", counts)
+	}
+
+	manager.Remove(1)
+	fsm.Transition("created", nil)
+
+	if counts := manager.Counts(); !reflect.DeepEqual(counts, map[string]int{"created": 1, "picked": 1}) {
+		t.Errorf("Counts() returned %v after removing an entity", counts)
+	}
+}
+
+func Test_managerCountsConcurrent(t *testing.T) {
+	manager := NewManager[int, CustomStateEnum]()
+
+	var wg sync.WaitGroup
+	for id := 0; id < 20; id++ {
+		fsm := newPingPongFSM()
+		manager.Add(id, fsm)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pingPong(fsm, 51)
+		}()
+	}
+	wg.Wait()
+
+	if counts := manager.Counts(); !reflect.DeepEqual(counts, map[CustomStateEnum]int{CustomStateEnumB: 20}) {
+		t.Errorf("Counts() returned %v, expected 20 in B", counts)
+	}
+}
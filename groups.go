@@ -0,0 +1,118 @@
+package statetrooper
+
+import "sort"
+
+// AddStateGroup adds states to the named group, so rules can be declared for the whole group with AddGroupRule
+// A state may belong to several groups
+func (fsm *FSM[T]) AddStateGroup(name string, states ...T) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.sealed {
+		return ErrSealed
+	}
+
+	for i := range states {
+		if err := fsm.checkRegistered(&states[i]); err != nil {
+			return err
+		}
+	}
+
+	if fsm.groups == nil {
+		fsm.groups = make(map[string][]T)
+	}
+
+	for _, state := range states {
+		if !contains(fsm.groups[name], state) {
+			fsm.groups[name] = append(fsm.groups[name], state)
+		}
+	}
+
+	return nil
+}
+
+// GroupStatesBy adds each of states to the group named by key, such as a Group field of a struct state type
+func (fsm *FSM[T]) GroupStatesBy(key func(state T) string, states ...T) error {
+	for _, state := range states {
+		if err := fsm.AddStateGroup(key(state), state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Group returns the states of the named group in the order they were added
+func (fsm *FSM[T]) Group(name string) []T {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return append([]T(nil), fsm.groups[name]...)
+}
+
+// AddGroupRule declares rules from every state of the named group to each of toState, such as
+// "any dropship state -> canceled". Group rules are expanded into regular rules by Seal, so they also
+// cover states added to the group later. Rules that already exist and rules from a state to itself are skipped
+func (fsm *FSM[T]) AddGroupRule(group string, toState ...T) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.sealed {
+		return ErrSealed
+	}
+
+	for i := range toState {
+		if err := fsm.checkRegistered(&toState[i]); err != nil {
+			return err
+		}
+	}
+
+	if fsm.groupRules == nil {
+		fsm.groupRules = make(map[string][]T)
+	}
+
+	fsm.groupRules[group] = append(fsm.groupRules[group], toState...)
+
+	return nil
+}
+
+// Seal expands the group rules into regular rules, validates the configuration as Validate does and, if it is
+// valid, freezes the rules: AddRule, AddRules, AddStateGroup and AddGroupRule then return ErrSealed
+// If the configuration is invalid, the errors are returned and the FSM is left unchanged and unsealed
+func (fsm *FSM[T]) Seal() error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.sealed {
+		return ErrSealed
+	}
+
+	groups := make([]string, 0, len(fsm.groupRules))
+	for group := range fsm.groupRules {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	ruleset := cloneMapOfSlices(fsm.ruleset)
+	for _, group := range groups {
+		for _, from := range fsm.groups[group] {
+			for _, to := range fsm.groupRules[group] {
+				if to != from && !contains(ruleset[from], to) {
+					ruleset[from] = append(ruleset[from], to)
+				}
+			}
+		}
+	}
+
+	previous, shared := fsm.ruleset, fsm.sharedRules
+	fsm.ruleset, fsm.sharedRules = ruleset, false
+
+	if err := fsm.validate(); err != nil {
+		fsm.ruleset, fsm.sharedRules = previous, shared
+		return err
+	}
+
+	fsm.sealed = true
+
+	return nil
+}
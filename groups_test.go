@@ -0,0 +1,79 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_groupRules(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	// A, B and D are active states that can all be canceled into C
+	group := func(state CustomStateEnum) string {
+		if state == CustomStateEnumC {
+			return "closed"
+		}
+		return "active"
+	}
+	if err := fsm.GroupStatesBy(group, CustomStateEnumA, CustomStateEnumB, CustomStateEnumC); err != nil {
+		t.Fatalf("GroupStatesBy() returned an error: %v", err)
+	}
+	if err := fsm.AddGroupRule("active", CustomStateEnumC); err != nil {
+		t.Fatalf("AddGroupRule() returned an error: %v", err)
+	}
+
+	// States added after the group rule are covered too
+	fsm.AddStateGroup("active", CustomStateEnumD)
+
+	if members := fsm.Group("active"); !reflect.DeepEqual(members, []CustomStateEnum{CustomStateEnumA, CustomStateEnumB, CustomStateEnumD}) {
+		t.Errorf("Group() returned %v, expected [A B D]", members)
+	}
+
+	if err := fsm.Seal(); err != nil {
+		t.Fatalf("Seal() returned an error: %v", err)
+	}
+
+	expected := map[CustomStateEnum][]CustomStateEnum{
+		CustomStateEnumA: {CustomStateEnumB, CustomStateEnumC},
+		CustomStateEnumB: {CustomStateEnumC},
+		CustomStateEnumD: {CustomStateEnumC},
+	}
+	if rules := fsm.Rules(); !reflect.DeepEqual(rules, expected) {
+		t.Errorf("Rules() returned %v after sealing, expected %v", rules, expected)
+	}
+
+	if err := fsm.AddRule(CustomStateEnumC, CustomStateEnumA); !errors.Is(err, ErrSealed) {
+		t.Errorf("AddRule() returned %v after sealing, expected ErrSealed", err)
+	}
+	if err := fsm.AddGroupRule("active", CustomStateEnumA); !errors.Is(err, ErrSealed) {
+		t.Errorf("AddGroupRule() returned %v after sealing, expected ErrSealed", err)
+	}
+	if err := fsm.Seal(); !errors.Is(err, ErrSealed) {
+		t.Errorf("Seal() returned %v when already sealed, expected ErrSealed", err)
+	}
+}
+
+func Test_sealInvalid(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddStateGroup("active", CustomStateEnumA)
+	fsm.AddGroupRule("active", CustomStateEnumC)
+	fsm.AddGroupRule("missing", CustomStateEnumC)
+
+	err := fsm.Seal()
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), `unknown group "missing"`) {
+		t.Errorf("Seal() returned %v, expected the unknown group to be reported", err)
+	}
+
+	// Nothing is expanded or sealed when the configuration is invalid
+	if rules := fsm.Rules(); len(rules[CustomStateEnumA]) != 1 {
+		t.Errorf("Rules() returned %v after a failed seal, expected them unchanged", rules)
+	}
+	if err := fsm.AddRule(CustomStateEnumB, CustomStateEnumC); err != nil {
+		t.Errorf("AddRule() returned %v after a failed seal, expected the FSM to stay unsealed", err)
+	}
+}
@@ -0,0 +1,84 @@
+package statetrooper
+
+import "context"
+
+// Guard is a condition evaluated before a transition is applied
+// Returning a non-nil error rejects the transition and leaves the current state unchanged
+// Guards run while the FSM is locked and must not call back into the FSM
+// ctx is derived from the context passed to TransitionCtx and is bounded by the hook timeout, if set
+type Guard[T comparable] func(ctx context.Context, tr Transition[T]) error
+
+// edge identifies a single rule from one state to another
+type edge[T comparable] struct {
+	from T
+	to   T
+}
+
+// AddGuard adds a guard to the transition from fromState to toState
+// Multiple guards on the same transition are evaluated in the order they were added
+func (fsm *FSM[T]) AddGuard(fromState T, toState T, guard Guard[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.guards == nil {
+		fsm.guards = make(map[edge[T]][]Guard[T])
+	}
+
+	e := edge[T]{from: fromState, to: toState}
+	fsm.guards[e] = append(fsm.guards[e], guard)
+}
+
+// checkGuards evaluates the guards for the given transition, returning a GuardError for the first rejection
+// During a replay the recorded outcome is used instead of calling the guards
+func (fsm *FSM[T]) checkGuards(ctx context.Context, tr *Transition[T]) error {
+	if replayed, err := fsm.replayedGuards(ctx); replayed {
+		return guardError(tr, err)
+	}
+
+	guards := fsm.guardsFor(tr.FromState, tr.ToState)
+	if len(guards) == 0 {
+		return nil
+	}
+
+	_, err := fsm.evaluateGuards(ctx, tr, guards)
+
+	fsm.recordGuards(ctx, err)
+
+	return guardError(tr, err)
+}
+
+// evaluateGuards calls guards on tr in order until one rejects it, returning the rejecting guard's
+// position among them and its error, or -1 and nil if all of them pass
+func (fsm *FSM[T]) evaluateGuards(ctx context.Context, tr *Transition[T], guards []Guard[T]) (int, error) {
+	for i, guard := range guards {
+		if err := callWithTimeout(ctx, fsm.hookTimeout, *tr, guard); err != nil {
+			return i, err
+		}
+	}
+
+	return -1, nil
+}
+
+// guardsFor returns the guards of a transition from fromState to toState in the order they are evaluated,
+// including the guards on rules from a composite state or targeting one that apply to it, see ruleEdges
+func (fsm *FSM[T]) guardsFor(fromState T, toState T) []Guard[T] {
+	var guards []Guard[T]
+	for _, e := range fsm.configuredEdges(len(fsm.guards), fromState, toState) {
+		guards = append(guards, fsm.guards[e]...)
+	}
+
+	return guards
+}
+
+// guardError wraps a guard rejection in a GuardError, returning nil if err is nil
+func guardError[T comparable](tr *Transition[T], err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return GuardError[T]{
+		FromState: tr.FromState,
+		ToState:   tr.ToState,
+		Err:       err,
+	}
+}
@@ -0,0 +1,54 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_guards(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	errNotReady := errors.New("not ready")
+	ready := false
+
+	var evaluated []string
+
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		evaluated = append(evaluated, "first")
+		return nil
+	})
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		evaluated = append(evaluated, "second")
+		if !ready {
+			return errNotReady
+		}
+		return nil
+	})
+
+	_, err := fsm.Transition(CustomStateEnumB, nil)
+	if !errors.Is(err, errNotReady) {
+		t.Errorf("Transition(%v) returned %v, expected %v", CustomStateEnumB, err, errNotReady)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumA || len(fsm.Transitions()) != 0 {
+		t.Errorf("Rejected transition modified the FSM")
+	}
+
+	if len(evaluated) != 2 || evaluated[0] != "first" || evaluated[1] != "second" {
+		t.Errorf("Guards evaluated in unexpected order: %v", evaluated)
+	}
+
+	ready = true
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Errorf("Transition(%v) returned an error: %v", CustomStateEnumB, err)
+	}
+
+	// Guards only apply to the transition they were added for
+	if _, err := fsm.Transition(CustomStateEnumC, nil); err != nil {
+		t.Errorf("Transition(%v) returned an error: %v", CustomStateEnumC, err)
+	}
+}
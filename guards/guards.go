@@ -0,0 +1,93 @@
+// Package guards provides composable guards for statetrooper FSMs.
+package guards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hishamk/statetrooper"
+)
+
+// ErrNotSatisfied is returned by Not when the wrapped guard passes
+var ErrNotSatisfied = errors.New("guard condition not satisfied")
+
+// All returns a guard that passes only if every given guard passes
+// Guards are evaluated in order and evaluation stops at the first rejection
+func All[T comparable](guards ...statetrooper.Guard[T]) statetrooper.Guard[T] {
+	return func(ctx context.Context, tr statetrooper.Transition[T]) error {
+		for _, guard := range guards {
+			if err := guard(ctx, tr); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// Any returns a guard that passes if at least one of the given guards passes
+// If every guard rejects, the returned error joins all of their errors
+func Any[T comparable](guards ...statetrooper.Guard[T]) statetrooper.Guard[T] {
+	return func(ctx context.Context, tr statetrooper.Transition[T]) error {
+		var errs []error
+
+		for _, guard := range guards {
+			err := guard(ctx, tr)
+			if err == nil {
+				return nil
+			}
+
+			errs = append(errs, err)
+		}
+
+		if len(errs) == 0 {
+			return fmt.Errorf("%w: no guards given", ErrNotSatisfied)
+		}
+
+		return errors.Join(errs...)
+	}
+}
+
+// Not returns a guard that passes only if the given guard rejects
+func Not[T comparable](guard statetrooper.Guard[T]) statetrooper.Guard[T] {
+	return func(ctx context.Context, tr statetrooper.Transition[T]) error {
+		if guard(ctx, tr) == nil {
+			return ErrNotSatisfied
+		}
+
+		return nil
+	}
+}
+
+// MetadataEquals returns a guard that passes if the transition metadata contains key with the given value
+func MetadataEquals[T comparable](key string, value string) statetrooper.Guard[T] {
+	return func(ctx context.Context, tr statetrooper.Transition[T]) error {
+		if v, ok := tr.Metadata[key]; !ok || v != value {
+			return fmt.Errorf("%w: metadata %q is not %q", ErrNotSatisfied, key, value)
+		}
+
+		return nil
+	}
+}
+
+// InState returns a guard that passes if the FSM of entity id in manager is in one of the given states,
+// e.g. only allowing an order to ship once its payment has been captured
+// The other FSM is locked briefly while the guard runs, so two FSMs must not guard on each other
+func InState[T comparable, K comparable, U comparable](manager *statetrooper.Manager[K, U], id K, states ...U) statetrooper.Guard[T] {
+	return func(ctx context.Context, tr statetrooper.Transition[T]) error {
+		fsm, ok := manager.Get(id)
+		if !ok {
+			return fmt.Errorf("%w: %v", statetrooper.ErrEntityNotFound, id)
+		}
+
+		current := fsm.CurrentState()
+		for _, state := range states {
+			if current == state {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %v is in %v, expected one of %v", ErrNotSatisfied, id, current, states)
+	}
+}
@@ -0,0 +1,98 @@
+package guards
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hishamk/statetrooper"
+)
+
+func pass(ctx context.Context, tr statetrooper.Transition[string]) error { return nil }
+
+func reject(ctx context.Context, tr statetrooper.Transition[string]) error {
+	return errors.New("rejected")
+}
+
+func TestCombinators(t *testing.T) {
+	tr := statetrooper.Transition[string]{
+		FromState: "packed",
+		ToState:   "shipped",
+		Metadata:  map[string]string{"carrier": "Aramex"},
+	}
+
+	tests := []struct {
+		name     string
+		guard    statetrooper.Guard[string]
+		expected bool
+	}{
+		{"All pass", All[string](pass, pass), true},
+		{"All reject", All[string](pass, reject), false},
+		{"All empty", All[string](), true},
+		{"Any pass", Any[string](reject, pass), true},
+		{"Any reject", Any[string](reject, reject), false},
+		{"Any empty", Any[string](), false},
+		{"Not pass", Not[string](reject), true},
+		{"Not reject", Not[string](pass), false},
+		{"MetadataEquals match", MetadataEquals[string]("carrier", "Aramex"), true},
+		{"MetadataEquals mismatch", MetadataEquals[string]("carrier", "DHL"), false},
+		{"MetadataEquals missing", MetadataEquals[string]("tracking_number", ""), false},
+		{"Nested", All(Any[string](reject, pass), Not(MetadataEquals[string]("carrier", "DHL"))), true},
+	}
+
+	for _, test := range tests {
+		err := test.guard(context.Background(), tr)
+		if (err == nil) != test.expected {
+			t.Errorf("%s: guard returned %v, expected pass = %t", test.name, err, test.expected)
+		}
+	}
+}
+
+func TestGuardedTransition(t *testing.T) {
+	fsm := statetrooper.NewFSM[string]("packed", 10)
+	fsm.AddRule("packed", "shipped")
+	fsm.AddGuard("packed", "shipped", MetadataEquals[string]("carrier", "Aramex"))
+
+	_, err := fsm.Transition("shipped", nil)
+
+	var gErr statetrooper.GuardError[string]
+	if !errors.As(err, &gErr) || !errors.Is(err, ErrNotSatisfied) {
+		t.Errorf("Transition without metadata returned %v, expected a GuardError wrapping ErrNotSatisfied", err)
+	}
+
+	if fsm.CurrentState() != "packed" {
+		t.Errorf("Rejected transition changed the current state to %v", fsm.CurrentState())
+	}
+
+	if _, err := fsm.Transition("shipped", map[string]string{"carrier": "Aramex"}); err != nil {
+		t.Errorf("Transition with metadata returned an error: %v", err)
+	}
+}
+
+func TestInState(t *testing.T) {
+	type paymentState string
+
+	payments := statetrooper.NewManager[string, paymentState]()
+	payment := statetrooper.NewFSM[paymentState]("authorized", 10)
+	payment.AddRule("authorized", "captured")
+	payments.Add("payment-1", payment)
+
+	order := statetrooper.NewFSM[string]("packed", 10)
+	order.AddRule("packed", "shipped")
+	order.AddGuard("packed", "shipped", InState[string](payments, "payment-1", paymentState("captured")))
+
+	if _, err := order.Transition("shipped", nil); !errors.Is(err, ErrNotSatisfied) {
+		t.Errorf("Transition() returned %v, expected ErrNotSatisfied", err)
+	}
+
+	payment.Transition("captured", nil)
+
+	if _, err := order.Transition("shipped", nil); err != nil {
+		t.Errorf("Transition() returned an error: %v", err)
+	}
+
+	missing := InState[string](payments, "payment-2", paymentState("captured"))
+	if err := missing(context.Background(), statetrooper.Transition[string]{}); !errors.Is(err, statetrooper.ErrEntityNotFound) {
+		t.Errorf("Guard returned %v, expected ErrEntityNotFound", err)
+	}
+}
@@ -0,0 +1,144 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Kinds of problems reported by HealthCheck
+const (
+	// HealthDwellExceeded is reported when the FSM has stayed in its current state longer than the state's dwell threshold
+	HealthDwellExceeded = "dwell_exceeded"
+	// HealthIdleExceeded is reported when the FSM has had no transition or touch for longer than the state's idle threshold
+	HealthIdleExceeded = "idle_exceeded"
+	// HealthUndeclaredState is reported when the current state is not registered or not referenced by any rule
+	HealthUndeclaredState = "undeclared_state"
+)
+
+// HealthProblem describes a single problem found by HealthCheck
+type HealthProblem struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// HealthReport is the result of a health check
+type HealthReport struct {
+	Name     string          `json:"name,omitempty"`
+	EntityID string          `json:"entity_id,omitempty"`
+	Healthy  bool            `json:"healthy"`
+	State    string          `json:"state"`
+	Dwell    time.Duration   `json:"dwell"`
+	Idle     time.Duration   `json:"idle"`
+	Problems []HealthProblem `json:"problems,omitempty"`
+}
+
+// HealthChecker is implemented by anything that can report its health, such as an FSM
+type HealthChecker interface {
+	HealthCheck() HealthReport
+}
+
+// SetDwellThreshold sets how long the FSM may stay in state before HealthCheck reports it as wedged
+// A zero threshold removes the check
+func (fsm *FSM[T]) SetDwellThreshold(state T, threshold time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if threshold <= 0 {
+		delete(fsm.dwellThresholds, state)
+		return
+	}
+
+	if fsm.dwellThresholds == nil {
+		fsm.dwellThresholds = make(map[T]time.Duration)
+	}
+
+	fsm.dwellThresholds[state] = threshold
+}
+
+// HealthCheck reports whether the FSM has exceeded the dwell or idle threshold of its current state
+// or is in a state that is not declared by its registered states or rules
+func (fsm *FSM[T]) HealthCheck() HealthReport {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	tn := fsm.timeNow()
+	report := HealthReport{
+		Name:     fsm.name,
+		EntityID: fsm.entityID,
+		State:    DisplayName(fsm.currentState, ""),
+		Dwell:    tn.Sub(fsm.enteredAt),
+		Idle:     tn.Sub(fsm.lastActivity()),
+	}
+
+	if threshold, ok := fsm.dwellThresholds[fsm.currentState]; ok && report.Dwell > threshold {
+		report.Problems = append(report.Problems, HealthProblem{
+			Kind:   HealthDwellExceeded,
+			Detail: fmt.Sprintf("in %v for %v, threshold %v", fsm.currentState, report.Dwell, threshold),
+		})
+	}
+
+	if threshold, ok := fsm.idleThresholds[fsm.currentState]; ok && report.Idle > threshold {
+		report.Problems = append(report.Problems, HealthProblem{
+			Kind:   HealthIdleExceeded,
+			Detail: fmt.Sprintf("no activity in %v for %v, threshold %v", fsm.currentState, report.Idle, threshold),
+		})
+	}
+
+	if !fsm.declared(fsm.currentState) {
+		report.Problems = append(report.Problems, HealthProblem{
+			Kind:   HealthUndeclaredState,
+			Detail: fmt.Sprintf("%v is not a declared state", fsm.currentState),
+		})
+	}
+
+	report.Healthy = len(report.Problems) == 0
+
+	return report
+}
+
+// declared reports whether state is registered, or if no states are registered, whether any rule,
+// composite state or terminal state declaration refers to it
+func (fsm *FSM[T]) declared(state T) bool {
+	if fsm.states != nil {
+		_, ok := fsm.states[state]
+		return ok
+	}
+
+	if len(fsm.ruleset) == 0 {
+		return true
+	}
+
+	for from, targets := range fsm.ruleset {
+		if from == state || contains(targets, state) {
+			return true
+		}
+	}
+
+	_, isSubstate := fsm.parents[state]
+	_, isTerminal := fsm.terminals[state]
+
+	return isSubstate || isTerminal
+}
+
+// HealthHandler returns an HTTP handler reporting the health of the named checkers as a JSON object
+// It responds with 503 Service Unavailable if any checker is unhealthy, so it can back readiness probes
+func HealthHandler(checkers map[string]HealthChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reports := make(map[string]HealthReport, len(checkers))
+		status := http.StatusOK
+
+		for name, checker := range checkers {
+			report := checker.HealthCheck()
+			if !report.Healthy {
+				status = http.StatusServiceUnavailable
+			}
+			reports[name] = report
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(reports)
+	})
+}
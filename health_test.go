@@ -0,0 +1,80 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_healthCheck(t *testing.T) {
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	fsm := NewFSM[string]("created", 10)
+	fsm.SetClock(func() time.Time { return now })
+	fsm.AddRule("created", "picked")
+	fsm.AddRule("picked", "packed")
+	fsm.SetDwellThreshold("picked", time.Hour)
+
+	fsm.Transition("picked", nil)
+
+	now = start.Add(30 * time.Minute)
+	if report := fsm.HealthCheck(); !report.Healthy || report.Dwell != 30*time.Minute {
+		t.Errorf("HealthCheck() returned %+v, expected a healthy report with 30m dwell", report)
+	}
+
+	now = start.Add(2 * time.Hour)
+	report := fsm.HealthCheck()
+	if report.Healthy || len(report.Problems) != 1 || report.Problems[0].Kind != HealthDwellExceeded {
+		t.Errorf("HealthCheck() returned %+v, expected a dwell problem", report)
+	}
+
+	fsm.SetDwellThreshold("picked", 0)
+	if report := fsm.HealthCheck(); !report.Healthy {
+		t.Errorf("HealthCheck() returned %+v after removing the threshold", report)
+	}
+
+	// A state restored from a snapshot that the rules no longer mention
+	if err := json.Unmarshal([]byte(`{"current_state":"staged","transitions":[]}`), fsm); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	report = fsm.HealthCheck()
+	if report.Healthy || report.Problems[0].Kind != HealthUndeclaredState {
+		t.Errorf("HealthCheck() returned %+v, expected an undeclared state problem", report)
+	}
+}
+
+func Test_healthHandler(t *testing.T) {
+	healthy := NewFSM[string]("created", 10)
+	healthy.AddRule("created", "picked")
+
+	stuck := NewFSM[string]("created", 10)
+	stuck.AddRule("created", "picked")
+	stuck.SetDwellThreshold("created", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	tests := []struct {
+		checkers map[string]HealthChecker
+		status   int
+	}{
+		{map[string]HealthChecker{"orders": healthy}, http.StatusOK},
+		{map[string]HealthChecker{"orders": healthy, "returns": stuck}, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		HealthHandler(tt.checkers).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		if rec.Code != tt.status {
+			t.Errorf("HealthHandler responded with %d, expected %d", rec.Code, tt.status)
+		}
+
+		var reports map[string]HealthReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &reports); err != nil || len(reports) != len(tt.checkers) {
+			t.Errorf("HealthHandler responded with %s, %v", rec.Body.String(), err)
+		}
+	}
+}
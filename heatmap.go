@@ -0,0 +1,125 @@
+package statetrooper
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HeatmapCell holds the usage of a single edge of the workflow graph
+// Dwell percentiles are the time spent in the source state before taking the edge, in seconds
+type HeatmapCell struct {
+	FromState string  `json:"from_state"`
+	ToState   string  `json:"to_state"`
+	Count     int     `json:"count"`
+	P50       float64 `json:"p50_seconds"`
+	P90       float64 `json:"p90_seconds"`
+	P99       float64 `json:"p99_seconds"`
+}
+
+// Heatmap is the edge usage of a set of FSMs, ordered by descending count
+// It can be encoded as JSON directly or written as CSV with WriteCSV
+type Heatmap []HeatmapCell
+
+// BuildHeatmap aggregates edge counts and dwell-time percentiles across histories, such as the
+// Transitions of many FSMs. States are identified by their string form and failed attempts are ignored
+func BuildHeatmap[T comparable](histories [][]Transition[T]) Heatmap {
+	counts := make(map[edge[string]]int)
+	dwells := make(map[edge[string]][]time.Duration)
+
+	for _, history := range histories {
+		history = succeeded(history)
+		for i, tr := range history {
+			e := edge[string]{from: toString(tr.FromState), to: toString(tr.ToState)}
+			counts[e]++
+
+			if i > 0 && tr.Timestamp != nil && history[i-1].Timestamp != nil {
+				dwells[e] = append(dwells[e], tr.Timestamp.Sub(*history[i-1].Timestamp))
+			}
+		}
+	}
+
+	heatmap := make(Heatmap, 0, len(counts))
+	for e, count := range counts {
+		d := dwells[e]
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+
+		heatmap = append(heatmap, HeatmapCell{
+			FromState: e.from,
+			ToState:   e.to,
+			Count:     count,
+			P50:       percentile(d, 50).Seconds(),
+			P90:       percentile(d, 90).Seconds(),
+			P99:       percentile(d, 99).Seconds(),
+		})
+	}
+
+	sort.Slice(heatmap, func(i, j int) bool {
+		a, b := heatmap[i], heatmap[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.FromState != b.FromState {
+			return a.FromState < b.FromState
+		}
+		return a.ToState < b.ToState
+	})
+
+	return heatmap
+}
+
+// percentile returns the nearest-rank percentile p of sorted durations, or 0 if there are none
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// WriteCSV writes the heatmap as CSV with a header row
+func (h Heatmap) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"from_state", "to_state", "count", "p50_seconds", "p90_seconds", "p99_seconds"}); err != nil {
+		return err
+	}
+
+	for _, c := range h {
+		record := []string{
+			c.FromState,
+			c.ToState,
+			strconv.Itoa(c.Count),
+			strconv.FormatFloat(c.P50, 'f', -1, 64),
+			strconv.FormatFloat(c.P90, 'f', -1, 64),
+			strconv.FormatFloat(c.P99, 'f', -1, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// succeeded returns history without failed attempts
+func succeeded[T comparable](history []Transition[T]) []Transition[T] {
+	filtered := make([]Transition[T], 0, len(history))
+	for _, tr := range history {
+		if !tr.Failed {
+			filtered = append(filtered, tr)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,48 @@
+package statetrooper
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_buildHeatmap(t *testing.T) {
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var histories [][]Transition[string]
+	for i := 1; i <= 10; i++ {
+		histories = append(histories, timedHistory(start, []string{"created", "picked", "packed"}, []int{0, i}))
+	}
+	histories = append(histories, timedHistory(start, []string{"created", "canceled"}, []int{0}))
+
+	heatmap := BuildHeatmap(histories)
+	if len(heatmap) != 3 {
+		t.Fatalf("BuildHeatmap() returned %d cells, expected 3", len(heatmap))
+	}
+
+	expected := HeatmapCell{FromState: "created", ToState: "picked", Count: 10}
+	if heatmap[0] != expected {
+		t.Errorf("First cell is %+v, expected %+v", heatmap[0], expected)
+	}
+
+	if c := heatmap[1]; c.FromState != "picked" || c.P50 != 300 || c.P90 != 540 || c.P99 != 600 {
+		t.Errorf("Second cell is %+v, expected picked -> packed with p50 300s, p90 540s and p99 600s", c)
+	}
+
+	var buf bytes.Buffer
+	if err := heatmap.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() returned an error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[2] != "picked,packed,10,300,540,600" {
+		t.Errorf("WriteCSV() wrote %q", buf.String())
+	}
+
+	data, err := json.Marshal(heatmap)
+	if err != nil || !strings.Contains(string(data), `"from_state":"created","to_state":"canceled","count":1`) {
+		t.Errorf("json.Marshal() returned %s, %v", data, err)
+	}
+}
@@ -0,0 +1,166 @@
+package statetrooper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// HookPhase identifies when a hook runs relative to a transition
+type HookPhase int
+
+const (
+	// PreCommit hooks run inside the critical section, after guards pass and before the state is updated
+	// or the transition is recorded. Returning an error aborts the transition with a HookError and leaves
+	// the FSM untouched, so pre-commit hooks must not perform side effects that cannot be undone
+	PreCommit HookPhase = iota
+	// PostCommit hooks run after the state has been updated, the transition recorded and the FSM unlocked
+	// They cannot abort the transition; errors are passed to the hook error handler or, in async mode,
+	// collected for FlushHooks. Side effects such as notifications belong here
+	PostCommit
+)
+
+// HookErrorHandler receives errors returned by post-commit hooks
+type HookErrorHandler[T comparable] func(tr Transition[T], err error)
+
+// Hook is a function run when a transition is performed
+// ctx is derived from the context passed to TransitionCtx and is bounded by the hook timeout, if set
+type Hook[T comparable] func(ctx context.Context, tr Transition[T]) error
+
+// registeredHook is a hook together with its ordering information
+type registeredHook[T comparable] struct {
+	hook     Hook[T]
+	priority int
+}
+
+// AddHook registers a hook for the given phase and returns a function that deregisters it
+// Hooks with a higher priority run first; hooks with equal priority run in the order they were added
+// Pre-commit hooks run while the FSM is locked and must not call back into the FSM
+func (fsm *FSM[T]) AddHook(phase HookPhase, priority int, hook Hook[T]) (remove func()) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.hooks == nil {
+		fsm.hooks = make(map[HookPhase][]*registeredHook[T])
+	}
+
+	rh := &registeredHook[T]{hook: hook, priority: priority}
+
+	// Copy on write so hooks can be run from a snapshot without holding the lock
+	hooks := make([]*registeredHook[T], len(fsm.hooks[phase]), len(fsm.hooks[phase])+1)
+	copy(hooks, fsm.hooks[phase])
+	hooks = append(hooks, rh)
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].priority > hooks[j].priority
+	})
+	fsm.hooks[phase] = hooks
+
+	return func() {
+		fsm.mu.Lock()
+		defer fsm.mu.Unlock()
+
+		current := fsm.hooks[phase]
+		hooks := make([]*registeredHook[T], 0, len(current))
+		for _, h := range current {
+			if h != rh {
+				hooks = append(hooks, h)
+			}
+		}
+		fsm.hooks[phase] = hooks
+	}
+}
+
+// runPreCommitHooks runs the pre-commit hooks, returning a HookError for the first failure
+func (fsm *FSM[T]) runPreCommitHooks(ctx context.Context, tr *Transition[T]) error {
+	for _, h := range fsm.hooks[PreCommit] {
+		if err := callWithTimeout(ctx, fsm.hookTimeout, *tr, h.hook); err != nil {
+			return HookError[T]{
+				FromState: tr.FromState,
+				ToState:   tr.ToState,
+				Err:       err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// runPostCommitHooks runs the post-commit hooks for a committed transition
+// It must be called without holding the lock
+func (fsm *FSM[T]) runPostCommitHooks(ctx context.Context, tr *Transition[T]) {
+	fsm.mu.Lock()
+	hooks := fsm.hooks[PostCommit]
+	async := fsm.asyncHooks
+	handler := fsm.hookErrorHandler
+	timeout := fsm.hookTimeout
+	fsm.mu.Unlock()
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	// Async hooks outlive the call, so they keep the context's values but not its cancellation
+	if async != nil && async.submit(detachedContext{ctx}, timeout, hooks, *tr) {
+		return
+	}
+
+	for _, h := range hooks {
+		if err := callWithTimeout(ctx, timeout, *tr, h.hook); err != nil && handler != nil {
+			handler(*tr, err)
+		}
+	}
+}
+
+// SetHookTimeout sets the maximum time each guard and hook may run for
+// A guard or hook that exceeds it fails with context.DeadlineExceeded, so a misbehaving function cannot hold
+// the FSM's lock forever. It is abandoned, not cancelled: its context is done, but it keeps running in the
+// background after the FSM is unlocked and may overlap with later transitions, so it must not change shared
+// state without synchronizing. With a timeout, each guard and hook runs in its own goroutine and a panic
+// fails it with an error wrapping ErrHookPanic. Zero means no timeout, and guards and hooks run inline
+func (fsm *FSM[T]) SetHookTimeout(d time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.hookTimeout = d
+}
+
+// callWithTimeout calls fn inline if timeout is zero, and otherwise with a context bounded by timeout,
+// returning as soon as fn returns or the context is done. If the context is done first, fn is abandoned:
+// it keeps running in the background and its result is discarded. A panic in fn is returned as an error
+// wrapping ErrHookPanic rather than crashing the process from the background goroutine
+func callWithTimeout[T comparable](ctx context.Context, timeout time.Duration, tr Transition[T], fn func(context.Context, Transition[T]) error) error {
+	if timeout <= 0 {
+		return fn(ctx, tr)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("%w: %v", ErrHookPanic, r)
+			}
+		}()
+
+		result <- fn(ctx, tr)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetHookErrorHandler sets the handler that receives errors returned by post-commit hooks run inline
+// Without a handler these errors are discarded
+func (fsm *FSM[T]) SetHookErrorHandler(handler HookErrorHandler[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.hookErrorHandler = handler
+}
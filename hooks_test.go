@@ -0,0 +1,227 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_hookOrdering(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	var calls []string
+	hook := func(name string) Hook[CustomStateEnum] {
+		return func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	fsm.AddHook(PostCommit, 0, hook("audit"))
+	removeMetrics := fsm.AddHook(PostCommit, 10, hook("metrics"))
+	fsm.AddHook(PostCommit, 0, hook("notifications"))
+	fsm.AddHook(PreCommit, -5, hook("validate"))
+
+	fsm.Transition(CustomStateEnumB, nil)
+
+	expected := []string{"validate", "metrics", "audit", "notifications"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Hooks ran in order %v, expected %v", calls, expected)
+	}
+
+	removeMetrics()
+	removeMetrics()
+	calls = nil
+
+	fsm.Transition(CustomStateEnumC, nil)
+
+	expected = []string{"validate", "audit", "notifications"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Hooks ran in order %v after deregistration, expected %v", calls, expected)
+	}
+}
+
+func Test_beforeHookAborts(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	errAbort := errors.New("abort")
+	afterCalled := false
+
+	fsm.AddHook(PreCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errAbort
+	})
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		afterCalled = true
+		return nil
+	})
+
+	_, err := fsm.Transition(CustomStateEnumB, nil)
+
+	var hErr HookError[CustomStateEnum]
+	if !errors.As(err, &hErr) || !errors.Is(err, errAbort) {
+		t.Errorf("Transition returned %v, expected a HookError wrapping %v", err, errAbort)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumA || afterCalled {
+		t.Errorf("Aborted transition changed the state or ran post-commit hooks")
+	}
+}
+
+func Test_afterHookCanAccessFSM(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	var observed CustomStateEnum
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		observed = fsm.CurrentState()
+		return nil
+	})
+
+	fsm.Transition(CustomStateEnumB, nil)
+
+	if observed != CustomStateEnumB {
+		t.Errorf("Post-commit hook observed state %v, expected %v", observed, CustomStateEnumB)
+	}
+}
+
+func Test_postCommitHookErrors(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	errNotify := errors.New("notification failed")
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errNotify
+	})
+
+	var handled error
+	fsm.SetHookErrorHandler(func(tr Transition[CustomStateEnum], err error) {
+		handled = err
+	})
+
+	// Post-commit hook errors do not undo or fail the transition
+	newState, err := fsm.Transition(CustomStateEnumB, nil)
+	if err != nil || newState != CustomStateEnumB {
+		t.Errorf("Transition returned (%v, %v), expected (%v, nil)", newState, err, CustomStateEnumB)
+	}
+
+	if handled != errNotify {
+		t.Errorf("Hook error handler received %v, expected %v", handled, errNotify)
+	}
+}
+
+func Test_hookTimeout(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.SetHookTimeout(10 * time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	// A hook that ignores its context must not hold the lock past the timeout
+	remove := fsm.AddHook(PreCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		<-release
+		return nil
+	})
+
+	_, err := fsm.Transition(CustomStateEnumB, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Transition with a stuck hook returned %v, expected context.DeadlineExceeded", err)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumA {
+		t.Errorf("Timed out transition changed the state to %v", fsm.CurrentState())
+	}
+
+	remove()
+
+	type ctxKey struct{}
+	var requestID any
+
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		requestID = ctx.Value(ctxKey{})
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("guard context has no deadline")
+		}
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "req-1")
+	if _, err := fsm.TransitionCtx(ctx, CustomStateEnumB, nil); err != nil {
+		t.Errorf("TransitionCtx returned an error: %v", err)
+	}
+
+	if requestID != "req-1" {
+		t.Errorf("Guard context carried value %v, expected req-1", requestID)
+	}
+}
+
+func Test_hookPanicWithTimeout(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetHookTimeout(time.Second)
+	remove := fsm.AddHook(PreCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		panic("boom")
+	})
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); !errors.Is(err, ErrHookPanic) {
+		t.Errorf("Transition with a panicking hook returned %v, expected ErrHookPanic", err)
+	}
+
+	remove()
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil || fsm.CurrentState() != CustomStateEnumB {
+		t.Errorf("Transition after a panicking hook returned %v, expected the FSM to stay usable", err)
+	}
+}
+
+func Test_transitionCtxCancelled(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := fsm.TransitionCtx(ctx, CustomStateEnumB, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("TransitionCtx with a cancelled context returned %v, expected context.Canceled", err)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumA {
+		t.Errorf("Cancelled transition changed the state to %v", fsm.CurrentState())
+	}
+}
+
+func Test_transitionCtxDeadline(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+
+	// Without a hook timeout a guard that ignores ctx runs to completion, but nothing is committed past the deadline
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	if _, err := fsm.TransitionCtx(ctx, CustomStateEnumB, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TransitionCtx returned %v, expected an error wrapping context.DeadlineExceeded", err)
+	}
+
+	// A context cancelled while the pre-commit hooks run prevents the commit even if they succeed
+	ctx, cancel = context.WithCancel(context.Background())
+	fsm.AddHook(PreCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		cancel()
+		return nil
+	})
+
+	if _, err := fsm.TransitionCtx(ctx, CustomStateEnumC, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("TransitionCtx returned %v, expected an error wrapping context.Canceled", err)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumA || len(fsm.Transitions()) != 0 {
+		t.Errorf("Transitions past their deadline left the FSM in %v with history %v", fsm.CurrentState(), fsm.Transitions())
+	}
+}
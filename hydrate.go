@@ -0,0 +1,144 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DriftMetadataKey is the metadata key set on the history entry recorded when hydration corrects drift
+const DriftMetadataKey = "drift_reconciled"
+
+// Hydrator loads the current state of an entity from an external source of truth, such as a database column
+type Hydrator[K comparable, T comparable] interface {
+	LoadCurrentState(ctx context.Context, id K) (T, error)
+}
+
+// HydratorFunc adapts a function to a Hydrator
+type HydratorFunc[K comparable, T comparable] func(ctx context.Context, id K) (T, error)
+
+// LoadCurrentState calls fn
+func (fn HydratorFunc[K, T]) LoadCurrentState(ctx context.Context, id K) (T, error) {
+	return fn(ctx, id)
+}
+
+// Drift is a difference between the state recorded by an entity's FSM and the state of the source of truth
+type Drift[K comparable, T comparable] struct {
+	ID       K
+	Recorded T
+	Actual   T
+}
+
+// hydration is the configuration of lazy hydration for a Manager
+type hydration[K comparable, T comparable] struct {
+	hydrator Hydrator[K, T]
+	newFSM   func(id K) *FSM[T]
+	onDrift  func(d Drift[K, T])
+}
+
+// SetHydrator sets the hydrator Hydrate uses to derive entities' states from an external source of truth
+// newFSM creates the FSM of an entity that is not registered yet, with its rules and configuration
+// onDrift, if not nil, is called when a registered FSM's recorded state differs from the source of truth
+func (m *Manager[K, T]) SetHydrator(hydrator Hydrator[K, T], newFSM func(id K) *FSM[T], onDrift func(d Drift[K, T])) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hydration = &hydration[K, T]{hydrator: hydrator, newFSM: newFSM, onDrift: onDrift}
+}
+
+// Hydrate returns the FSM of an entity, loading its state from the hydrator on first access
+// An entity that is not registered is created with the hydrator's newFSM in the loaded state and added
+// If a registered FSM with a history is in a different state, the source of truth wins: the correction is recorded
+// in the history with DriftMetadataKey set, without running rules, hooks or actions since the change already
+// happened elsewhere, and the drift is reported. Later calls return the FSM without loading it again
+// Without a hydrator, Hydrate behaves like Get and returns ErrEntityNotFound for unknown entities
+func (m *Manager[K, T]) Hydrate(ctx context.Context, id K) (*FSM[T], error) {
+	for {
+		m.mu.RLock()
+		fsm, ok := m.fsms[id]
+		hydrated := m.hydrated[id]
+		h := m.hydration
+		m.mu.RUnlock()
+
+		if ok && (hydrated || h == nil) {
+			return fsm, nil
+		}
+
+		if h == nil {
+			return nil, fmt.Errorf("%w: %v", ErrEntityNotFound, id)
+		}
+
+		state, err := h.hydrator.LoadCurrentState(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			fsm = h.newFSM(id)
+			fsm.reconcile(state)
+
+			// Another caller may have added the entity meanwhile, in which case its FSM is hydrated instead
+			if err := m.Add(id, fsm); errors.Is(err, ErrEntityExists) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+		} else {
+			recorded, drifted := fsm.reconcile(state)
+
+			// No hook reports the change, so the counts are updated here
+			m.countsMu.Lock()
+			if e, ok := m.tracked[id]; ok && e.fsm == fsm {
+				m.move(e, state)
+			}
+			m.countsMu.Unlock()
+
+			if drifted && h.onDrift != nil {
+				h.onDrift(Drift[K, T]{ID: id, Recorded: recorded, Actual: state})
+			}
+		}
+
+		m.mu.Lock()
+		if m.hydrated == nil {
+			m.hydrated = make(map[K]bool)
+		}
+		m.hydrated[id] = true
+		m.mu.Unlock()
+
+		return fsm, nil
+	}
+}
+
+// reconcile moves the FSM to state, the state of the source of truth
+// If the FSM has a history, a differing state is drift and the correction is recorded. The recorded state
+// before the correction is returned along with whether there was drift
+func (fsm *FSM[T]) reconcile(state T) (T, bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	recorded := fsm.currentState
+	fsm.unstarted = false
+
+	if state == recorded {
+		return recorded, false
+	}
+
+	fsm.currentState = state
+	fsm.enteredAt = fsm.timeNow()
+	fsm.rememberActive(state)
+	fsm.checkTerminal()
+
+	if len(fsm.transitions) == 0 && fsm.transitionCount == 0 {
+		return recorded, false
+	}
+
+	tn := fsm.enteredAt
+	fsm.recordTransition(&Transition[T]{
+		FromState: recorded,
+		ToState:   state,
+		Timestamp: &tn,
+		Metadata:  map[string]string{DriftMetadataKey: "true"},
+	})
+
+	return recorded, true
+}
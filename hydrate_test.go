@@ -0,0 +1,69 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_hydrate(t *testing.T) {
+	m := NewManager[string, CustomStateEnum]()
+
+	if _, err := m.Hydrate(context.Background(), "order-1"); !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("Hydrate() returned %v without a hydrator, expected ErrEntityNotFound", err)
+	}
+
+	column := map[string]CustomStateEnum{"order-1": CustomStateEnumB, "order-2": CustomStateEnumA}
+	loads := 0
+	var drifts []Drift[string, CustomStateEnum]
+	m.SetHydrator(HydratorFunc[string, CustomStateEnum](func(ctx context.Context, id string) (CustomStateEnum, error) {
+		loads++
+		state, ok := column[id]
+		if !ok {
+			return "", ErrEntityNotFound
+		}
+		return state, nil
+	}), func(id string) *FSM[CustomStateEnum] {
+		return newPingPongFSM()
+	}, func(d Drift[string, CustomStateEnum]) {
+		drifts = append(drifts, d)
+	})
+
+	// Unregistered entities are created in the loaded state without reporting drift
+	fsm, err := m.Hydrate(context.Background(), "order-1")
+	if err != nil || fsm.CurrentState() != CustomStateEnumB || len(fsm.Transitions()) != 0 {
+		t.Fatalf("Hydrate() returned %v, %v, expected a new FSM in B", fsm, err)
+	}
+
+	if again, _ := m.Hydrate(context.Background(), "order-1"); again != fsm || loads != 1 {
+		t.Errorf("Hydrate() loaded the state %d times, expected the hydrated FSM to be reused", loads)
+	}
+
+	// A registered FSM whose history disagrees with the source of truth is corrected
+	registered := newPingPongFSM()
+	pingPong(registered, 1)
+	m.Add("order-2", registered)
+
+	if fsm, err := m.Hydrate(context.Background(), "order-2"); err != nil || fsm.CurrentState() != CustomStateEnumA {
+		t.Fatalf("Hydrate() returned %v, %v, expected the FSM moved to A", fsm, err)
+	}
+
+	expected := []Drift[string, CustomStateEnum]{{ID: "order-2", Recorded: CustomStateEnumB, Actual: CustomStateEnumA}}
+	if !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("Drift reported %v, expected %v", drifts, expected)
+	}
+
+	trs := registered.Transitions()
+	if last := trs[len(trs)-1]; last.ToState != CustomStateEnumA || last.Metadata[DriftMetadataKey] != "true" {
+		t.Errorf("Correction recorded as %v, expected a transition to A with %s set", last, DriftMetadataKey)
+	}
+
+	if counts := m.Counts(); counts[CustomStateEnumA] != 1 || counts[CustomStateEnumB] != 1 {
+		t.Errorf("Counts() returned %v after hydration, expected one entity in A and one in B", counts)
+	}
+
+	if _, err := m.Hydrate(context.Background(), "order-3"); !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("Hydrate() returned %v, expected the hydrator's error", err)
+	}
+}
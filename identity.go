@@ -0,0 +1,91 @@
+package statetrooper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NewNamedFSM creates an FSM like NewFSM, identified by the name of its machine and the ID of its entity
+// The identity is included in transition errors, stats, health reports, JSON exports and change events,
+// so artifacts of services running many machines can be attributed without wrapping the FSM
+func NewNamedFSM[T comparable](name string, entityID string, initialState T, maxHistory int) *FSM[T] {
+	fsm := NewFSM[T](initialState, maxHistory)
+	fsm.name = name
+	fsm.entityID = entityID
+
+	return fsm
+}
+
+// NewNamed creates an FSM like New, identified by entityID. The machine name is taken from the template's prototype
+func (tmpl *Template[T]) NewNamed(entityID string, initialState T) *FSM[T] {
+	fsm := tmpl.New(initialState)
+	fsm.entityID = entityID
+
+	return fsm
+}
+
+// Name returns the name of the FSM's machine, empty if it has none
+func (fsm *FSM[T]) Name() string {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.name
+}
+
+// EntityID returns the ID of the FSM's entity, empty if it has none
+func (fsm *FSM[T]) EntityID() string {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.entityID
+}
+
+// MachineError attributes an error returned by a transition to the machine and entity of the FSM
+// It unwraps to the original error, so errors.Is and errors.As see through it
+type MachineError struct {
+	Machine string
+	Entity  string
+	Err     error
+}
+
+func (err MachineError) Error() string {
+	return fmt.Sprintf("%s: %v", identity(err.Machine, err.Entity), err.Err)
+}
+
+func (err MachineError) Unwrap() error {
+	return err.Err
+}
+
+// attribute wraps err in a MachineError if the FSM has an identity. The caller must not hold the lock
+func (fsm *FSM[T]) attribute(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.attributeLocked(err)
+}
+
+// attributeLocked is attribute for callers holding the lock
+func (fsm *FSM[T]) attributeLocked(err error) error {
+	var machineErr MachineError
+	if err == nil || fsm.name == "" && fsm.entityID == "" || errors.As(err, &machineErr) {
+		return err
+	}
+
+	return MachineError{Machine: fsm.name, Entity: fsm.entityID, Err: err}
+}
+
+// identity formats a machine name and entity ID as name/entity, leaving out whichever is empty
+func identity(name, entityID string) string {
+	switch {
+	case name == "":
+		return entityID
+	case entityID == "":
+		return name
+	default:
+		return name + "/" + entityID
+	}
+}
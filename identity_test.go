@@ -0,0 +1,78 @@
+package statetrooper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_namedFSMErrors(t *testing.T) {
+	fsm := NewNamedFSM[CustomStateEnum]("order", "42", CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	_, err := fsm.Transition(CustomStateEnumC, nil)
+
+	var machineErr MachineError
+	if !errors.As(err, &machineErr) || machineErr.Machine != "order" || machineErr.Entity != "42" {
+		t.Fatalf("Transition returned %v, expected a MachineError for order/42", err)
+	}
+
+	var transitionErr TransitionError[CustomStateEnum]
+	if !errors.As(err, &transitionErr) || transitionErr.Machine != "order/42" {
+		t.Errorf("Transition returned %v, expected it to unwrap to a TransitionError naming order/42", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), "order/42: ") {
+		t.Errorf("Transition returned %q, expected it to be prefixed with order/42", err)
+	}
+
+	unnamed := newPingPongFSM()
+	if _, err := unnamed.Transition(CustomStateEnumC, nil); errors.As(err, &machineErr) {
+		t.Errorf("Transition of an unnamed FSM returned %v, expected no MachineError", err)
+	}
+}
+
+func Test_namedFSMArtifacts(t *testing.T) {
+	tmpl := NewTemplate(NewNamedFSM[CustomStateEnum]("order", "", CustomStateEnumA, 10))
+	fsm := tmpl.NewNamed("42", CustomStateEnumA)
+
+	if fsm.Name() != "order" || fsm.EntityID() != "42" {
+		t.Errorf("NewNamed created %q/%q, expected order/42", fsm.Name(), fsm.EntityID())
+	}
+
+	if stats := fsm.Stats(); stats.Name != "order" || stats.EntityID != "42" {
+		t.Errorf("Stats are labelled %q/%q, expected order/42", stats.Name, stats.EntityID)
+	}
+
+	if report := fsm.HealthCheck(); report.Name != "order" || report.EntityID != "42" {
+		t.Errorf("HealthCheck reported %q/%q, expected order/42", report.Name, report.EntityID)
+	}
+
+	data, err := json.Marshal(fsm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Name() != "order" || restored.EntityID() != "42" {
+		t.Errorf("UnmarshalJSON restored %q/%q, expected order/42", restored.Name(), restored.EntityID())
+	}
+
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.Transition(CustomStateEnumB, nil)
+
+	var buf bytes.Buffer
+	if err := fsm.ExportCDC(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var event CDCEvent[CustomStateEnum]
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil || event.Source.Name != "order/42" {
+		t.Errorf("ExportCDC wrote %s, expected the source to be named order/42", buf.String())
+	}
+}
@@ -0,0 +1,118 @@
+package statetrooper
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// IDGenerator issues transition IDs, for example from a database sequence or with a node prefix, so that
+// histories from several nodes can be merged in a global order
+type IDGenerator interface {
+	// NextID returns the ID of the next recorded entry given the last ID the FSM issued, zero if none
+	// IDs must increase within an FSM, since its history is looked up by ID in order
+	NextID(last uint64) uint64
+}
+
+// IDGeneratorFunc adapts a function to an IDGenerator
+type IDGeneratorFunc func(last uint64) uint64
+
+// NextID calls fn
+func (fn IDGeneratorFunc) NextID(last uint64) uint64 {
+	return fn(last)
+}
+
+// NodeIDGenerator returns an IDGenerator that prefixes a sequence with node in the top 16 bits,
+// so IDs issued by different nodes never collide
+func NodeIDGenerator(node uint16) IDGenerator {
+	const seqBits = 48
+
+	return IDGeneratorFunc(func(last uint64) uint64 {
+		seq := last & (1<<seqBits - 1)
+		return uint64(node)<<seqBits | (seq + 1)
+	})
+}
+
+// SequenceIDGenerator returns an IDGenerator backed by a counter that can be shared by several FSMs,
+// so their IDs are unique across all of them, as Manager.Feed requires
+// It never issues an ID below the last ID of the FSM, so it can be set on FSMs that already have a history
+func SequenceIDGenerator() IDGenerator {
+	var seq atomic.Uint64
+
+	return IDGeneratorFunc(func(last uint64) uint64 {
+		for {
+			cur := seq.Load()
+			next := cur
+			if last > next {
+				next = last
+			}
+			next++
+
+			if seq.CompareAndSwap(cur, next) {
+				return next
+			}
+		}
+	})
+}
+
+// Timestamper issues the timestamps of recorded transitions, beyond reading the clock
+type Timestamper interface {
+	// Timestamp returns the timestamp of the next recorded entry given the clock's time and the timestamp
+	// of the last entry the FSM recorded, the zero time if none
+	Timestamp(now time.Time, last time.Time) time.Time
+}
+
+// TimestamperFunc adapts a function to a Timestamper
+type TimestamperFunc func(now time.Time, last time.Time) time.Time
+
+// Timestamp calls fn
+func (fn TimestamperFunc) Timestamp(now time.Time, last time.Time) time.Time {
+	return fn(now, last)
+}
+
+// MonotonicTimestamper returns a Timestamper that keeps timestamps strictly increasing, like the physical part
+// of a hybrid logical clock, even if the clock steps back or two entries are recorded at the same instant
+func MonotonicTimestamper() Timestamper {
+	return TimestamperFunc(func(now time.Time, last time.Time) time.Time {
+		if !now.After(last) {
+			return last.Add(time.Nanosecond)
+		}
+
+		return now
+	})
+}
+
+// SetIDGenerator sets the generator of transition IDs. Passing nil restores sequential IDs starting from one
+func (fsm *FSM[T]) SetIDGenerator(generator IDGenerator) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.idGenerator = generator
+}
+
+// SetTimestamper sets the generator of transition timestamps, which is passed the time read from the clock
+// The clock still drives cooldowns, debouncing and dwell times. Passing nil records the clock's time as is
+func (fsm *FSM[T]) SetTimestamper(timestamper Timestamper) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.timestamper = timestamper
+}
+
+// stamp assigns the next ID and timestamp to an entry about to be recorded. The caller must hold the lock
+func (fsm *FSM[T]) stamp(tr *Transition[T]) {
+	if fsm.idGenerator != nil {
+		fsm.lastID = fsm.idGenerator.NextID(fsm.lastID)
+	} else {
+		fsm.lastID++
+	}
+	tr.ID = fsm.lastID
+
+	if fsm.timestamper != nil && tr.Timestamp != nil {
+		ts := fsm.timestamper.Timestamp(*tr.Timestamp, fsm.lastStamp)
+		tr.Timestamp = &ts
+	}
+
+	if tr.Timestamp != nil {
+		fsm.lastStamp = *tr.Timestamp
+	}
+}
@@ -0,0 +1,52 @@
+package statetrooper
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_idGenerator(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetIDGenerator(NodeIDGenerator(7))
+	pingPong(fsm, 2)
+
+	trs := fsm.Transitions()
+	if trs[0].ID != 7<<48|1 || trs[1].ID != 7<<48|2 {
+		t.Errorf("Transitions have IDs %x and %x, expected node 7 with sequence 1 and 2", trs[0].ID, trs[1].ID)
+	}
+
+	if err := fsm.AnnotateTransition(trs[1].ID, "k", "v"); err != nil {
+		t.Errorf("AnnotateTransition() returned an error for a generated ID: %v", err)
+	}
+
+	fsm.SetIDGenerator(IDGeneratorFunc(func(last uint64) uint64 { return last + 10 }))
+	pingPong(fsm, 1)
+
+	if trs := fsm.Transitions(); trs[2].ID != 7<<48|12 {
+		t.Errorf("Transition has ID %x, expected %x", trs[2].ID, uint64(7<<48|12))
+	}
+}
+
+func Test_timestamper(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	fsm := newPingPongFSM()
+	fsm.SetClock(func() time.Time { return now })
+	fsm.SetTimestamper(MonotonicTimestamper())
+	pingPong(fsm, 2)
+
+	// The clock stepping back does not reorder the history
+	now = now.Add(-time.Second)
+	pingPong(fsm, 1)
+
+	trs := fsm.Transitions()
+	for i := 1; i < len(trs); i++ {
+		if !trs[i].Timestamp.After(*trs[i-1].Timestamp) {
+			t.Errorf("Transition %d at %v is not after %v", i, trs[i].Timestamp, trs[i-1].Timestamp)
+		}
+	}
+
+	if !fsm.LastActivity().Equal(*trs[2].Timestamp) {
+		t.Errorf("LastActivity() returned %v, expected the last transition's timestamp %v", fsm.LastActivity(), trs[2].Timestamp)
+	}
+}
@@ -0,0 +1,149 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Input is a request to move an FSM decoded from an external payload
+// If Event is set the event is fired, otherwise the FSM transitions to Target
+type Input[T comparable] struct {
+	Target   T
+	Event    string
+	Metadata map[string]string
+}
+
+// InputMapper decodes a payload of type P into an Input
+type InputMapper[P any, T comparable] func(payload P) (Input[T], error)
+
+// InputErrorHandler receives payloads that could not be mapped or whose transition failed
+type InputErrorHandler[P any] func(payload P, err error)
+
+// Receiver is a message queue consumer. Each received payload is acknowledged with Ack once its transition
+// has been applied, or rejected with Nack and the reason so the queue can redeliver or dead-letter it
+type Receiver[P any] interface {
+	Receive(ctx context.Context) (P, error)
+	Ack(ctx context.Context, payload P) error
+	Nack(ctx context.Context, payload P, reason error) error
+}
+
+// InputResponse is the body written by InputHandler
+type InputResponse struct {
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+// ApplyInput fires the input's event or transitions to its target
+func (fsm *FSM[T]) ApplyInput(ctx context.Context, in Input[T]) (T, error) {
+	if in.Event != "" {
+		return fsm.FireCtx(ctx, in.Event, in.Metadata)
+	}
+
+	return fsm.TransitionCtx(ctx, in.Target, in.Metadata)
+}
+
+// Drive applies the payloads received from ch to fsm in order until ch is closed, ctx is done or fsm is closed
+// Payloads that cannot be mapped or applied are passed to onError, which may be nil, and do not stop the loop
+// It returns nil once ch is closed, ctx.Err() if ctx is done and ErrClosed if fsm is closed
+func Drive[P any, T comparable](ctx context.Context, fsm *FSM[T], ch <-chan P, mapper InputMapper[P, T], onError InputErrorHandler[P]) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case payload, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			err := applyPayload(ctx, fsm, payload, mapper)
+			if errors.Is(err, ErrClosed) {
+				return err
+			}
+			if err != nil && onError != nil {
+				onError(payload, err)
+			}
+		}
+	}
+}
+
+// Consume applies the payloads received from r to fsm until receiving fails, acknowledging each payload once
+// applied and rejecting it with the mapping or transition error otherwise. A payload whose transition was
+// committed but whose entry or exit action failed is acknowledged, since redelivering it cannot succeed
+// It returns the error of Receive, such as ctx.Err() once ctx is done, or ErrClosed if fsm is closed
+func Consume[P any, T comparable](ctx context.Context, fsm *FSM[T], r Receiver[P], mapper InputMapper[P, T]) error {
+	for {
+		payload, err := r.Receive(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := applyPayload(ctx, fsm, payload, mapper); err != nil && !errors.Is(err, ErrActionFailed) {
+			// A closed FSM will reject every payload, so it is handed back for another consumer
+			if nackErr := r.Nack(ctx, payload, err); nackErr != nil || errors.Is(err, ErrClosed) {
+				return errors.Join(err, nackErr)
+			}
+			continue
+		}
+
+		if err := r.Ack(ctx, payload); err != nil {
+			return err
+		}
+	}
+}
+
+// InputHandler returns an HTTP handler applying the Input mapped from each request to fsm, such as a webhook
+// It responds with the resulting state as JSON: 200 OK on success, 400 Bad Request if the request cannot
+// be mapped and otherwise a status describing the rejection, such as 409 Conflict for an invalid transition
+func InputHandler[T comparable](fsm *FSM[T], mapper InputMapper[*http.Request, T]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in, err := mapper(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, InputResponse{State: DisplayName(fsm.CurrentState(), ""), Error: err.Error()})
+			return
+		}
+
+		state, err := fsm.ApplyInput(r.Context(), in)
+
+		response := InputResponse{State: DisplayName(state, "")}
+		if err != nil {
+			response.Error = err.Error()
+		}
+
+		writeJSON(w, inputStatus[T](err), response)
+	})
+}
+
+// applyPayload maps payload and applies the resulting input to fsm
+func applyPayload[P any, T comparable](ctx context.Context, fsm *FSM[T], payload P, mapper InputMapper[P, T]) error {
+	in, err := mapper(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = fsm.ApplyInput(ctx, in)
+
+	return err
+}
+
+// inputStatus returns the HTTP status reporting the outcome of an input
+func inputStatus[T comparable](err error) int {
+	var transitionErr TransitionError[T]
+
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, ErrThrottled), errors.Is(err, ErrTooSoon), errors.Is(err, ErrMinDwell):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrClosed), errors.Is(err, ErrNotStarted):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.As(err, &transitionErr), errors.Is(err, ErrUnknownEvent), errors.Is(err, ErrStateNotRegistered):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
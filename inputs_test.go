@@ -0,0 +1,155 @@
+package statetrooper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// orderEvent is a payload as it might arrive from a queue
+type orderEvent struct {
+	Kind  string
+	State string
+}
+
+func mapOrderEvent(e orderEvent) (Input[CustomStateEnum], error) {
+	switch e.Kind {
+	case "event":
+		return Input[CustomStateEnum]{Event: e.State}, nil
+	case "state":
+		return Input[CustomStateEnum]{Target: CustomStateEnum(e.State), Metadata: map[string]string{"source": "queue"}}, nil
+	default:
+		return Input[CustomStateEnum]{}, errors.New("unknown kind")
+	}
+}
+
+func newInputFSM() *FSM[CustomStateEnum] {
+	fsm := newPingPongFSM()
+	fsm.AddEvent("finish", CustomStateEnumB, CustomStateEnumC)
+	return fsm
+}
+
+func Test_drive(t *testing.T) {
+	fsm := newInputFSM()
+
+	ch := make(chan orderEvent, 4)
+	ch <- orderEvent{Kind: "state", State: "B"}
+	ch <- orderEvent{Kind: "bogus"}
+	ch <- orderEvent{Kind: "state", State: "D"}
+	ch <- orderEvent{Kind: "event", State: "finish"}
+	close(ch)
+
+	var failed []orderEvent
+	err := Drive(context.Background(), fsm, ch, mapOrderEvent, func(e orderEvent, err error) {
+		failed = append(failed, e)
+	})
+
+	if err != nil {
+		t.Errorf("Drive returned %v once the channel was closed, expected nil", err)
+	}
+	if fsm.CurrentState() != CustomStateEnumC {
+		t.Errorf("Drive moved the FSM to %v, expected C", fsm.CurrentState())
+	}
+	if len(failed) != 2 || failed[0].Kind != "bogus" || failed[1].State != "D" {
+		t.Errorf("Drive reported failures for %v, expected the unmappable payload and the invalid transition", failed)
+	}
+
+	fsm.Close(context.Background())
+	ch = make(chan orderEvent, 1)
+	ch <- orderEvent{Kind: "state", State: "A"}
+	if err := Drive(context.Background(), fsm, ch, mapOrderEvent, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Drive of a closed FSM returned %v, expected ErrClosed", err)
+	}
+}
+
+// sliceReceiver receives payloads from a slice and records acknowledgements
+type sliceReceiver struct {
+	payloads []orderEvent
+	acked    []orderEvent
+	nacked   []orderEvent
+}
+
+func (r *sliceReceiver) Receive(ctx context.Context) (orderEvent, error) {
+	if len(r.payloads) == 0 {
+		return orderEvent{}, io.EOF
+	}
+
+	payload := r.payloads[0]
+	r.payloads = r.payloads[1:]
+
+	return payload, nil
+}
+
+func (r *sliceReceiver) Ack(ctx context.Context, payload orderEvent) error {
+	r.acked = append(r.acked, payload)
+	return nil
+}
+
+func (r *sliceReceiver) Nack(ctx context.Context, payload orderEvent, reason error) error {
+	r.nacked = append(r.nacked, payload)
+	return nil
+}
+
+func Test_consume(t *testing.T) {
+	fsm := newInputFSM()
+	r := &sliceReceiver{payloads: []orderEvent{
+		{Kind: "state", State: "B"},
+		{Kind: "event", State: "restart"},
+		{Kind: "event", State: "finish"},
+	}}
+
+	if err := Consume[orderEvent](context.Background(), fsm, r, mapOrderEvent); !errors.Is(err, io.EOF) {
+		t.Errorf("Consume returned %v, expected the receive error", err)
+	}
+
+	if len(r.acked) != 2 || len(r.nacked) != 1 || r.nacked[0].State != "restart" {
+		t.Errorf("Consume acked %v and nacked %v, expected the unknown event to be nacked", r.acked, r.nacked)
+	}
+
+	if md := fsm.Transitions()[0].Metadata; md["source"] != "queue" {
+		t.Errorf("Consume recorded metadata %v, expected the mapped metadata", md)
+	}
+}
+
+func Test_inputHandler(t *testing.T) {
+	fsm := newInputFSM()
+	handler := InputHandler(fsm, func(r *http.Request) (Input[CustomStateEnum], error) {
+		if target := r.URL.Query().Get("state"); target != "" {
+			return Input[CustomStateEnum]{Target: CustomStateEnum(target)}, nil
+		}
+		if event := r.URL.Query().Get("event"); event != "" {
+			return Input[CustomStateEnum]{Event: event}, nil
+		}
+		return Input[CustomStateEnum]{}, errors.New("missing state or event")
+	})
+
+	tests := []struct {
+		query  string
+		status int
+		state  string
+	}{
+		{"", http.StatusBadRequest, "A"},
+		{"state=B", http.StatusOK, "B"},
+		{"state=D", http.StatusConflict, "B"},
+		{"event=restart", http.StatusConflict, "B"},
+		{"event=finish", http.StatusOK, "C"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders/1?"+tt.query, nil))
+
+		var response InputResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("InputHandler responded with %s, %v", rec.Body.String(), err)
+		}
+
+		if rec.Code != tt.status || response.State != tt.state || (tt.status != http.StatusOK) != (response.Error != "") {
+			t.Errorf("InputHandler responded to %q with %d %+v, expected %d in state %s", tt.query, rec.Code, response, tt.status, tt.state)
+		}
+	}
+}
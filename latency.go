@@ -0,0 +1,197 @@
+package statetrooper
+
+import (
+	"context"
+	"time"
+)
+
+// TransitionTimings is the time a transition attempt spent in each of its phases
+type TransitionTimings struct {
+	// LockWait is the time spent waiting for the FSM's lock
+	LockWait time.Duration `json:"lock_wait"`
+	Guards   time.Duration `json:"guards"`
+	// Hooks covers the pre-commit and synchronous post-commit hooks
+	Hooks time.Duration `json:"hooks"`
+	// Commit is the time spent recording the transition, including the history and the outbox
+	Commit time.Duration `json:"commit"`
+	// Publish is the time spent delivering the transition to subscriptions
+	Publish time.Duration `json:"publish"`
+	Actions time.Duration `json:"actions"`
+	Total   time.Duration `json:"total"`
+}
+
+// SlowTransition describes a transition attempt that took longer than the slow-transition threshold
+type SlowTransition[T comparable] struct {
+	FromState T
+	ToState   T
+	Timings   TransitionTimings
+	Err       error
+}
+
+// LatencyStats aggregates the timings of the transition attempts measured since tracking was enabled
+type LatencyStats struct {
+	Attempts int `json:"attempts"`
+	// Slow counts the attempts that exceeded the slow-transition threshold
+	Slow int `json:"slow"`
+	// Sum and Max are the summed and the longest time of each phase
+	Sum TransitionTimings `json:"sum"`
+	Max TransitionTimings `json:"max"`
+}
+
+// latencyTracking is the latency configuration and aggregate of an FSM
+type latencyTracking[T comparable] struct {
+	threshold time.Duration
+	onSlow    func(SlowTransition[T])
+	stats     LatencyStats
+}
+
+// SetLatencyTracking enables or disables measuring the time transition attempts spend in each phase
+// Disabling it discards the aggregated stats and the slow-transition threshold
+func (fsm *FSM[T]) SetLatencyTracking(enabled bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	switch {
+	case !enabled:
+		fsm.latency = nil
+	case fsm.latency == nil:
+		fsm.latency = &latencyTracking[T]{}
+	}
+}
+
+// SetSlowTransitionThreshold enables latency tracking and calls onSlow, without holding the lock, for every
+// transition attempt that takes at least threshold in total. A zero threshold or nil onSlow removes the callback
+func (fsm *FSM[T]) SetSlowTransitionThreshold(threshold time.Duration, onSlow func(SlowTransition[T])) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.latency == nil {
+		fsm.latency = &latencyTracking[T]{}
+	}
+
+	if threshold <= 0 || onSlow == nil {
+		threshold, onSlow = 0, nil
+	}
+
+	fsm.latency.threshold = threshold
+	fsm.latency.onSlow = onSlow
+}
+
+// Latency returns the aggregated timings of the transition attempts measured so far
+// It returns zero stats if latency tracking is disabled
+func (fsm *FSM[T]) Latency() LatencyStats {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.latency == nil {
+		return LatencyStats{}
+	}
+
+	return fsm.latency.stats
+}
+
+type timingsKey struct{}
+
+// startTiming returns ctx carrying the timings of a new transition attempt if latency tracking is enabled
+func (fsm *FSM[T]) startTiming(ctx context.Context) (context.Context, *TransitionTimings, T) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.latency == nil {
+		return ctx, nil, fsm.currentState
+	}
+
+	timings := &TransitionTimings{}
+
+	return context.WithValue(ctx, timingsKey{}, timings), timings, fsm.currentState
+}
+
+// timingsFrom returns the timings of the transition attempt of ctx, nil if it is not measured
+func timingsFrom(ctx context.Context) *TransitionTimings {
+	timings, _ := ctx.Value(timingsKey{}).(*TransitionTimings)
+	return timings
+}
+
+// startPhase returns the start time of a phase, or the zero time if the attempt is not measured
+// to avoid reading the clock
+func startPhase(timings *TransitionTimings) time.Time {
+	if timings == nil {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// Phases of a transition attempt, as passed to endPhase
+var (
+	lockWaitPhase = func(t *TransitionTimings) *time.Duration { return &t.LockWait }
+	guardsPhase   = func(t *TransitionTimings) *time.Duration { return &t.Guards }
+	hooksPhase    = func(t *TransitionTimings) *time.Duration { return &t.Hooks }
+	commitPhase   = func(t *TransitionTimings) *time.Duration { return &t.Commit }
+	publishPhase  = func(t *TransitionTimings) *time.Duration { return &t.Publish }
+	actionsPhase  = func(t *TransitionTimings) *time.Duration { return &t.Actions }
+)
+
+// endPhase adds the time elapsed since start to the phase selected by phase
+func endPhase(timings *TransitionTimings, phase func(*TransitionTimings) *time.Duration, start time.Time) {
+	if timings == nil {
+		return
+	}
+
+	*phase(timings) += time.Since(start)
+}
+
+// observeLatency aggregates the timings of a finished transition attempt and reports it if it was slow
+// It must be called without holding the lock
+func (fsm *FSM[T]) observeLatency(fromState T, targetState T, timings *TransitionTimings, err error) {
+	fsm.mu.Lock()
+	latency := fsm.latency
+	if latency == nil {
+		fsm.mu.Unlock()
+		return
+	}
+
+	stats := &latency.stats
+	stats.Attempts++
+	addTimings(&stats.Sum, timings)
+	maxTimings(&stats.Max, timings)
+
+	slow := latency.onSlow != nil && timings.Total >= latency.threshold
+	if slow {
+		stats.Slow++
+	}
+	onSlow := latency.onSlow
+	fsm.mu.Unlock()
+
+	if slow {
+		onSlow(SlowTransition[T]{FromState: fromState, ToState: targetState, Timings: *timings, Err: err})
+	}
+}
+
+// addTimings adds each phase of t to sum
+func addTimings(sum *TransitionTimings, t *TransitionTimings) {
+	sum.LockWait += t.LockWait
+	sum.Guards += t.Guards
+	sum.Hooks += t.Hooks
+	sum.Commit += t.Commit
+	sum.Publish += t.Publish
+	sum.Actions += t.Actions
+	sum.Total += t.Total
+}
+
+// maxTimings raises each phase of max to the phase of t if it is longer
+func maxTimings(max *TransitionTimings, t *TransitionTimings) {
+	raise := func(m *time.Duration, d time.Duration) {
+		if d > *m {
+			*m = d
+		}
+	}
+
+	raise(&max.LockWait, t.LockWait)
+	raise(&max.Guards, t.Guards)
+	raise(&max.Hooks, t.Hooks)
+	raise(&max.Commit, t.Commit)
+	raise(&max.Publish, t.Publish)
+	raise(&max.Actions, t.Actions)
+	raise(&max.Total, t.Total)
+}
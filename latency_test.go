@@ -0,0 +1,62 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_latencyTracking(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	pingPong(fsm, 1)
+	if stats := fsm.Latency(); stats.Attempts != 0 {
+		t.Errorf("Latency counted %d attempts before tracking was enabled", stats.Attempts)
+	}
+
+	fsm.SetLatencyTracking(true)
+	pingPong(fsm, 2)
+
+	stats := fsm.Latency()
+	if stats.Attempts != 2 {
+		t.Errorf("Latency counted %d attempts, expected 2", stats.Attempts)
+	}
+	if stats.Max.Guards < 5*time.Millisecond || stats.Sum.Total < stats.Sum.Guards {
+		t.Errorf("Latency measured guards %v of total %v, expected at least 5ms", stats.Max.Guards, stats.Sum.Total)
+	}
+
+	fsm.SetLatencyTracking(false)
+	if stats := fsm.Latency(); stats.Attempts != 0 {
+		t.Errorf("Latency counted %d attempts after tracking was disabled", stats.Attempts)
+	}
+}
+
+func Test_slowTransitionThreshold(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.AddHook(PreCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		if tr.ToState == CustomStateEnumB {
+			time.Sleep(5 * time.Millisecond)
+			return errors.New("slow and failing")
+		}
+		return nil
+	})
+
+	var slow []SlowTransition[CustomStateEnum]
+	fsm.SetSlowTransitionThreshold(5*time.Millisecond, func(s SlowTransition[CustomStateEnum]) {
+		slow = append(slow, s)
+	})
+
+	fsm.Transition(CustomStateEnumB, nil)
+
+	if len(slow) != 1 || fsm.Latency().Slow != 1 {
+		t.Fatalf("reported %d slow transitions, expected 1", len(slow))
+	}
+	if s := slow[0]; s.FromState != CustomStateEnumA || s.ToState != CustomStateEnumB || s.Err == nil || s.Timings.Hooks < 5*time.Millisecond {
+		t.Errorf("reported %+v, expected a failed transition from A to B slowed down by its hooks", s)
+	}
+}
@@ -0,0 +1,74 @@
+package statetrooper
+
+import (
+	"context"
+	"sync"
+)
+
+// Listener is called after the FSM enters the state it was registered for
+type Listener[T comparable] func(tr Transition[T])
+
+// OnState registers fn to be called after each transition into state and returns a function that deregisters it
+// Listeners run as post-commit hooks, so they follow the async hook settings and run without the lock held
+func (fsm *FSM[T]) OnState(state T, fn Listener[T]) (remove func()) {
+	return fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[T]) error {
+		if tr.ToState == state {
+			fn(tr)
+		}
+		return nil
+	})
+}
+
+// OnEnter is OnState under the name used by classic state machine APIs
+func (fsm *FSM[T]) OnEnter(state T, fn Listener[T]) (remove func()) {
+	return fsm.OnState(state, fn)
+}
+
+// OnExit registers fn to be called after each transition out of state and returns a function that deregisters it
+// Like OnState listeners it runs without the lock held, after the transition has been committed
+func (fsm *FSM[T]) OnExit(state T, fn Listener[T]) (remove func()) {
+	return fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[T]) error {
+		if tr.FromState == state {
+			fn(tr)
+		}
+		return nil
+	})
+}
+
+// OnTransition registers fn to be called after every committed transition and returns a function that deregisters it
+// For each transition, OnExit, OnTransition and OnEnter listeners run in the order they were registered
+func (fsm *FSM[T]) OnTransition(fn Listener[T]) (remove func()) {
+	return fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[T]) error {
+		fn(tr)
+		return nil
+	})
+}
+
+// OnceState registers fn to be called after the next transition into state only
+// The returned function deregisters it if the state has not been entered yet
+func (fsm *FSM[T]) OnceState(state T, fn Listener[T]) (remove func()) {
+	var once sync.Once
+	var mu sync.Mutex
+	var deregister func()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	deregister = fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[T]) error {
+		if tr.ToState != state {
+			return nil
+		}
+
+		once.Do(func() {
+			mu.Lock()
+			remove := deregister
+			mu.Unlock()
+
+			remove()
+			fn(tr)
+		})
+		return nil
+	})
+
+	return deregister
+}
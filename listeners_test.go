@@ -0,0 +1,66 @@
+package statetrooper
+
+import "testing"
+
+func Test_onState(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	var entered, once []Transition[CustomStateEnum]
+	remove := fsm.OnState(CustomStateEnumB, func(tr Transition[CustomStateEnum]) {
+		entered = append(entered, tr)
+	})
+	fsm.OnceState(CustomStateEnumB, func(tr Transition[CustomStateEnum]) {
+		once = append(once, tr)
+	})
+	removeUnused := fsm.OnceState(CustomStateEnumC, func(tr Transition[CustomStateEnum]) {
+		t.Errorf("Listener for an unvisited state was called")
+	})
+
+	pingPong(fsm, 4)
+
+	if len(entered) != 2 || entered[0].ToState != CustomStateEnumB || entered[1].ToState != CustomStateEnumB {
+		t.Errorf("OnState() listener received %v, expected two transitions into B", entered)
+	}
+
+	if len(once) != 1 {
+		t.Errorf("OnceState() listener was called %d times, expected 1", len(once))
+	}
+
+	remove()
+	removeUnused()
+	pingPong(fsm, 2)
+
+	if len(entered) != 2 {
+		t.Errorf("OnState() listener was called after being removed")
+	}
+}
+
+func Test_onEnterExitTransition(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	var calls []string
+	fsm.OnExit(CustomStateEnumA, func(tr Transition[CustomStateEnum]) {
+		calls = append(calls, "exit "+string(tr.FromState))
+	})
+	removeTransition := fsm.OnTransition(func(tr Transition[CustomStateEnum]) {
+		calls = append(calls, string(tr.FromState)+"->"+string(tr.ToState))
+	})
+	fsm.OnEnter(CustomStateEnumB, func(tr Transition[CustomStateEnum]) {
+		calls = append(calls, "enter "+string(tr.ToState))
+	})
+
+	pingPong(fsm, 2)
+	removeTransition()
+	pingPong(fsm, 1)
+
+	expected := []string{"exit A", "A->B", "enter B", "B->A", "exit A", "enter B"}
+	if len(calls) != len(expected) {
+		t.Fatalf("listeners were called with %v, expected %v", calls, expected)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("listeners were called with %v, expected %v", calls, expected)
+			break
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package statetrooper
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// Mailbox serializes transition commands for an FSM through a priority queue processed by a single goroutine
+// Commands with a higher priority are processed first; commands with equal priority are processed in the order they were sent
+type Mailbox[T comparable] struct {
+	fsm *FSM[T]
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   commandQueue[T]
+	seq     uint64
+	stopped bool
+	done    chan struct{}
+}
+
+// MailboxResult is the outcome of a command processed by a Mailbox
+type MailboxResult[T comparable] struct {
+	State T
+	Err   error
+}
+
+// command is a queued transition request
+type command[T comparable] struct {
+	toState  T
+	metadata map[string]string
+	priority int
+	seq      uint64
+	result   chan MailboxResult[T]
+}
+
+// NewMailbox creates a Mailbox for the given FSM and starts processing commands
+func NewMailbox[T comparable](fsm *FSM[T]) *Mailbox[T] {
+	m := &Mailbox[T]{
+		fsm:  fsm,
+		done: make(chan struct{}),
+	}
+	m.cond = sync.NewCond(&m.mu)
+
+	go m.run()
+
+	return m
+}
+
+// Send queues a transition to targetState with the given priority
+// The returned channel receives the result once the command has been processed
+func (m *Mailbox[T]) Send(targetState T, metadata map[string]string, priority int) <-chan MailboxResult[T] {
+	result := make(chan MailboxResult[T], 1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		result <- MailboxResult[T]{State: m.fsm.CurrentState(), Err: ErrMailboxStopped}
+		return result
+	}
+
+	m.seq++
+	heap.Push(&m.queue, &command[T]{
+		toState:  targetState,
+		metadata: metadata,
+		priority: priority,
+		seq:      m.seq,
+		result:   result,
+	})
+	m.cond.Signal()
+
+	return result
+}
+
+// Pending returns the number of commands waiting to be processed
+func (m *Mailbox[T]) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.queue.Len()
+}
+
+// Stop stops accepting new commands, processes the commands already queued and waits for processing to finish
+func (m *Mailbox[T]) Stop() {
+	m.mu.Lock()
+	m.stopped = true
+	m.cond.Signal()
+	m.mu.Unlock()
+
+	<-m.done
+}
+
+// run processes queued commands until the mailbox is stopped and drained
+func (m *Mailbox[T]) run() {
+	defer close(m.done)
+
+	for {
+		m.mu.Lock()
+		for m.queue.Len() == 0 && !m.stopped {
+			m.cond.Wait()
+		}
+
+		if m.queue.Len() == 0 {
+			m.mu.Unlock()
+			return
+		}
+
+		cmd := heap.Pop(&m.queue).(*command[T])
+		m.mu.Unlock()
+
+		state, err := m.fsm.Transition(cmd.toState, cmd.metadata)
+		cmd.result <- MailboxResult[T]{State: state, Err: err}
+	}
+}
+
+// commandQueue implements heap.Interface ordered by priority, then by send order
+type commandQueue[T comparable] []*command[T]
+
+func (q commandQueue[T]) Len() int { return len(q) }
+
+func (q commandQueue[T]) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+
+	return q[i].seq < q[j].seq
+}
+
+func (q commandQueue[T]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *commandQueue[T]) Push(x any) { *q = append(*q, x.(*command[T])) }
+
+func (q *commandQueue[T]) Pop() any {
+	old := *q
+	n := len(old)
+	cmd := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+
+	return cmd
+}
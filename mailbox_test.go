@@ -0,0 +1,56 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_mailboxPriority(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC, CustomStateEnumD)
+	fsm.AddRule(CustomStateEnumC, CustomStateEnumD)
+
+	// Block the mailbox goroutine on a guard so commands pile up in the queue
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		close(entered)
+		<-release
+		return nil
+	})
+
+	m := NewMailbox(fsm)
+
+	first := m.Send(CustomStateEnumB, nil, 0)
+	<-entered
+
+	bulk := m.Send(CustomStateEnumC, nil, 0)
+	correction := m.Send(CustomStateEnumD, map[string]string{"requested_by": "operator"}, 10)
+
+	if m.Pending() != 2 {
+		t.Errorf("Pending() = %d, expected 2", m.Pending())
+	}
+
+	close(release)
+
+	if res := <-first; res.Err != nil {
+		t.Errorf("First command returned an error: %v", res.Err)
+	}
+
+	if res := <-correction; res.Err != nil || res.State != CustomStateEnumD {
+		t.Errorf("High priority command returned (%v, %v), expected (%v, nil)", res.State, res.Err, CustomStateEnumD)
+	}
+
+	// The bulk command runs after the correction and is no longer valid from D
+	if res := <-bulk; res.Err == nil {
+		t.Errorf("Low priority command should have been processed after the high priority command")
+	}
+
+	m.Stop()
+
+	if res := <-m.Send(CustomStateEnumC, nil, 0); !errors.Is(res.Err, ErrMailboxStopped) {
+		t.Errorf("Send after Stop returned %v, expected ErrMailboxStopped", res.Err)
+	}
+}
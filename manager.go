@@ -0,0 +1,254 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Manager keeps track of the FSMs of many entities, identified by IDs of type K
+type Manager[K comparable, T comparable] struct {
+	mu   sync.RWMutex
+	fsms map[K]*FSM[T]
+	// registered maps each registered FSM to its ID, so that an FSM cannot be held under two IDs
+	registered map[*FSM[T]]K
+
+	// batchMu serializes all-or-nothing batches so that their FSM locks cannot be taken in conflicting orders
+	batchMu sync.Mutex
+
+	triggers []*trigger[K, T]
+
+	// countsMu guards the state population counts, which are updated by hooks outside of mu
+	countsMu sync.Mutex
+	counts   map[T]int
+	tracked  map[K]*trackedEntity[T]
+
+	variants    []Variant[T]
+	assignments map[K]string
+
+	quota   int
+	tenants map[string]*Manager[K, T]
+	closed  bool
+
+	hydration *hydration[K, T]
+	hydrated  map[K]bool
+}
+
+// NewManager creates an empty Manager
+func NewManager[K comparable, T comparable]() *Manager[K, T] {
+	return &Manager[K, T]{
+		fsms:       make(map[K]*FSM[T]),
+		registered: make(map[*FSM[T]]K),
+		counts:     make(map[T]int),
+		tracked:    make(map[K]*trackedEntity[T]),
+	}
+}
+
+// Add registers the FSM of an entity
+// ErrEntityExists is returned if the ID is already in use, ErrFSMRegistered if the FSM is registered under
+// another ID, ErrQuotaExceeded if the quota is reached and ErrClosed if the Manager has been closed
+func (m *Manager[K, T]) Add(id K, fsm *FSM[T]) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrClosed
+	}
+
+	if _, ok := m.fsms[id]; ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %v", ErrEntityExists, id)
+	}
+
+	// Batches lock each FSM once per ID, so an FSM held under two IDs would deadlock them
+	if other, ok := m.registered[fsm]; ok {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: as %v", ErrFSMRegistered, other)
+	}
+
+	if m.quota > 0 && len(m.fsms) >= m.quota {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %d entities", ErrQuotaExceeded, m.quota)
+	}
+
+	m.fsms[id] = fsm
+	m.registered[fsm] = id
+	m.mu.Unlock()
+
+	m.track(id, fsm)
+
+	return nil
+}
+
+// Get returns the FSM of an entity
+func (m *Manager[K, T]) Get(id K) (*FSM[T], bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fsm, ok := m.fsms[id]
+
+	return fsm, ok
+}
+
+// Remove unregisters the FSM of an entity
+func (m *Manager[K, T]) Remove(id K) {
+	m.mu.Lock()
+	if fsm, ok := m.fsms[id]; ok {
+		delete(m.registered, fsm)
+	}
+	delete(m.fsms, id)
+	delete(m.hydrated, id)
+	m.mu.Unlock()
+
+	m.untrack(id)
+}
+
+// IDs returns the IDs of all registered entities in no particular order
+func (m *Manager[K, T]) IDs() []K {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]K, 0, len(m.fsms))
+	for id := range m.fsms {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// BatchMode determines how TransitionMany handles failures
+type BatchMode int
+
+const (
+	// BestEffort transitions each entity independently, so some may succeed while others fail
+	BestEffort BatchMode = iota
+	// AllOrNothing prepares the transition of every entity first and only commits them if all can be applied
+	AllOrNothing
+)
+
+// BatchResult is the outcome of a batched transition for a single entity
+type BatchResult[K comparable, T comparable] struct {
+	ID    K
+	State T
+	Err   error
+}
+
+// TransitionMany transitions the FSMs of the given entities to targetState and returns one result per distinct ID,
+// in the order the IDs were given, along with an error joining the failures, if any
+// In AllOrNothing mode rules, cooldowns, budgets, guards and pre-commit hooks are checked for every entity before
+// any of them is committed. If one fails, none are committed and the others fail with ErrBatchAborted.
+// Entry and exit actions run after the batch is committed and may still fail individually
+func (m *Manager[K, T]) TransitionMany(ids []K, targetState T, metadata map[string]string, mode BatchMode) ([]BatchResult[K, T], error) {
+	ctx := context.Background()
+
+	results := make([]BatchResult[K, T], 0, len(ids))
+	fsms := make([]*FSM[T], 0, len(ids))
+	seen := make(map[K]bool, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		fsm, ok := m.Get(id)
+		results = append(results, BatchResult[K, T]{ID: id})
+		fsms = append(fsms, fsm)
+		if !ok {
+			results[len(results)-1].Err = fmt.Errorf("%w: %v", ErrEntityNotFound, id)
+		}
+	}
+
+	if mode == AllOrNothing {
+		m.transitionAll(ctx, fsms, results, targetState, metadata)
+	} else {
+		for i, fsm := range fsms {
+			if fsm != nil {
+				results[i].State, results[i].Err = fsm.TransitionCtx(ctx, targetState, metadata)
+			}
+		}
+	}
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", r.ID, r.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// transitionAll applies an all-or-nothing batch, filling in results
+func (m *Manager[K, T]) transitionAll(ctx context.Context, fsms []*FSM[T], results []BatchResult[K, T], targetState T, metadata map[string]string) {
+	m.batchMu.Lock()
+	defer m.batchMu.Unlock()
+
+	failed := false
+	for _, r := range results {
+		failed = failed || r.Err != nil
+	}
+
+	// Prepare every transition while holding all of the FSM locks, taken in the global lock order
+	// so that batches cannot deadlock with transactions spanning the same FSMs
+	var locking []*FSM[T]
+	if !failed {
+		locking = append(locking, fsms...)
+		sort.Slice(locking, func(i, j int) bool {
+			return locking[i].order() < locking[j].order()
+		})
+	}
+	for _, fsm := range locking {
+		fsm.mu.Lock()
+	}
+
+	prepared := make([]*Transition[T], len(fsms))
+	for i, fsm := range fsms {
+		if failed {
+			break
+		}
+
+		// Tags and extracted metadata are added as by TransitionCtx, per FSM
+		tagged := fsm.tag(ctx, metadata)
+		tr, err := fsm.prepare(ctx, targetState, tagged)
+		results[i].State = fsm.currentState
+		if err != nil {
+			fsm.recordFailure(ctx, targetState, tagged, err)
+			results[i].Err = fsm.attributeLocked(err)
+			failed = true
+		}
+		prepared[i] = tr
+	}
+
+	chains := make([][]*Transition[T], len(fsms))
+	chainErrs := make([]error, len(fsms))
+	for i, fsm := range fsms {
+		if !failed && prepared[i] != nil {
+			chains[i], chainErrs[i] = fsm.commitChain(ctx, prepared[i])
+			results[i].State = fsm.currentState
+		}
+	}
+
+	for _, fsm := range locking {
+		fsm.mu.Unlock()
+	}
+
+	for i, fsm := range fsms {
+		switch {
+		case failed && results[i].Err == nil:
+			results[i].Err = ErrBatchAborted
+			if fsm != nil {
+				results[i].State = fsm.CurrentState()
+			}
+		case failed:
+			if fsm != nil {
+				fsm.deadLetter(results[i].State, targetState, metadata, results[i].Err, 1)
+			}
+		case len(chains[i]) > 0:
+			var actionErr error
+			results[i].State, actionErr = fsm.afterCommitChain(ctx, chains[i])
+			results[i].Err = errors.Join(chainErrs[i], actionErr)
+		}
+	}
+}
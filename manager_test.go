@@ -0,0 +1,153 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func newOrderManager(t *testing.T) *Manager[int, string] {
+	manager := NewManager[int, string]()
+
+	for id := 1; id <= 3; id++ {
+		fsm := NewFSM[string]("created", 10)
+		fsm.AddRule("created", "picked")
+		fsm.AddRule("picked", "packed")
+		if err := manager.Add(id, fsm); err != nil {
+			t.Fatalf("Add() returned an error: %v", err)
+		}
+	}
+
+	return manager
+}
+
+func Test_manager(t *testing.T) {
+	manager := newOrderManager(t)
+
+	if err := manager.Add(1, NewFSM[string]("created", 10)); !errors.Is(err, ErrEntityExists) {
+		t.Errorf("Add() returned %v, expected ErrEntityExists", err)
+	}
+
+	if _, ok := manager.Get(2); !ok {
+		t.Errorf("Get() did not find entity 2")
+	}
+
+	manager.Remove(2)
+	if _, ok := manager.Get(2); ok || len(manager.IDs()) != 2 {
+		t.Errorf("Remove() did not remove entity 2")
+	}
+}
+
+func Test_managerAddRegisteredFSM(t *testing.T) {
+	manager := newOrderManager(t)
+
+	fsm, _ := manager.Get(1)
+	if err := manager.Add(4, fsm); !errors.Is(err, ErrFSMRegistered) {
+		t.Fatalf("Add() returned %v, expected ErrFSMRegistered", err)
+	}
+
+	// An all-or-nothing batch over the IDs must not lock the same FSM twice
+	if _, err := manager.TransitionMany([]int{1, 4}, "picked", nil, AllOrNothing); !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("TransitionMany() returned %v, expected ErrEntityNotFound", err)
+	}
+
+	manager.Remove(1)
+	if err := manager.Add(4, fsm); err != nil {
+		t.Errorf("Add() returned %v after the FSM was removed", err)
+	}
+}
+
+func Test_transitionManyBestEffort(t *testing.T) {
+	manager := newOrderManager(t)
+
+	fsm, _ := manager.Get(2)
+	fsm.Transition("picked", nil)
+
+	results, err := manager.TransitionMany([]int{1, 2, 3, 1, 4}, "picked", nil, BestEffort)
+	if err == nil {
+		t.Errorf("TransitionMany() returned no error")
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("TransitionMany() returned %d results, expected 4", len(results))
+	}
+
+	for i, expected := range []struct {
+		id    int
+		state string
+		err   error
+	}{
+		{1, "picked", nil},
+		{2, "picked", &TransitionError[string]{}},
+		{3, "picked", nil},
+		{4, "", ErrEntityNotFound},
+	} {
+		r := results[i]
+		if r.ID != expected.id || r.State != expected.state || (expected.err == nil) != (r.Err == nil) {
+			t.Errorf("Result %d is %+v, expected %+v", i, r, expected)
+		}
+		if target, ok := expected.err.(*TransitionError[string]); ok {
+			if !errors.As(r.Err, target) {
+				t.Errorf("Result %d has error %v, expected a TransitionError", i, r.Err)
+			}
+		} else if expected.err != nil && !errors.Is(r.Err, expected.err) {
+			t.Errorf("Result %d has error %v, expected %v", i, r.Err, expected.err)
+		}
+	}
+}
+
+func Test_transitionManyAllOrNothing(t *testing.T) {
+	manager := newOrderManager(t)
+
+	errNoStock := errors.New("no stock")
+	fsm, _ := manager.Get(3)
+	fsm.AddGuard("created", "picked", func(ctx context.Context, tr Transition[string]) error {
+		return errNoStock
+	})
+
+	results, err := manager.TransitionMany([]int{1, 2, 3}, "picked", nil, AllOrNothing)
+	if !errors.Is(err, errNoStock) || !errors.Is(err, ErrBatchAborted) {
+		t.Errorf("TransitionMany() returned %v, expected errors wrapping %v and ErrBatchAborted", err, errNoStock)
+	}
+
+	for _, r := range results {
+		if r.State != "created" {
+			t.Errorf("Entity %d is in %v after an aborted batch, expected created", r.ID, r.State)
+		}
+	}
+
+	if !errors.Is(results[0].Err, ErrBatchAborted) || !errors.Is(results[2].Err, errNoStock) {
+		t.Errorf("TransitionMany() returned results %+v", results)
+	}
+
+	// Without the failing entity the batch is committed
+	results, err = manager.TransitionMany([]int{1, 2}, "picked", nil, AllOrNothing)
+	if err != nil {
+		t.Fatalf("TransitionMany() returned an error: %v", err)
+	}
+
+	for _, r := range results {
+		if r.State != "picked" {
+			t.Errorf("Entity %d is in %v, expected picked", r.ID, r.State)
+		}
+	}
+
+	// An unknown entity aborts the whole batch
+	if _, err := manager.TransitionMany([]int{1, 5}, "packed", nil, AllOrNothing); !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("TransitionMany() returned %v, expected ErrEntityNotFound", err)
+	}
+
+	if fsm, _ := manager.Get(1); fsm.CurrentState() != "picked" {
+		t.Errorf("Entity 1 is in %v, expected picked", fsm.CurrentState())
+	}
+
+	// A same-state touch of an aborted batch is not recorded
+	touched, _ := manager.Get(1)
+	touched.SetSameStatePolicy(SameStateTouch)
+	if _, err := manager.TransitionMany([]int{1, 3}, "picked", nil, AllOrNothing); !errors.Is(err, ErrBatchAborted) {
+		t.Errorf("TransitionMany() returned %v, expected ErrBatchAborted", err)
+	}
+	if history := touched.Transitions(); history[len(history)-1].Touch {
+		t.Errorf("Entity 1 recorded a touch for an aborted batch: %v", history)
+	}
+}
@@ -0,0 +1,44 @@
+package statetrooper
+
+import "sort"
+
+// MarshalOptions controls what MarshalJSON emits in addition to the current state and transitions
+// The ruleset and history limit are informational and are ignored by UnmarshalJSON
+type MarshalOptions struct {
+	// IncludeRuleset adds the rules as a "ruleset" list of from states and their targets
+	IncludeRuleset bool
+	// IncludeMaxHistory adds the "max_history" limit
+	IncludeMaxHistory bool
+	// IncludeStats adds the aggregate counters as "stats", which UnmarshalJSON restores so that
+	// transition counts, budgets and the history summary survive restarts
+	IncludeStats bool
+}
+
+// RuleExport is the JSON form of the rules from a single state
+// A list is used rather than a map so that states need not be usable as JSON object keys
+type RuleExport[T comparable] struct {
+	From T   `json:"from"`
+	To   []T `json:"to"`
+}
+
+// SetMarshalOptions sets the options used by MarshalJSON
+func (fsm *FSM[T]) SetMarshalOptions(opts MarshalOptions) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.marshalOptions = opts
+}
+
+// exportRuleset returns the rules ordered by the string form of their from state. The caller must hold the lock
+func (fsm *FSM[T]) exportRuleset() []RuleExport[T] {
+	rules := make([]RuleExport[T], 0, len(fsm.ruleset))
+	for from, to := range fsm.ruleset {
+		rules = append(rules, RuleExport[T]{From: from, To: to})
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return toString(rules[i].From) < toString(rules[j].From)
+	})
+
+	return rules
+}
@@ -0,0 +1,40 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_marshalOptions(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 5)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+
+	data, err := json.Marshal(fsm)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	if strings.Contains(string(data), "ruleset") || strings.Contains(string(data), "max_history") {
+		t.Errorf("json.Marshal() returned %s, expected no ruleset or max_history by default", data)
+	}
+
+	fsm.SetMarshalOptions(MarshalOptions{IncludeRuleset: true, IncludeMaxHistory: true})
+
+	data, err = json.Marshal(fsm)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	expected := `"ruleset":[{"from":"A","to":["B","C"]},{"from":"B","to":["C"]}],"max_history":5`
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("json.Marshal() returned %s, expected it to contain %s", data, expected)
+	}
+
+	// The extra fields do not prevent loading the payload
+	restored := NewFSM[CustomStateEnum](CustomStateEnumA, 5)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Errorf("json.Unmarshal() returned an error: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package statetrooper
+
+import "sort"
+
+// AdjacencyMatrix returns the ruleset as a matrix together with the states indexing it
+// matrix[i][j] is true if there is a rule from states[i] to states[j]. The states are the registered
+// states, or if none are registered, the states referenced by rules, ordered by their string form
+func (fsm *FSM[T]) AdjacencyMatrix() ([][]bool, []T) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	seen := make(map[T]struct{})
+	for state := range fsm.states {
+		seen[state] = struct{}{}
+	}
+
+	if len(seen) == 0 {
+		for from, targets := range fsm.ruleset {
+			seen[from] = struct{}{}
+			for _, to := range targets {
+				seen[to] = struct{}{}
+			}
+		}
+	}
+
+	states := make([]T, 0, len(seen))
+	for state := range seen {
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return toString(states[i]) < toString(states[j])
+	})
+
+	index := make(map[T]int, len(states))
+	for i, state := range states {
+		index[state] = i
+	}
+
+	matrix := make([][]bool, len(states))
+	for i, from := range states {
+		matrix[i] = make([]bool, len(states))
+		for _, to := range fsm.ruleset[from] {
+			// Rules added before the states were registered may refer to unregistered states
+			if j, ok := index[to]; ok {
+				matrix[i][j] = true
+			}
+		}
+	}
+
+	return matrix, states
+}
@@ -0,0 +1,33 @@
+package statetrooper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_adjacencyMatrix(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+	fsm.AddRule(CustomStateEnumC, CustomStateEnumA)
+
+	matrix, states := fsm.AdjacencyMatrix()
+
+	if !reflect.DeepEqual(states, []CustomStateEnum{CustomStateEnumA, CustomStateEnumB, CustomStateEnumC}) {
+		t.Errorf("AdjacencyMatrix() returned states %v", states)
+	}
+
+	expected := [][]bool{
+		{false, true, true},
+		{false, false, false},
+		{true, false, false},
+	}
+	if !reflect.DeepEqual(matrix, expected) {
+		t.Errorf("AdjacencyMatrix() returned %v, expected %v", matrix, expected)
+	}
+
+	// Registered states without rules get their own row and column
+	fsm.RegisterStates(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC, CustomStateEnumD)
+	if matrix, states := fsm.AdjacencyMatrix(); len(states) != 4 || len(matrix[3]) != 4 {
+		t.Errorf("AdjacencyMatrix() returned %v for states %v, expected a 4x4 matrix", matrix, states)
+	}
+}
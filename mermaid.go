@@ -0,0 +1,109 @@
+package statetrooper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// mermaidArrow matches a directed flowchart or state diagram arrow with an optional label
+	mermaidArrow = regexp.MustCompile(`\s*(?:-->|==>|-\.->|--\s+[^>|]+?\s+-->|==\s+[^>|]+?\s+==>)\s*(?:\|[^|]*\|)?\s*`)
+
+	// mermaidNode matches a node ID, optionally followed by a shape such as [Label] or (Label)
+	mermaidNode = regexp.MustCompile(`^(\[\*\]|[\p{L}\p{N}_.-]+)\s*(?:[\[({>].*)?$`)
+)
+
+// mermaidSkipped lists the keywords of statements that do not declare transitions
+var mermaidSkipped = []string{"direction", "classDef", "class", "style", "linkStyle", "click", "subgraph", "end", "state", "note", "}"}
+
+// RulesFromMermaid builds a ruleset from Mermaid graph, flowchart or stateDiagram-v2 text, including the
+// output of GenerateMermaidRulesDiagram, with an edge a --> b becoming a rule from a to b
+// Edge labels, node shapes, chains (a --> b --> c) and node lists (a --> b & c) are supported;
+// edges to and from the [*] pseudo-state are ignored
+func RulesFromMermaid[T ~string](src string) (map[T][]T, error) {
+	rules := make(map[T][]T)
+	header := false
+	inNote := false
+
+	for _, line := range strings.Split(src, "\n") {
+		if i := strings.Index(line, "%%"); i >= 0 {
+			line = line[:i]
+		}
+
+		for _, stmt := range strings.Split(line, ";") {
+			stmt = strings.TrimSpace(stmt)
+			if stmt == "" {
+				continue
+			}
+
+			if !header {
+				kind := strings.Fields(stmt)[0]
+				if kind != "graph" && kind != "flowchart" && kind != "stateDiagram" && kind != "stateDiagram-v2" {
+					return nil, fmt.Errorf("%w: unsupported diagram type %q", ErrInvalidDiagram, kind)
+				}
+				header = true
+				continue
+			}
+
+			if inNote {
+				inNote = stmt != "end note"
+				continue
+			}
+
+			keyword := strings.Fields(stmt)[0]
+			if contains(mermaidSkipped, keyword) {
+				// A note without inline text spans the following lines up to "end note"
+				inNote = keyword == "note" && !strings.Contains(stmt, ":")
+				continue
+			}
+
+			if err := addMermaidEdges(rules, stmt); err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", ErrInvalidDiagram, stmt, err)
+			}
+		}
+	}
+
+	if !header {
+		return nil, fmt.Errorf("%w: empty diagram", ErrInvalidDiagram)
+	}
+
+	return rules, nil
+}
+
+// addMermaidEdges adds the rules declared by a single Mermaid statement
+func addMermaidEdges[T ~string](rules map[T][]T, stmt string) error {
+	// State diagram transition labels follow a colon
+	if i := strings.Index(stmt, " : "); i >= 0 {
+		stmt = stmt[:i]
+	}
+
+	parts := mermaidArrow.Split(stmt, -1)
+	var from []string
+
+	for i, part := range parts {
+		var nodes []string
+		for _, n := range strings.Split(part, "&") {
+			m := mermaidNode.FindStringSubmatch(strings.TrimSpace(n))
+			if m == nil {
+				return fmt.Errorf("invalid node %q", strings.TrimSpace(n))
+			}
+			nodes = append(nodes, m[1])
+		}
+
+		if i > 0 {
+			for _, f := range from {
+				for _, to := range nodes {
+					if f == "[*]" || to == "[*]" || contains(rules[T(f)], T(to)) {
+						continue
+					}
+					rules[T(f)] = append(rules[T(f)], T(to))
+				}
+			}
+		}
+
+		from = nodes
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_rulesFromMermaid(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"graph", `graph LR;
+			created;picked;canceled
+			created --> picked;
+			created -->|cancel| canceled;
+			picked --> packed --> shipped
+			picked -- cancel --> canceled`},
+		{"flowchart", `flowchart TD
+			%% order workflow
+			subgraph fulfilment
+			created[Created] --> picked(Picked) & canceled{Canceled}
+			end
+			picked ==> packed
+			packed -.-> shipped
+			picked --> canceled
+			classDef done fill:#f9f
+			class shipped done`},
+		{"stateDiagram", `stateDiagram-v2
+			direction LR
+			[*] --> created
+			created --> picked : pick
+			created --> canceled
+			state fulfilment {
+				picked --> packed
+				packed --> shipped
+			}
+			note right of picked
+				picked by the warehouse
+			end note
+			picked --> canceled
+			shipped --> [*]`},
+	}
+
+	expected := map[string][]string{
+		"created": {"picked", "canceled"},
+		"picked":  {"packed", "canceled"},
+		"packed":  {"shipped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := RulesFromMermaid[string](tt.src)
+			if err != nil {
+				t.Fatalf("RulesFromMermaid() returned an error: %v", err)
+			}
+
+			if !reflect.DeepEqual(rules, expected) {
+				t.Errorf("RulesFromMermaid() returned %v, expected %v", rules, expected)
+			}
+		})
+	}
+}
+
+func Test_rulesFromMermaidRoundTrip(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumD)
+
+	diagram, err := fsm.GenerateMermaidRulesDiagram()
+	if err != nil {
+		t.Fatalf("GenerateMermaidRulesDiagram() returned an error: %v", err)
+	}
+
+	rules, err := RulesFromMermaid[CustomStateEnum](diagram)
+	if err != nil {
+		t.Fatalf("RulesFromMermaid() returned an error: %v", err)
+	}
+
+	if equivalent, seq := EquivalentRulesets(fsm.Rules(), rules, []CustomStateEnum{CustomStateEnumA}); !equivalent {
+		t.Errorf("Re-imported rules differ, distinguishing sequence %v", seq)
+	}
+}
+
+func Test_rulesFromMermaidErrors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"sequenceDiagram\nA->>B: hi",
+		"graph LR\nA --- B",
+		"graph LR\nA --> ",
+	} {
+		if _, err := RulesFromMermaid[string](src); !errors.Is(err, ErrInvalidDiagram) {
+			t.Errorf("RulesFromMermaid(%q) returned %v, expected ErrInvalidDiagram", src, err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package statetrooper
+
+import "time"
+
+// SetMinDwell sets the minimum time the FSM must remain in state before any outbound transition is allowed,
+// for example for compliance holds or cooling-off periods. A zero duration removes the minimum
+func (fsm *FSM[T]) SetMinDwell(state T, d time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if d <= 0 {
+		delete(fsm.minDwells, state)
+		return
+	}
+
+	if fsm.minDwells == nil {
+		fsm.minDwells = make(map[T]time.Duration)
+	}
+
+	fsm.minDwells[state] = d
+}
+
+// checkMinDwell returns a MinDwellError if the FSM has not been in its current state for its minimum dwell at now
+func (fsm *FSM[T]) checkMinDwell(toState *T, now time.Time) error {
+	d, ok := fsm.minDwells[fsm.currentState]
+	if !ok {
+		return nil
+	}
+
+	if remaining := fsm.enteredAt.Add(d).Sub(now); remaining > 0 {
+		return MinDwellError[T]{
+			FromState: fsm.currentState,
+			ToState:   *toState,
+			MinDwell:  d,
+			Remaining: remaining,
+		}
+	}
+
+	return nil
+}
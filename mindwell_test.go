@@ -0,0 +1,67 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_minDwell(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	fsm := newPingPongFSM()
+	fsm.SetClock(func() time.Time { return now })
+	fsm.SetMinDwell(CustomStateEnumB, 5*time.Minute)
+
+	// Entering a state with a minimum dwell is not restricted
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Fatalf("Transition() returned an error: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	var dwellErr MinDwellError[CustomStateEnum]
+	_, err := fsm.Transition(CustomStateEnumA, nil)
+	if !errors.As(err, &dwellErr) || !errors.Is(err, ErrMinDwell) {
+		t.Fatalf("Transition() returned %v, expected a MinDwellError", err)
+	}
+
+	if dwellErr.Remaining != 3*time.Minute || dwellErr.MinDwell != 5*time.Minute {
+		t.Errorf("MinDwellError is %+v, expected 3m remaining of 5m", dwellErr)
+	}
+
+	if ex := fsm.Explain(CustomStateEnumA); ex.Reason != RejectMinDwell {
+		t.Errorf("Explain() returned reason %v, expected RejectMinDwell", ex.Reason)
+	}
+
+	now = now.Add(3 * time.Minute)
+	if _, err := fsm.Transition(CustomStateEnumA, nil); err != nil {
+		t.Errorf("Transition() returned an error after the minimum dwell: %v", err)
+	}
+
+	fsm.SetMinDwell(CustomStateEnumD, time.Minute)
+	if err := fsm.Validate(); err == nil {
+		t.Errorf("Validate() returned nil, expected it to report the minimum dwell on an undeclared state")
+	}
+}
+
+func Test_minDwellInitialStateWithClock(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.SetClock(func() time.Time { return now })
+	fsm.SetMinDwell(CustomStateEnumA, 5*time.Minute)
+
+	now = now.Add(2 * time.Minute)
+
+	var dwellErr MinDwellError[CustomStateEnum]
+	if _, err := fsm.Transition(CustomStateEnumB, nil); !errors.As(err, &dwellErr) || dwellErr.Remaining != 3*time.Minute {
+		t.Fatalf("Transition() returned %v, expected 3m of the minimum dwell to remain on the injected clock", err)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Errorf("Transition() returned an error after the minimum dwell: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package statetrooper
+
+import "context"
+
+// SetOutbox sets whether committed transitions are queued in an outbox for RelayOutbox to publish
+// A transition is queued under the same lock that commits it, and the outbox is serialized by MarshalJSON
+// along with the state, so persisting the FSM persists its pending events atomically. Disabling the outbox
+// discards the pending events
+func (fsm *FSM[T]) SetOutbox(enabled bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.outboxEnabled = enabled
+	if !enabled {
+		fsm.outbox = nil
+	}
+}
+
+// PendingEvents returns the transitions queued in the outbox that have not been relayed yet, oldest first
+func (fsm *FSM[T]) PendingEvents() []Transition[T] {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return append([]Transition[T](nil), fsm.outbox...)
+}
+
+// RelayOutbox publishes the pending events in order and removes each one from the outbox once publish returns
+// nil. It stops at the first error and returns it along with the number of events published
+// An event is only removed after it is published, so a crash in between publishes it again when the FSM is
+// restored. Consumers that deduplicate by the transition's ID therefore see each event exactly once
+// publish is called without holding the lock. Concurrent relays of the same FSM may publish an event twice
+func (fsm *FSM[T]) RelayOutbox(ctx context.Context, publish func(ctx context.Context, event Transition[T]) error) (int, error) {
+	fsm.mu.Lock()
+	pending := append([]Transition[T](nil), fsm.outbox...)
+	fsm.mu.Unlock()
+
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+
+		if err := publish(ctx, pending[i]); err != nil {
+			return i, err
+		}
+
+		fsm.ack(pending[i].ID)
+	}
+
+	return len(pending), nil
+}
+
+// enqueue adds a committed transition to the outbox, if enabled. The caller must hold the lock
+func (fsm *FSM[T]) enqueue(tr *Transition[T]) {
+	if fsm.outboxEnabled {
+		fsm.outbox = append(fsm.outbox, *tr)
+	}
+}
+
+// ack removes the events up to and including id from the outbox
+func (fsm *FSM[T]) ack(id uint64) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	n := 0
+	for n < len(fsm.outbox) && fsm.outbox[n].ID <= id {
+		n++
+	}
+
+	fsm.outbox = fsm.outbox[n:]
+}
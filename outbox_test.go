@@ -0,0 +1,70 @@
+package statetrooper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_outbox(t *testing.T) {
+	fsm := newPingPongFSM()
+	pingPong(fsm, 1)
+
+	if events := fsm.PendingEvents(); len(events) != 0 {
+		t.Errorf("PendingEvents() returned %v before the outbox was enabled", events)
+	}
+
+	fsm.SetOutbox(true)
+	pingPong(fsm, 3)
+
+	// The broker fails on the second event, which stays in the outbox with the third
+	var published []uint64
+	broker := func(ctx context.Context, event Transition[CustomStateEnum]) error {
+		if len(published) == 1 {
+			return errors.New("broker unavailable")
+		}
+		published = append(published, event.ID)
+		return nil
+	}
+
+	n, err := fsm.RelayOutbox(context.Background(), broker)
+	if n != 1 || err == nil || !reflect.DeepEqual(published, []uint64{2}) {
+		t.Errorf("RelayOutbox() returned %d, %v, expected 1 event and the broker's error", n, err)
+	}
+
+	// The pending events survive a restart
+	data, err := json.Marshal(fsm)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	restored := newPingPongFSM()
+	restored.SetOutbox(true)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	if events := restored.PendingEvents(); len(events) != 2 || events[0].ID != 3 {
+		t.Fatalf("PendingEvents() after restoring returned %v, expected transitions 3 and 4", events)
+	}
+
+	published = nil
+	broker = func(ctx context.Context, event Transition[CustomStateEnum]) error {
+		published = append(published, event.ID)
+		return nil
+	}
+
+	if n, err := restored.RelayOutbox(context.Background(), broker); n != 2 || err != nil {
+		t.Errorf("RelayOutbox() returned %d, %v, expected 2 events", n, err)
+	}
+
+	if expected := []uint64{3, 4}; !reflect.DeepEqual(published, expected) {
+		t.Errorf("Published %v, expected %v", published, expected)
+	}
+
+	if events := restored.PendingEvents(); len(events) != 0 {
+		t.Errorf("PendingEvents() returned %v after relaying, expected none", events)
+	}
+}
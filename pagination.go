@@ -0,0 +1,183 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// HistoryPage is a page of an FSM's history
+type HistoryPage[T comparable] struct {
+	Transitions []Transition[T] `json:"transitions"`
+	// Next is the cursor of the following page, zero if this is the last page
+	Next uint64 `json:"next,omitempty"`
+}
+
+// FeedEntry is a transition of one of the entities of a Manager
+type FeedEntry[K comparable, T comparable] struct {
+	ID         K             `json:"id"`
+	Transition Transition[T] `json:"transition"`
+}
+
+// FeedPage is a page of the transition feed of a Manager
+type FeedPage[K comparable, T comparable] struct {
+	Entries []FeedEntry[K, T] `json:"entries"`
+	// Next is the cursor of the following page, zero if this is the last page
+	Next uint64 `json:"next,omitempty"`
+}
+
+// HistoryPage returns up to limit retained transitions with an ID greater than after, oldest first
+// Pass zero to start from the beginning and the returned Next to fetch the following page
+// A limit of zero or less returns all remaining transitions
+func (fsm *FSM[T]) HistoryPage(after uint64, limit int) HistoryPage[T] {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.page(after, limit, false)
+}
+
+// page returns a page of the history, with its metadata redacted if redacted is set. The caller must hold the lock
+func (fsm *FSM[T]) page(after uint64, limit int, redacted bool) HistoryPage[T] {
+	// IDs increase along the history, so the first entry of the page can be found by binary search
+	start := sort.Search(len(fsm.transitions), func(i int) bool {
+		return fsm.transitions[i].ID > after
+	})
+
+	end := len(fsm.transitions)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	page := HistoryPage[T]{Transitions: make([]Transition[T], end-start)}
+	copy(page.Transitions, fsm.transitions[start:end])
+
+	if redacted {
+		page.Transitions = fsm.redact(page.Transitions)
+	}
+
+	if end < len(fsm.transitions) {
+		page.Next = page.Transitions[len(page.Transitions)-1].ID
+	}
+
+	return page
+}
+
+// Feed returns up to limit transitions of all entities with an ID greater than after, in ID order
+// Transition IDs act as the sequence numbers of the feed, so they must be unique across entities,
+// for example by setting one SequenceIDGenerator on all FSMs. Entries with equal IDs are ordered by entity ID
+// but may be skipped at a page boundary. A limit of zero or less returns all remaining transitions
+func (m *Manager[K, T]) Feed(after uint64, limit int) FeedPage[K, T] {
+	return m.feed(after, limit, false)
+}
+
+// feed returns a page of the feed, with metadata redacted by each FSM's rules if redacted is set
+func (m *Manager[K, T]) feed(after uint64, limit int, redacted bool) FeedPage[K, T] {
+	m.mu.RLock()
+	ids := make([]K, 0, len(m.fsms))
+	fsms := make([]*FSM[T], 0, len(m.fsms))
+	for id, fsm := range m.fsms {
+		ids = append(ids, id)
+		fsms = append(fsms, fsm)
+	}
+	m.mu.RUnlock()
+
+	// No entity can contribute more than limit entries, so each history is read one page at a time
+	var entries []FeedEntry[K, T]
+	more := false
+	for i, fsm := range fsms {
+		fsm.mu.Lock()
+		page := fsm.page(after, limit, redacted)
+		fsm.mu.Unlock()
+
+		for _, tr := range page.Transitions {
+			entries = append(entries, FeedEntry[K, T]{ID: ids[i], Transition: tr})
+		}
+		more = more || page.Next != 0
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Transition.ID != entries[j].Transition.ID {
+			return entries[i].Transition.ID < entries[j].Transition.ID
+		}
+		return fmt.Sprint(entries[i].ID) < fmt.Sprint(entries[j].ID)
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+		more = true
+	}
+
+	page := FeedPage[K, T]{Entries: entries}
+	if page.Entries == nil {
+		page.Entries = []FeedEntry[K, T]{}
+	}
+	if more {
+		page.Next = entries[len(entries)-1].Transition.ID
+	}
+
+	return page
+}
+
+// HistoryHandler returns an HTTP handler serving pages of the FSM's history as JSON, with metadata redacted
+// The cursor and page size are read from the "after" and "limit" query parameters
+func HistoryHandler[T comparable](fsm *FSM[T]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after, limit, err := pageParams(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fsm.mu.Lock()
+		page := fsm.page(after, limit, true)
+		fsm.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, page)
+	})
+}
+
+// FeedHandler returns an HTTP handler serving pages of the Manager's transition feed as JSON,
+// with metadata redacted. The cursor and page size are read from the "after" and "limit" query parameters
+func FeedHandler[K comparable, T comparable](m *Manager[K, T]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after, limit, err := pageParams(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, m.feed(after, limit, true))
+	})
+}
+
+// pageParams parses the "after" and "limit" query parameters, which default to zero
+func pageParams(r *http.Request) (uint64, int, error) {
+	query := r.URL.Query()
+
+	var after uint64
+	if v := query.Get("after"); v != "" {
+		var err error
+		if after, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid after: %q", v)
+		}
+	}
+
+	var limit int
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			return 0, 0, fmt.Errorf("invalid limit: %q", v)
+		}
+	}
+
+	return after, limit, nil
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
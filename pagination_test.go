@@ -0,0 +1,147 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_historyPage(t *testing.T) {
+	fsm := newPingPongFSM()
+	pingPong(fsm, 5)
+
+	var ids []uint64
+	var after uint64
+	for pages := 0; ; pages++ {
+		page := fsm.HistoryPage(after, 2)
+		for _, tr := range page.Transitions {
+			ids = append(ids, tr.ID)
+		}
+
+		if page.Next == 0 {
+			if pages != 2 {
+				t.Errorf("HistoryPage returned %d pages, expected 3", pages+1)
+			}
+			break
+		}
+		after = page.Next
+	}
+
+	if len(ids) != 5 || ids[0] != 1 || ids[4] != 5 {
+		t.Errorf("HistoryPage returned IDs %v, expected 1 to 5", ids)
+	}
+
+	if page := fsm.HistoryPage(0, 0); len(page.Transitions) != 5 || page.Next != 0 {
+		t.Errorf("HistoryPage without a limit returned %d transitions and cursor %d", len(page.Transitions), page.Next)
+	}
+
+	if page := fsm.HistoryPage(5, 2); len(page.Transitions) != 0 || page.Next != 0 {
+		t.Errorf("HistoryPage past the end returned %d transitions and cursor %d", len(page.Transitions), page.Next)
+	}
+}
+
+func Test_managerFeed(t *testing.T) {
+	seq := SequenceIDGenerator()
+	m := NewManager[string, CustomStateEnum]()
+
+	a, b := newPingPongFSM(), newPingPongFSM()
+	a.SetIDGenerator(seq)
+	b.SetIDGenerator(seq)
+	m.Add("a", a)
+	m.Add("b", b)
+
+	pingPong(a, 2)
+	pingPong(b, 1)
+	pingPong(a, 1)
+
+	var entities []string
+	var after uint64
+	for {
+		page := m.Feed(after, 3)
+		for _, entry := range page.Entries {
+			if entry.Transition.ID <= after {
+				t.Errorf("Feed returned ID %d after cursor %d", entry.Transition.ID, after)
+			}
+			after = entry.Transition.ID
+			entities = append(entities, entry.ID)
+		}
+
+		if page.Next == 0 {
+			break
+		}
+		after = page.Next
+	}
+
+	if got := strings.Join(entities, " "); got != "a a b a" {
+		t.Errorf("Feed returned entities %v, expected a a b a", got)
+	}
+}
+
+func Test_sequenceIDGenerator(t *testing.T) {
+	seq := SequenceIDGenerator()
+
+	if id := seq.NextID(0); id != 1 {
+		t.Errorf("NextID returned %d, expected 1", id)
+	}
+	if id := seq.NextID(10); id != 11 {
+		t.Errorf("NextID returned %d, expected 11 after an FSM's last ID of 10", id)
+	}
+	if id := seq.NextID(0); id != 12 {
+		t.Errorf("NextID returned %d, expected 12", id)
+	}
+}
+
+func Test_historyHandler(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetRedactionRules(RedactionRule{Pattern: "secret"})
+	fsm.Transition(CustomStateEnumB, map[string]string{"secret": "s3cr3t"})
+	fsm.Transition(CustomStateEnumA, nil)
+
+	rec := httptest.NewRecorder()
+	HistoryHandler(fsm).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history?limit=1", nil))
+
+	var page HistoryPage[CustomStateEnum]
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("HistoryHandler responded with %s, %v", rec.Body.String(), err)
+	}
+
+	if len(page.Transitions) != 1 || page.Next != 1 {
+		t.Errorf("HistoryHandler returned %d transitions and cursor %d, expected 1 and 1", len(page.Transitions), page.Next)
+	}
+	if got := page.Transitions[0].Metadata["secret"]; got != RedactedValue {
+		t.Errorf("HistoryHandler returned metadata %q, expected it to be redacted", got)
+	}
+
+	rec = httptest.NewRecorder()
+	HistoryHandler(fsm).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/history?after=x", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("HistoryHandler responded with %d to an invalid cursor, expected 400", rec.Code)
+	}
+}
+
+func Test_feedHandler(t *testing.T) {
+	m := NewManager[string, CustomStateEnum]()
+	fsm := newPingPongFSM()
+	m.Add("a", fsm)
+	pingPong(fsm, 2)
+
+	rec := httptest.NewRecorder()
+	FeedHandler(m).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed?after=1", nil))
+
+	var page FeedPage[string, CustomStateEnum]
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("FeedHandler responded with %s, %v", rec.Body.String(), err)
+	}
+
+	if len(page.Entries) != 1 || page.Entries[0].ID != "a" || page.Entries[0].Transition.ID != 2 || page.Next != 0 {
+		t.Errorf("FeedHandler returned %+v", page)
+	}
+
+	rec = httptest.NewRecorder()
+	FeedHandler(m).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed?limit=-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("FeedHandler responded with %d to an invalid limit, expected 400", rec.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package statetrooper
+
+import "sync"
+
+// SetPooling enables or disables recycling the memory of transitions, reducing allocations and GC pressure
+// for FSMs performing many transitions with history enabled. Prepared transition records are reused once
+// their hooks and listeners have returned, and the history keeps its backing array instead of growing a new one
+// as old entries are evicted. Transitions and the other history accessors return copies, so callers are unaffected,
+// but a history evict handler must not retain the slice it is passed after returning
+// Metadata maps and timestamps are shared with hooks and subscribers, so they are never recycled
+func (fsm *FSM[T]) SetPooling(enabled bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if !enabled {
+		fsm.transitionPool.Store(nil)
+		fsm.historyBuf = nil
+		return
+	}
+
+	if fsm.transitionPool.Load() == nil {
+		fsm.transitionPool.Store(&sync.Pool{
+			New: func() any { return new(Transition[T]) },
+		})
+	}
+}
+
+// newTransition returns a transition record, from the pool if pooling is enabled
+func (fsm *FSM[T]) newTransition() *Transition[T] {
+	if pool := fsm.transitionPool.Load(); pool != nil {
+		return pool.Get().(*Transition[T])
+	}
+
+	return new(Transition[T])
+}
+
+// release returns the transitions of a chain whose hooks and actions have finished to the pool
+// Their contents were copied into the history and to hooks, so the records themselves are no longer referenced
+func (fsm *FSM[T]) release(chain []*Transition[T]) {
+	pool := fsm.transitionPool.Load()
+	if pool == nil {
+		return
+	}
+
+	for _, tr := range chain {
+		*tr = Transition[T]{}
+		pool.Put(tr)
+	}
+}
+
+// reuseHistory moves the history to the front of its backing array when appending would otherwise allocate
+// a new array. The array is only reused once it is at least twice the history's length, so the cost of moving
+// is spread over as many appends. The caller must hold the lock
+func (fsm *FSM[T]) reuseHistory() {
+	n := len(fsm.transitions)
+	buf := fsm.historyBuf[:cap(fsm.historyBuf)]
+
+	if n == 0 || n < cap(fsm.transitions) || len(buf) < 2*n || !sameArray(buf, fsm.transitions) {
+		return
+	}
+
+	copy(buf, fsm.transitions)
+
+	// Clear the vacated entries so their metadata can be collected
+	var zero Transition[T]
+	for i := n; i < len(buf); i++ {
+		buf[i] = zero
+	}
+
+	fsm.transitions = buf[:n]
+}
+
+// sameArray reports whether a and b end at the same element of their backing arrays, which for slices of
+// the history means they share one
+func sameArray[E any](a, b []E) bool {
+	if cap(a) == 0 || cap(b) == 0 {
+		return false
+	}
+
+	return &a[:cap(a)][cap(a)-1] == &b[:cap(b)][cap(b)-1]
+}
@@ -0,0 +1,74 @@
+package statetrooper
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_pooling(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 3)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.SetPooling(true)
+
+	var seen []Transition[CustomStateEnum]
+	fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		seen = append(seen, tr)
+		return nil
+	})
+
+	before := fsm.Transitions()
+	pingPong(fsm, 50)
+
+	history := fsm.Transitions()
+	if len(history) != 3 {
+		t.Fatalf("history has %d transitions, expected 3", len(history))
+	}
+	for i, tr := range history {
+		if tr.ID != uint64(48+i) {
+			t.Errorf("history has IDs %d at %d, expected %d", tr.ID, i, 48+i)
+		}
+	}
+
+	if len(before) != 0 {
+		t.Errorf("a copy of the history taken earlier changed to %v", before)
+	}
+
+	for i, tr := range seen {
+		if tr.ID != uint64(i+1) || tr.Timestamp == nil {
+			t.Fatalf("hook received %+v as transition %d", tr, i+1)
+		}
+	}
+}
+
+func Test_poolingAllocations(t *testing.T) {
+	allocs := func(pooling bool) float64 {
+		fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 100)
+		fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+		fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+		fsm.SetPooling(pooling)
+
+		// Fill the history first so evictions are measured
+		pingPong(fsm, 1000)
+
+		return testing.AllocsPerRun(1000, func() {
+			pingPong(fsm, 1)
+		})
+	}
+
+	if pooled, unpooled := allocs(true), allocs(false); pooled >= unpooled {
+		t.Errorf("pooling made %v allocations per transition, expected fewer than %v", pooled, unpooled)
+	}
+}
+
+func Benchmark_pooledTransitions(b *testing.B) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.SetPooling(true)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pingPong(fsm, 2)
+	}
+}
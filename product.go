@@ -0,0 +1,53 @@
+package statetrooper
+
+import "fmt"
+
+// Pair is a state of a product machine, combining a state of each of two machines
+type Pair[A comparable, B comparable] struct {
+	First  A
+	Second B
+}
+
+// String returns the states of the pair joined by an underscore, so pairs can be used in Mermaid diagrams
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("%s_%s", toString(p.First), toString(p.Second))
+}
+
+// ProductRules builds the rules of the synchronous product of two rulesets, in which both machines transition
+// together: a pair may move to any pair of targets allowed by both rulesets
+// Only pairs reachable from start are included
+func ProductRules[A comparable, B comparable](a map[A][]A, b map[B][]B, start Pair[A, B]) map[Pair[A, B]][]Pair[A, B] {
+	rules := make(map[Pair[A, B]][]Pair[A, B])
+	visited := map[Pair[A, B]]bool{start: true}
+	queue := []Pair[A, B]{start}
+
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		for _, first := range a[p.First] {
+			for _, second := range b[p.Second] {
+				next := Pair[A, B]{First: first, Second: second}
+				rules[p] = append(rules[p], next)
+
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	return rules
+}
+
+// NewProduct creates an FSM for the synchronous product of two FSMs, starting at the pair of their current states
+// It is a snapshot for verification and diagrams; it is not kept in sync with a or b
+func NewProduct[A comparable, B comparable](a *FSM[A], b *FSM[B], maxHistory int) *FSM[Pair[A, B]] {
+	start := Pair[A, B]{First: a.CurrentState(), Second: b.CurrentState()}
+
+	fsm := NewFSM[Pair[A, B]](start, maxHistory)
+	fsm.ruleset = ProductRules(a.Rules(), b.Rules(), start)
+
+	return fsm
+}
@@ -0,0 +1,47 @@
+package statetrooper
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_product(t *testing.T) {
+	order := NewFSM[string]("placed", 10)
+	order.AddRule("placed", "paid", "canceled")
+	order.AddRule("paid", "shipped")
+
+	payment := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	payment.AddRule(CustomStateEnumA, CustomStateEnumB)
+	payment.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	product := NewProduct(order, payment, 10)
+
+	start := Pair[string, CustomStateEnum]{"placed", CustomStateEnumA}
+	if product.CurrentState() != start {
+		t.Errorf("Product starts in %v, expected %v", product.CurrentState(), start)
+	}
+
+	rules := product.Rules()
+	if len(rules) != 2 {
+		t.Errorf("Product has rules from %d pairs, expected 2: %v", len(rules), rules)
+	}
+
+	// Both machines move together
+	if !product.CanTransition(Pair[string, CustomStateEnum]{"paid", CustomStateEnumB}) ||
+		product.CanTransition(Pair[string, CustomStateEnum]{"paid", CustomStateEnumA}) {
+		t.Errorf("Product allows unexpected transitions: %v", rules)
+	}
+
+	if _, err := product.Transition(Pair[string, CustomStateEnum]{"paid", CustomStateEnumB}, nil); err != nil {
+		t.Fatalf("Transition() returned an error: %v", err)
+	}
+
+	if !product.CanTransition(Pair[string, CustomStateEnum]{"shipped", CustomStateEnumC}) {
+		t.Errorf("Expected transition to shipped_C to be allowed")
+	}
+
+	diagram, err := product.GenerateMermaidRulesDiagram()
+	if err != nil || !strings.Contains(diagram, "placed_A --> paid_B;") {
+		t.Errorf("GenerateMermaidRulesDiagram() returned %q, %v", diagram, err)
+	}
+}
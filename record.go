@@ -0,0 +1,180 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// RecordedInput is a transition attempt captured by a Recorder
+// Errors are kept as strings so recordings can be persisted as JSON
+type RecordedInput[T comparable] struct {
+	FromState T                 `json:"from_state"`
+	Target    T                 `json:"target"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Time      time.Time         `json:"time"`
+	Guarded   bool              `json:"guarded,omitempty"`
+	GuardErr  string            `json:"guard_err,omitempty"`
+	State     T                 `json:"state"`
+	Err       string            `json:"err,omitempty"`
+}
+
+// Recorder captures every transition attempt of the FSMs it is attached to
+type Recorder[T comparable] struct {
+	mu     sync.Mutex
+	inputs []RecordedInput[T]
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder[T comparable]() *Recorder[T] {
+	return &Recorder[T]{}
+}
+
+// Inputs returns the recorded transition attempts in the order they were made
+func (r *Recorder[T]) Inputs() []RecordedInput[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inputs := make([]RecordedInput[T], len(r.inputs))
+	copy(inputs, r.inputs)
+
+	return inputs
+}
+
+func (r *Recorder[T]) add(in RecordedInput[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inputs = append(r.inputs, in)
+}
+
+// Divergence describes a replayed input whose outcome differs from the recorded one
+type Divergence[T comparable] struct {
+	Index int
+	Input RecordedInput[T]
+	State T
+	Err   error
+}
+
+// SetRecorder attaches a Recorder that captures the target, metadata, clock reading, guard outcome and
+// result of every transition attempt
+// Passing nil stops recording
+func (fsm *FSM[T]) SetRecorder(recorder *Recorder[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.recorder = recorder
+}
+
+// Replay runs recorded inputs against the FSM and returns the inputs whose resulting state or success differ
+// from the recording
+// The FSM's clock reads the recorded time and guards evaluated during recording are replaced by their
+// recorded outcome, so a machine can be checked against historical traffic deterministically
+// Hooks and actions of the FSM run as usual; Replay is intended for a fresh FSM that is not used concurrently
+func (fsm *FSM[T]) Replay(inputs []RecordedInput[T]) []Divergence[T] {
+	var current time.Time
+
+	fsm.mu.Lock()
+	clock := fsm.now
+	fsm.now = func() time.Time { return current }
+	fsm.mu.Unlock()
+
+	defer func() {
+		fsm.mu.Lock()
+		fsm.now = clock
+		fsm.mu.Unlock()
+	}()
+
+	var divergences []Divergence[T]
+	for i, in := range inputs {
+		current = in.Time
+
+		ctx := context.WithValue(context.Background(), replayKey{}, replaying[T]{fsm: fsm, in: in})
+		state, err := fsm.TransitionCtx(ctx, in.Target, in.Metadata)
+		if state != in.State || (err != nil) != (in.Err != "") {
+			divergences = append(divergences, Divergence[T]{Index: i, Input: in, State: state, Err: err})
+		}
+	}
+
+	return divergences
+}
+
+// recordKey is the context key of the recording of a transition attempt
+type recordKey struct{}
+
+// replayKey is the context key of the replaying of a transition attempt
+type replayKey struct{}
+
+// recording is the RecordedInput being captured for a transition attempt of fsm
+// Recordings and replayings are scoped to their FSM, as the context also reaches transitions of other FSMs
+// started from post-commit hooks, such as triggers
+type recording[T comparable] struct {
+	fsm *FSM[T]
+	in  *RecordedInput[T]
+}
+
+// replaying is the RecordedInput being replayed by fsm
+type replaying[T comparable] struct {
+	fsm *FSM[T]
+	in  RecordedInput[T]
+}
+
+// startRecording returns ctx carrying a new RecordedInput if the FSM has a recorder
+func (fsm *FSM[T]) startRecording(ctx context.Context, targetState T, metadata map[string]string) (context.Context, *Recorder[T], *RecordedInput[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.recorder == nil {
+		return ctx, nil, nil
+	}
+
+	in := &RecordedInput[T]{
+		FromState: fsm.currentState,
+		Target:    targetState,
+		Metadata:  metadata,
+	}
+
+	return context.WithValue(ctx, recordKey{}, recording[T]{fsm: fsm, in: in}), fsm.recorder, in
+}
+
+// recorded returns the RecordedInput being captured for a transition attempt of fsm in ctx, or nil
+func (fsm *FSM[T]) recorded(ctx context.Context) *RecordedInput[T] {
+	if r, ok := ctx.Value(recordKey{}).(recording[T]); ok && r.fsm == fsm {
+		return r.in
+	}
+
+	return nil
+}
+
+// recordClock stores the clock reading of a transition attempt being recorded
+func (fsm *FSM[T]) recordClock(ctx context.Context, now time.Time) {
+	if in := fsm.recorded(ctx); in != nil {
+		in.Time = now
+	}
+}
+
+// recordGuards stores the guard outcome of a transition attempt being recorded
+func (fsm *FSM[T]) recordGuards(ctx context.Context, err error) {
+	if in := fsm.recorded(ctx); in != nil {
+		in.Guarded = true
+		if err != nil {
+			in.GuardErr = err.Error()
+		}
+	}
+}
+
+// replayedGuards reports whether a transition attempt of fsm is being replayed and its guards were
+// evaluated during recording, and if so returns the recorded guard error
+func (fsm *FSM[T]) replayedGuards(ctx context.Context) (bool, error) {
+	r, ok := ctx.Value(replayKey{}).(replaying[T])
+	if !ok || r.fsm != fsm || !r.in.Guarded {
+		return false, nil
+	}
+
+	if r.in.GuardErr == "" {
+		return true, nil
+	}
+
+	return true, errors.New(r.in.GuardErr)
+}
@@ -0,0 +1,120 @@
+package statetrooper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_recordReplay(t *testing.T) {
+	newMachine := func() *FSM[string] {
+		fsm := NewFSM[string]("created", 10)
+		fsm.AddRule("created", "paid", "canceled")
+		fsm.AddRule("paid", "shipped")
+		return fsm
+	}
+
+	fsm := newMachine()
+	fsm.SetCooldown(time.Minute)
+
+	approved := false
+	fsm.AddGuard("created", "paid", func(ctx context.Context, tr Transition[string]) error {
+		if !approved {
+			return errors.New("payment pending")
+		}
+		return nil
+	})
+
+	recorder := NewRecorder[string]()
+	fsm.SetRecorder(recorder)
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	fsm.SetClock(func() time.Time { return now })
+
+	fsm.Transition("paid", nil)
+	approved = true
+	now = start.Add(2 * time.Minute)
+	fsm.Transition("paid", map[string]string{"ref": "1"})
+	now = start.Add(2*time.Minute + time.Second)
+	fsm.Transition("shipped", nil) // rejected by the cooldown
+	now = start.Add(5 * time.Minute)
+	fsm.Transition("shipped", nil)
+
+	inputs := recorder.Inputs()
+	if len(inputs) != 4 {
+		t.Fatalf("Recorded %d inputs, expected 4", len(inputs))
+	}
+
+	if !inputs[0].Guarded || inputs[0].GuardErr != "payment pending" || inputs[0].Err == "" {
+		t.Errorf("First input recorded as %+v, expected a guard rejection", inputs[0])
+	}
+
+	if !inputs[3].Time.Equal(start.Add(5*time.Minute)) || inputs[3].State != "shipped" {
+		t.Errorf("Last input recorded as %+v", inputs[3])
+	}
+
+	// Recordings survive a JSON round trip
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	var restored []RecordedInput[string]
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	// The same machine without the guard behaves identically using the recorded guard outcomes
+	same := newMachine()
+	same.SetCooldown(time.Minute)
+	if divergences := same.Replay(restored); len(divergences) != 0 {
+		t.Errorf("Replay() returned divergences %+v, expected none", divergences)
+	}
+
+	if !same.timeNow().After(start.Add(time.Hour)) {
+		t.Errorf("Replay() did not restore the clock")
+	}
+
+	// Without the cooldown the third input now succeeds and everything after it diverges
+	changed := newMachine()
+	divergences := changed.Replay(restored)
+	if len(divergences) != 2 || divergences[0].Index != 2 || divergences[0].State != "shipped" {
+		t.Errorf("Replay() returned divergences %+v, expected inputs 2 and 3 to diverge", divergences)
+	}
+}
+
+func Test_recordTriggeredTransitions(t *testing.T) {
+	manager := NewManager[string, string]()
+
+	order := NewFSM[string]("created", 10)
+	order.AddRule("created", "paid")
+
+	invoice := NewFSM[string]("open", 10)
+	invoice.AddRule("open", "settled")
+	invoice.AddGuard("open", "settled", func(ctx context.Context, tr Transition[string]) error {
+		return errors.New("invoice says no")
+	})
+
+	manager.Add("order-1", order)
+	manager.Add("invoice-1", invoice)
+	manager.AddTrigger("order-1", "paid", "invoice-1", "settled")
+
+	recorder := NewRecorder[string]()
+	order.SetRecorder(recorder)
+	order.Transition("paid", nil)
+
+	// The triggered transition of the invoice is not part of the order's recording
+	inputs := recorder.Inputs()
+	if len(inputs) != 1 || inputs[0].Guarded || inputs[0].GuardErr != "" {
+		t.Fatalf("Recorded %+v, expected one input without guards", inputs)
+	}
+
+	replayed := NewFSM[string]("created", 10)
+	replayed.AddRule("created", "paid")
+	if divergences := replayed.Replay(inputs); len(divergences) != 0 {
+		t.Errorf("Replay() returned divergences %+v, expected none", divergences)
+	}
+}
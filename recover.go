@@ -0,0 +1,180 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Conflict is an entity whose FSMs differ between the primary and secondary source of a recovery
+type Conflict[K comparable, T comparable] struct {
+	ID        K
+	Primary   *FSM[T]
+	Secondary *FSM[T]
+}
+
+// ConflictResolver chooses the FSM to recover for a conflicting entity
+// It returns an error if it cannot reconcile the two, in which case the entity is not recovered
+type ConflictResolver[K comparable, T comparable] func(c Conflict[K, T]) (*FSM[T], error)
+
+// PreferLatest resolves conflicts in favor of the FSM whose last transition is the most recent
+// Conflicts whose last transitions have the same time cannot be resolved
+func PreferLatest[K comparable, T comparable]() ConflictResolver[K, T] {
+	return func(c Conflict[K, T]) (*FSM[T], error) {
+		p, s := lastTransitionTime(c.Primary), lastTransitionTime(c.Secondary)
+
+		switch {
+		case p.After(s):
+			return c.Primary, nil
+		case s.After(p):
+			return c.Secondary, nil
+		default:
+			return nil, fmt.Errorf("%w: both sources last changed at %v", ErrConflict, p)
+		}
+	}
+}
+
+// RecoveryReport describes the outcome of Manager.Recover
+type RecoveryReport[K comparable] struct {
+	// Recovered lists the entities added to the Manager, ordered by the string form of their IDs
+	Recovered []K
+	// Resolved lists the recovered entities whose sources conflicted and were reconciled
+	Resolved []K
+	// Failed holds the entities that could not be recovered and why
+	Failed map[K]error
+}
+
+// Recover hydrates the Manager at startup from the NDJSON written by Export
+// Each entity's FSM is created with newFSM, so it has its rules and configuration, and its state and history
+// are then loaded from the export. If secondary is not nil, an entity that only loads from one source is
+// recovered from it, and entities whose FSMs in both sources are not Equal, including their histories, are
+// passed to resolve. Without a resolver, such conflicts are not recovered
+// Entities that cannot be loaded, reconciled or added are reported rather than aborting the recovery
+// An error is only returned if a source cannot be read
+func (m *Manager[K, T]) Recover(primary io.Reader, secondary io.Reader, newFSM func(id K) *FSM[T], resolve ConflictResolver[K, T]) (RecoveryReport[K], error) {
+	report := RecoveryReport[K]{Failed: make(map[K]error)}
+
+	fsms, failed, err := readEntities(primary, newFSM)
+	if err != nil {
+		return report, err
+	}
+
+	resolved := make(map[K]bool)
+	if secondary != nil {
+		others, otherFailed, err := readEntities(secondary, newFSM)
+		if err != nil {
+			return report, err
+		}
+
+		for id, err := range otherFailed {
+			if _, ok := failed[id]; !ok {
+				failed[id] = err
+			}
+		}
+
+		for id, other := range others {
+			fsm, ok := fsms[id]
+			switch {
+			case !ok:
+				fsms[id] = other
+			case fsm.Equal(other, EqualOptions{CompareHistory: true}):
+			default:
+				chosen, err := resolveConflict(Conflict[K, T]{ID: id, Primary: fsm, Secondary: other}, resolve)
+				if err != nil {
+					delete(fsms, id)
+					failed[id] = err
+					continue
+				}
+
+				fsms[id] = chosen
+				resolved[id] = true
+			}
+		}
+	}
+
+	// Entities that failed in one source but loaded from the other are recovered
+	for id, err := range failed {
+		if _, ok := fsms[id]; !ok {
+			report.Failed[id] = err
+		}
+	}
+
+	ids := make([]K, 0, len(fsms))
+	for id := range fsms {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return fmt.Sprint(ids[i]) < fmt.Sprint(ids[j])
+	})
+
+	for _, id := range ids {
+		if err := m.Add(id, fsms[id]); err != nil {
+			report.Failed[id] = err
+			continue
+		}
+
+		report.Recovered = append(report.Recovered, id)
+		if resolved[id] {
+			report.Resolved = append(report.Resolved, id)
+		}
+	}
+
+	return report, nil
+}
+
+// resolveConflict calls resolve, failing with ErrConflict if there is no resolver
+func resolveConflict[K comparable, T comparable](c Conflict[K, T], resolve ConflictResolver[K, T]) (*FSM[T], error) {
+	if resolve == nil {
+		return nil, fmt.Errorf("%w: no resolver", ErrConflict)
+	}
+
+	return resolve(c)
+}
+
+// readEntities decodes the entities exported by Manager.Export, returning those that fail to load separately
+func readEntities[K comparable, T comparable](r io.Reader, newFSM func(id K) *FSM[T]) (map[K]*FSM[T], map[K]error, error) {
+	fsms := make(map[K]*FSM[T])
+	failed := make(map[K]error)
+
+	dec := json.NewDecoder(r)
+	for {
+		var entity struct {
+			ID  K               `json:"id"`
+			FSM json.RawMessage `json:"fsm"`
+		}
+
+		if err := dec.Decode(&entity); err == io.EOF {
+			return fsms, failed, nil
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		fsm := newFSM(entity.ID)
+		if err := json.Unmarshal(entity.FSM, fsm); err != nil {
+			failed[entity.ID] = err
+			continue
+		}
+
+		fsms[entity.ID] = fsm
+	}
+}
+
+// lastTransitionTime returns the time of the FSM's last recorded transition, or the zero time
+func lastTransitionTime[T comparable](fsm *FSM[T]) (last time.Time) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	for i := len(fsm.transitions) - 1; i >= 0; i-- {
+		tr := &fsm.transitions[i]
+		if tr.Until != nil {
+			return *tr.Until
+		}
+		if tr.Timestamp != nil {
+			return *tr.Timestamp
+		}
+	}
+
+	return last
+}
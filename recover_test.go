@@ -0,0 +1,91 @@
+package statetrooper
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_recover(t *testing.T) {
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	// at returns an FSM that transitioned to B at the given offset from start
+	at := func(offset time.Duration) *FSM[CustomStateEnum] {
+		fsm := newPingPongFSM()
+		fsm.SetClock(func() time.Time { return start.Add(offset) })
+		fsm.Transition(CustomStateEnumB, nil)
+		return fsm
+	}
+
+	export := func(entities map[string]*FSM[CustomStateEnum]) *bytes.Buffer {
+		m := NewManager[string, CustomStateEnum]()
+		for id, fsm := range entities {
+			m.Add(id, fsm)
+		}
+
+		var buf bytes.Buffer
+		if err := m.Export(&buf); err != nil {
+			t.Fatalf("Export() returned an error: %v", err)
+		}
+		return &buf
+	}
+
+	unsupported := newPingPongFSM()
+	unsupported.SetVersion(2)
+
+	primary := export(map[string]*FSM[CustomStateEnum]{
+		"same":    at(0),
+		"newer":   at(0),
+		"tie":     at(0),
+		"broken":  unsupported,
+		"primary": at(0),
+	})
+
+	// The tie's sources differ but last changed at the same time
+	tie := at(0)
+	tie.Transition(CustomStateEnumA, nil)
+
+	secondary := export(map[string]*FSM[CustomStateEnum]{
+		"same":      at(0),
+		"newer":     at(time.Minute),
+		"tie":       tie,
+		"broken":    at(0),
+		"secondary": at(0),
+	})
+
+	m := NewManager[string, CustomStateEnum]()
+	report, err := m.Recover(primary, secondary, func(id string) *FSM[CustomStateEnum] { return newPingPongFSM() }, PreferLatest[string, CustomStateEnum]())
+	if err != nil {
+		t.Fatalf("Recover() returned an error: %v", err)
+	}
+
+	if expected := []string{"broken", "newer", "primary", "same", "secondary"}; !reflect.DeepEqual(report.Recovered, expected) {
+		t.Errorf("Recover() recovered %v, expected %v", report.Recovered, expected)
+	}
+
+	if expected := []string{"newer"}; !reflect.DeepEqual(report.Resolved, expected) {
+		t.Errorf("Recover() resolved %v, expected %v", report.Resolved, expected)
+	}
+
+	if len(report.Failed) != 1 || !errors.Is(report.Failed["tie"], ErrConflict) {
+		t.Errorf("Recover() failed %v, expected only the tie with ErrConflict", report.Failed)
+	}
+
+	newer, _ := m.Get("newer")
+	if trs := newer.Transitions(); len(trs) != 1 || !trs[0].Timestamp.Equal(start.Add(time.Minute)) {
+		t.Errorf("Recovered history %v, expected the secondary's later transition", trs)
+	}
+
+	// Without a secondary source, entities that fail to load are reported
+	m = NewManager[string, CustomStateEnum]()
+	report, err = m.Recover(export(map[string]*FSM[CustomStateEnum]{"broken": unsupported}), nil, func(id string) *FSM[CustomStateEnum] { return newPingPongFSM() }, nil)
+	if err != nil || len(report.Recovered) != 0 || !errors.Is(report.Failed["broken"], ErrUnsupportedVersion) {
+		t.Errorf("Recover() returned %+v, %v, expected broken to fail with ErrUnsupportedVersion", report, err)
+	}
+
+	if _, err := m.Recover(bytes.NewBufferString("{"), nil, nil, nil); err == nil {
+		t.Errorf("Recover() returned no error for an unreadable source")
+	}
+}
@@ -0,0 +1,178 @@
+package statetrooper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+)
+
+// RedactAction determines how a redacted metadata value is exported
+type RedactAction int
+
+const (
+	// RedactMask replaces the value with RedactedValue
+	RedactMask RedactAction = iota
+	// RedactHash replaces the value with its hex-encoded SHA-256 hash, so equal values can still be correlated
+	RedactHash
+	// RedactDrop removes the key entirely
+	RedactDrop
+)
+
+// RedactedValue replaces metadata values masked with RedactMask
+const RedactedValue = "[REDACTED]"
+
+// RedactionRule redacts exported metadata whose key matches Pattern
+// Pattern uses path.Match syntax, for example "email" or "customer_*"
+type RedactionRule struct {
+	Pattern string
+	Action  RedactAction
+}
+
+// SetRedactionRules sets the rules applied to transition metadata by MarshalJSON and ExportHistoryCompressed
+// The first matching rule applies to each key. The in-memory history is left untouched,
+// so Transitions and hooks still see the full metadata. Calling it with no rules disables redaction
+func (fsm *FSM[T]) SetRedactionRules(rules ...RedactionRule) error {
+	for _, rule := range rules {
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return err
+		}
+	}
+
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.redactionRules = append([]RedactionRule(nil), rules...)
+
+	return nil
+}
+
+// redact returns transitions with their metadata redacted. The caller must hold the lock
+// transitions is returned as is when there are no rules
+func (fsm *FSM[T]) redact(transitions []Transition[T]) []Transition[T] {
+	if len(fsm.redactionRules) == 0 {
+		return transitions
+	}
+
+	redacted := make([]Transition[T], len(transitions))
+	for i, tr := range transitions {
+		redacted[i] = tr
+		redacted[i].Metadata = fsm.redactMetadata(tr.Metadata)
+
+		if tr.Amendments != nil {
+			redacted[i].Amendments = make([]Amendment, len(tr.Amendments))
+			for j, a := range tr.Amendments {
+				redacted[i].Amendments[j] = a
+				redacted[i].Amendments[j].Metadata = fsm.redactMetadata(a.Metadata)
+				redacted[i].Amendments[j].Previous = fsm.redactMetadata(a.Previous)
+			}
+		}
+	}
+
+	return redacted
+}
+
+// redactMetadata returns a redacted copy of metadata
+func (fsm *FSM[T]) redactMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		rule, ok := fsm.redactionRule(key)
+		if !ok {
+			redacted[key] = value
+			continue
+		}
+
+		switch rule.Action {
+		case RedactMask:
+			redacted[key] = RedactedValue
+		case RedactHash:
+			sum := sha256.Sum256([]byte(value))
+			redacted[key] = hex.EncodeToString(sum[:])
+		}
+	}
+
+	return redacted
+}
+
+// redactionRule returns the first rule matching key
+func (fsm *FSM[T]) redactionRule(key string) (RedactionRule, bool) {
+	for _, rule := range fsm.redactionRules {
+		if ok, _ := path.Match(rule.Pattern, key); ok {
+			return rule, true
+		}
+	}
+
+	return RedactionRule{}, false
+}
+
+// ScrubMetadata removes every metadata entry for which predicate returns true from the whole history,
+// including amendments, and from the events pending in the outbox, for example to honor an erasure request
+// States and timestamps are kept so the audit trail stays intact. It returns the number of entries removed
+func (fsm *FSM[T]) ScrubMetadata(predicate func(key, value string) bool) int {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	removed := 0
+	for _, transitions := range [][]Transition[T]{fsm.transitions, fsm.outbox} {
+		for i := range transitions {
+			removed += scrubTransition(&transitions[i], predicate)
+		}
+	}
+
+	return removed
+}
+
+// scrubTransition removes the metadata entries for which predicate returns true from tr and its amendments
+// and returns the number removed
+func scrubTransition[T comparable](tr *Transition[T], predicate func(key, value string) bool) int {
+	var removed int
+	tr.Metadata, removed = scrub(tr.Metadata, predicate)
+
+	copied := false
+	for j, a := range tr.Amendments {
+		scrubbed, n := scrub(a.Metadata, predicate)
+		previous, m := scrub(a.Previous, predicate)
+		if n+m == 0 {
+			continue
+		}
+
+		// Amendments may be shared with callers as well, so the slice is replaced too
+		if !copied {
+			tr.Amendments = append([]Amendment(nil), tr.Amendments...)
+			copied = true
+		}
+		tr.Amendments[j].Metadata = scrubbed
+		tr.Amendments[j].Previous = previous
+		removed += n + m
+	}
+
+	return removed
+}
+
+// scrub returns metadata without the entries for which predicate returns true and the number removed
+// Metadata maps may be shared with callers, so a copy is returned rather than modifying metadata
+func scrub(metadata map[string]string, predicate func(key, value string) bool) (map[string]string, int) {
+	var scrubbed map[string]string
+	removed := 0
+
+	for key, value := range metadata {
+		if !predicate(key, value) {
+			continue
+		}
+
+		if scrubbed == nil {
+			scrubbed = cloneMap(metadata)
+		}
+		delete(scrubbed, key)
+		removed++
+	}
+
+	if scrubbed == nil {
+		return metadata, 0
+	}
+
+	return scrubbed, removed
+}
@@ -0,0 +1,142 @@
+package statetrooper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_redactionRules(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "paid")
+	fsm.Transition("paid", map[string]string{
+		"email":          "customer@example.com",
+		"customer_name":  "Jane",
+		"customer_phone": "555-0100",
+		"order":          "42",
+	})
+
+	if err := fsm.SetRedactionRules(
+		RedactionRule{Pattern: "email", Action: RedactHash},
+		RedactionRule{Pattern: "customer_phone", Action: RedactDrop},
+		RedactionRule{Pattern: "customer_*", Action: RedactMask},
+	); err != nil {
+		t.Fatalf("SetRedactionRules() returned an error: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("customer@example.com"))
+	expected := map[string]string{
+		"email":         hex.EncodeToString(sum[:]),
+		"customer_name": RedactedValue,
+		"order":         "42",
+	}
+
+	data, err := json.Marshal(fsm)
+	if err != nil {
+		t.Fatalf("MarshalJSON() returned an error: %v", err)
+	}
+
+	var snapshot struct {
+		Transitions []Transition[string] `json:"transitions"`
+	}
+	json.Unmarshal(data, &snapshot)
+	if !reflect.DeepEqual(snapshot.Transitions[0].Metadata, expected) {
+		t.Errorf("MarshalJSON() metadata is %v, expected %v", snapshot.Transitions[0].Metadata, expected)
+	}
+
+	var buf bytes.Buffer
+	if err := fsm.ExportHistoryCompressed(&buf); err != nil {
+		t.Fatalf("ExportHistoryCompressed() returned an error: %v", err)
+	}
+	zr, _ := gzip.NewReader(&buf)
+	raw, _ := io.ReadAll(zr)
+	if strings.Contains(string(raw), "customer@example.com") || strings.Contains(string(raw), "555-0100") {
+		t.Errorf("ExportHistoryCompressed() leaked metadata: %s", raw)
+	}
+
+	// The in-memory history keeps full fidelity
+	if got := fsm.Transitions()[0].Metadata["email"]; got != "customer@example.com" {
+		t.Errorf("In-memory metadata was redacted to %q", got)
+	}
+
+	if err := fsm.SetRedactionRules(RedactionRule{Pattern: "["}); err == nil {
+		t.Errorf("SetRedactionRules() accepted an invalid pattern")
+	}
+}
+
+func Test_scrubMetadata(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "paid")
+	fsm.AddRule("paid", "shipped")
+
+	paid := map[string]string{"customer": "jane", "order": "42"}
+	fsm.Transition("paid", paid)
+	fsm.Transition("shipped", map[string]string{"address": "1 Main St", "customer": "jane"})
+
+	removed := fsm.ScrubMetadata(func(key, value string) bool {
+		return value == "jane" || key == "address"
+	})
+	if removed != 3 {
+		t.Errorf("ScrubMetadata() removed %d entries, expected 3", removed)
+	}
+
+	history := fsm.Transitions()
+	if len(history) != 2 || history[1].ToState != "shipped" {
+		t.Fatalf("ScrubMetadata() changed the history structure: %v", history)
+	}
+
+	if !reflect.DeepEqual(history[0].Metadata, map[string]string{"order": "42"}) || len(history[1].Metadata) != 0 {
+		t.Errorf("Scrubbed metadata is %v and %v", history[0].Metadata, history[1].Metadata)
+	}
+
+	if paid["customer"] != "jane" {
+		t.Errorf("ScrubMetadata() modified the caller's metadata map")
+	}
+}
+
+func Test_scrubMetadataOutbox(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "paid")
+	fsm.SetOutbox(true)
+	fsm.Transition("paid", map[string]string{"customer": "jane", "order": "42"})
+
+	if removed := fsm.ScrubMetadata(func(key, value string) bool { return key == "customer" }); removed != 2 {
+		t.Errorf("ScrubMetadata() removed %d entries, expected the history and outbox entries", removed)
+	}
+
+	if pending := fsm.PendingEvents(); len(pending) != 1 || !reflect.DeepEqual(pending[0].Metadata, map[string]string{"order": "42"}) {
+		t.Errorf("Pending events after scrubbing are %v", pending)
+	}
+
+	data, _ := json.Marshal(fsm)
+	if strings.Contains(string(data), "jane") {
+		t.Errorf("MarshalJSON() persisted scrubbed metadata: %s", data)
+	}
+}
+
+func Test_redactionCoversAmendments(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "paid")
+	fsm.Transition("paid", nil)
+	fsm.AnnotateLastTransition("email", "customer@example.com")
+	fsm.SetRedactionRules(RedactionRule{Pattern: "email", Action: RedactMask})
+
+	data, _ := json.Marshal(fsm)
+	if strings.Contains(string(data), "customer@example.com") {
+		t.Errorf("MarshalJSON() leaked amended metadata: %s", data)
+	}
+
+	if removed := fsm.ScrubMetadata(func(key, value string) bool { return key == "email" }); removed != 2 {
+		t.Errorf("ScrubMetadata() removed %d entries, expected the metadata and amendment entries", removed)
+	}
+
+	if tr := fsm.Transitions()[0]; len(tr.Metadata) != 0 || len(tr.Amendments[0].Metadata) != 0 {
+		t.Errorf("ScrubMetadata() left %v and %v", tr.Metadata, tr.Amendments)
+	}
+}
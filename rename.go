@@ -0,0 +1,180 @@
+package statetrooper
+
+import "fmt"
+
+// RenameState renames oldState to newState throughout the FSM: the current state, rules, history,
+// registered states and all per-state and per-transition configuration
+// ErrStateExists is returned and nothing is changed if newState is already in use
+func (fsm *FSM[T]) RenameState(oldState T, newState T) error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.stateInUse(newState) {
+		return fmt.Errorf("%w: %v", ErrStateExists, newState)
+	}
+
+	rename := func(state T) T {
+		if state == oldState {
+			return newState
+		}
+		return state
+	}
+
+	fsm.currentState = rename(fsm.currentState)
+
+	// Pending outbox events are renamed too, so consumers only ever see the new name once it is in use
+	for _, transitions := range [][]Transition[T]{fsm.transitions, fsm.outbox} {
+		for i := range transitions {
+			transitions[i].FromState = rename(transitions[i].FromState)
+			transitions[i].ToState = rename(transitions[i].ToState)
+		}
+	}
+
+	fsm.ownRules()
+	fsm.ruleset = renameKeys(fsm.ruleset, rename)
+	for state, targets := range fsm.ruleset {
+		for i := range targets {
+			fsm.ruleset[state][i] = rename(targets[i])
+		}
+	}
+
+	fsm.states = renameKeys(fsm.states, rename)
+	fsm.guards = renameEdges(fsm.guards, rename)
+	fsm.edgeCooldowns = renameEdges(fsm.edgeCooldowns, rename)
+	fsm.edgeLastAt = renameEdges(fsm.edgeLastAt, rename)
+	fsm.edgeMaxTransitions = renameEdges(fsm.edgeMaxTransitions, rename)
+	fsm.edgeCounts = renameEdges(fsm.edgeCounts, rename)
+	fsm.allowedRoles = renameEdges(fsm.allowedRoles, rename)
+	fsm.entryActions = renameKeys(fsm.entryActions, rename)
+	fsm.exitActions = renameKeys(fsm.exitActions, rename)
+	fsm.terminals = renameKeys(fsm.terminals, rename)
+	fsm.ruleFlags = renameEdges(fsm.ruleFlags, rename)
+	fsm.minDwells = renameKeys(fsm.minDwells, rename)
+	fsm.dwellThresholds = renameKeys(fsm.dwellThresholds, rename)
+	fsm.idleThresholds = renameKeys(fsm.idleThresholds, rename)
+	fsm.stateSameStatePolicies = renameKeys(fsm.stateSameStatePolicies, rename)
+	fsm.groups = renameValues(fsm.groups, rename)
+	fsm.groupRules = renameValues(fsm.groupRules, rename)
+
+	if fsm.autoTransitions != nil {
+		autoTransitions := make(map[T][]autoTransition[T], len(fsm.autoTransitions))
+		for from, autos := range fsm.autoTransitions {
+			renamed := make([]autoTransition[T], len(autos))
+			for i, auto := range autos {
+				renamed[i] = autoTransition[T]{toState: rename(auto.toState), condition: auto.condition}
+			}
+			autoTransitions[rename(from)] = renamed
+		}
+		fsm.autoTransitions = autoTransitions
+	}
+
+	if fsm.summary != nil {
+		fsm.summary.edges = renameEdges(fsm.summary.edges, rename)
+	}
+
+	if fsm.events != nil {
+		events := make(map[eventRule[T]]T, len(fsm.events))
+		for key, target := range fsm.events {
+			events[eventRule[T]{event: key.event, from: rename(key.from)}] = rename(target)
+		}
+		fsm.events = events
+	}
+
+	fsm.composites = renameKeys(fsm.composites, rename)
+	for parent, c := range fsm.composites {
+		c.initial = rename(c.initial)
+		fsm.composites[parent] = c
+	}
+
+	fsm.parents = renameKeys(fsm.parents, rename)
+	fsm.lastActive = renameKeys(fsm.lastActive, rename)
+	for _, m := range []map[T]T{fsm.parents, fsm.lastActive} {
+		for k, v := range m {
+			m[k] = rename(v)
+		}
+	}
+
+	return nil
+}
+
+// SetStateAliases sets a mapping from old state names to current ones that is applied to the current state
+// and history when the FSM is unmarshaled, so snapshots persisted before a RenameState can still be loaded
+func (fsm *FSM[T]) SetStateAliases(aliases map[T]T) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.aliases = aliases
+}
+
+// resolveAlias returns the state that state is an alias of, or state itself
+func (fsm *FSM[T]) resolveAlias(state T) T {
+	if alias, ok := fsm.aliases[state]; ok {
+		return alias
+	}
+
+	return state
+}
+
+// stateInUse reports whether state is the current state, registered, or referenced by a rule
+func (fsm *FSM[T]) stateInUse(state T) bool {
+	if state == fsm.currentState {
+		return true
+	}
+
+	if _, ok := fsm.states[state]; ok {
+		return true
+	}
+
+	for from, targets := range fsm.ruleset {
+		if from == state || contains(targets, state) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renameKeys returns m with its keys passed through rename, or nil if m is nil
+func renameKeys[T comparable, V any](m map[T]V, rename func(T) T) map[T]V {
+	if m == nil {
+		return nil
+	}
+
+	renamed := make(map[T]V, len(m))
+	for k, v := range m {
+		renamed[rename(k)] = v
+	}
+
+	return renamed
+}
+
+// renameValues returns m with the states in its values passed through rename, or nil if m is nil
+func renameValues[K comparable, T comparable](m map[K][]T, rename func(T) T) map[K][]T {
+	if m == nil {
+		return nil
+	}
+
+	renamed := make(map[K][]T, len(m))
+	for k, states := range m {
+		renamed[k] = make([]T, len(states))
+		for i, state := range states {
+			renamed[k][i] = rename(state)
+		}
+	}
+
+	return renamed
+}
+
+// renameEdges returns m with the states of its edges passed through rename, or nil if m is nil
+func renameEdges[T comparable, V any](m map[edge[T]]V, rename func(T) T) map[edge[T]]V {
+	if m == nil {
+		return nil
+	}
+
+	renamed := make(map[edge[T]]V, len(m))
+	for e, v := range m {
+		renamed[edge[T]{from: rename(e.from), to: rename(e.to)}] = v
+	}
+
+	return renamed
+}
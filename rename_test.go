@@ -0,0 +1,151 @@
+package statetrooper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_renameState(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "packed")
+	fsm.AddRule("packed", "shipped")
+	fsm.SetEdgeCooldown("packed", "shipped", time.Hour)
+	fsm.Transition("packed", nil)
+
+	if err := fsm.RenameState("packed", "shipped"); !errors.Is(err, ErrStateExists) {
+		t.Errorf("RenameState() returned %v, expected ErrStateExists", err)
+	}
+
+	if err := fsm.RenameState("packed", "staged"); err != nil {
+		t.Fatalf("RenameState() returned an error: %v", err)
+	}
+
+	if fsm.CurrentState() != "staged" {
+		t.Errorf("Current state is %v, expected staged", fsm.CurrentState())
+	}
+
+	expected := map[string][]string{"created": {"staged"}, "staged": {"shipped"}}
+	if !reflect.DeepEqual(fsm.Rules(), expected) {
+		t.Errorf("Rules are %v, expected %v", fsm.Rules(), expected)
+	}
+
+	if tr := fsm.Transitions()[0]; tr.ToState != "staged" {
+		t.Errorf("History records a transition to %v, expected staged", tr.ToState)
+	}
+
+	if _, ok := fsm.edgeCooldowns[edge[string]{from: "staged", to: "shipped"}]; !ok {
+		t.Errorf("Edge cooldown was not renamed")
+	}
+}
+
+func Test_stateAliases(t *testing.T) {
+	old := NewFSM[string]("created", 10)
+	old.AddRule("created", "packed")
+	old.Transition("packed", nil)
+
+	data, err := json.Marshal(old)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	fsm := NewFSM[string]("created", 10)
+	fsm.SetStateAliases(map[string]string{"packed": "staged"})
+	if err := json.Unmarshal(data, fsm); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	if fsm.CurrentState() != "staged" || fsm.Transitions()[0].ToState != "staged" {
+		t.Errorf("Unmarshaled state %v and history %v, expected the packed alias to resolve to staged",
+			fsm.CurrentState(), fsm.Transitions())
+	}
+}
+
+func Test_renameStateKeepsRoles(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "packed")
+	fsm.AddRule("packed", "shipped")
+	fsm.SetAllowedRoles("created", "packed", "warehouse")
+	fsm.SetAllowedRoles("packed", "shipped", "warehouse")
+
+	if err := fsm.RenameState("packed", "staged"); err != nil {
+		t.Fatalf("RenameState() returned an error: %v", err)
+	}
+
+	if _, err := fsm.Transition("staged", nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Transition() to the renamed state returned %v without an actor, expected ErrUnauthorized", err)
+	}
+
+	picker := WithActor(context.Background(), Actor{ID: "alice", Roles: []string{"warehouse"}})
+	if _, err := fsm.TransitionCtx(picker, "staged", nil); err != nil {
+		t.Fatalf("TransitionCtx() returned an error for an allowed role: %v", err)
+	}
+
+	if _, err := fsm.Transition("shipped", nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Transition() from the renamed state returned %v without an actor, expected ErrUnauthorized", err)
+	}
+}
+
+func Test_renameStateConfiguration(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "packed")
+	fsm.AddRule("packed", "shipped")
+	fsm.AddRule("shipped", "packed")
+	fsm.SetRuleEnabled("created", "packed", false)
+	fsm.SetMinDwell("packed", time.Hour)
+	fsm.SetDwellThreshold("packed", time.Hour)
+	fsm.SetIdleThreshold("packed", time.Hour)
+	fsm.SetStateSameStatePolicy("packed", SameStateTouch)
+	fsm.SetAutoTransition("shipped", "packed", func(tr Transition[string]) bool { return false })
+	fsm.AddStateGroup("warehouse", "packed", "shipped")
+	fsm.AddGroupRule("warehouse", "created")
+	fsm.AddStateGroup("outbound", "shipped")
+	fsm.AddGroupRule("outbound", "packed")
+
+	if err := fsm.RenameState("packed", "staged"); err != nil {
+		t.Fatalf("RenameState() returned an error: %v", err)
+	}
+
+	if fsm.CanTransition("staged") {
+		t.Errorf("CanTransition() returned true, expected the disabled rule to stay disabled")
+	}
+
+	for name, ok := range map[string]bool{
+		"minimum dwell":        fsm.minDwells["staged"] == time.Hour,
+		"dwell threshold":      fsm.dwellThresholds["staged"] == time.Hour,
+		"idle threshold":       fsm.idleThresholds["staged"] == time.Hour,
+		"same-state policy":    fsm.stateSameStatePolicies["staged"] == SameStateTouch,
+		"automatic transition": fsm.autoTransitions["shipped"][0].toState == "staged",
+		"group":                reflect.DeepEqual(fsm.Group("warehouse"), []string{"staged", "shipped"}),
+		"group rule":           reflect.DeepEqual(fsm.groupRules["outbound"], []string{"staged"}),
+	} {
+		if !ok {
+			t.Errorf("RenameState() did not rename the %s", name)
+		}
+	}
+
+	if err := fsm.Seal(); err != nil {
+		t.Fatalf("Seal() returned an error: %v", err)
+	}
+	if rules := fsm.Rules(); !contains(rules["staged"], "created") || !contains(rules["shipped"], "staged") {
+		t.Errorf("Rules are %v after sealing, expected the group rules to use the renamed state", rules)
+	}
+}
+
+func Test_renameStateOutbox(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "packed")
+	fsm.SetOutbox(true)
+	fsm.Transition("packed", nil)
+
+	if err := fsm.RenameState("packed", "staged"); err != nil {
+		t.Fatalf("RenameState() returned an error: %v", err)
+	}
+
+	if pending := fsm.PendingEvents(); len(pending) != 1 || pending[0].ToState != "staged" {
+		t.Errorf("Pending events after renaming are %v, expected a transition to staged", pending)
+	}
+}
@@ -0,0 +1,60 @@
+package statetrooper
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReplayTo returns the state the FSM was in after the index-th transition of its retained history
+// Index 0 is the state before the oldest retained transition
+// ErrHistoryUnavailable is returned if index is outside the retained history
+func (fsm *FSM[T]) ReplayTo(index int) (T, error) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if index < 0 || index > len(fsm.transitions) {
+		var zero T
+		return zero, fmt.Errorf("%w: index %d, %d transitions retained", ErrHistoryUnavailable, index, len(fsm.transitions))
+	}
+
+	return fsm.stateAfter(index), nil
+}
+
+// StateAt returns the state the FSM was in at the given time, reconstructed from its history
+// ErrHistoryUnavailable is returned if t precedes the oldest retained transition and older
+// transitions may have been removed, whether by the history limit or by the history TTL
+func (fsm *FSM[T]) StateAt(t time.Time) (T, error) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	// Count the transitions that had happened by t
+	n := 0
+	for n < len(fsm.transitions) && !fsm.transitions[n].Timestamp.After(t) {
+		n++
+	}
+
+	// A restored history may have been trimmed before it was persisted, which a full history suggests.
+	// Once every entry has been removed, only times since the last transition are known
+	trimmed := fsm.evicted || len(fsm.transitions) > 0 && len(fsm.transitions) >= fsm.maxHistory
+	if n == 0 && trimmed && (len(fsm.transitions) > 0 || t.Before(fsm.lastTransitionAt)) {
+		var zero T
+		return zero, fmt.Errorf("%w: %v precedes the oldest retained transition", ErrHistoryUnavailable, t)
+	}
+
+	return fsm.stateAfter(n), nil
+}
+
+// stateAfter returns the state after the first n retained transitions
+func (fsm *FSM[T]) stateAfter(n int) T {
+	switch {
+	case len(fsm.transitions) == 0:
+		return fsm.currentState
+	case n == 0:
+		return fsm.transitions[0].FromState
+	case fsm.transitions[n-1].Cycles > 0 || fsm.transitions[n-1].Failed:
+		// A compacted loop ends where it started and a failed attempt leaves the state unchanged
+		return fsm.transitions[n-1].FromState
+	default:
+		return fsm.transitions[n-1].ToState
+	}
+}
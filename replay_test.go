@@ -0,0 +1,100 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_replay(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	fsm.SetClock(func() time.Time { return now })
+
+	now = start.Add(time.Hour)
+	fsm.Transition(CustomStateEnumB, nil)
+	now = start.Add(3 * time.Hour)
+	fsm.Transition(CustomStateEnumC, nil)
+
+	for index, expected := range []CustomStateEnum{CustomStateEnumA, CustomStateEnumB, CustomStateEnumC} {
+		state, err := fsm.ReplayTo(index)
+		if err != nil || state != expected {
+			t.Errorf("ReplayTo(%d) returned %v, %v, expected %v", index, state, err, expected)
+		}
+	}
+
+	if _, err := fsm.ReplayTo(3); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Errorf("ReplayTo(3) returned %v, expected ErrHistoryUnavailable", err)
+	}
+
+	tests := []struct {
+		at       time.Time
+		expected CustomStateEnum
+	}{
+		{start, CustomStateEnumA},
+		{start.Add(time.Hour), CustomStateEnumB},
+		{start.Add(2 * time.Hour), CustomStateEnumB},
+		{start.Add(4 * time.Hour), CustomStateEnumC},
+	}
+
+	for _, tt := range tests {
+		if state, err := fsm.StateAt(tt.at); err != nil || state != tt.expected {
+			t.Errorf("StateAt(%v) returned %v, %v, expected %v", tt.at, state, err, tt.expected)
+		}
+	}
+}
+
+func Test_stateAtEvictedHistory(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 1)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	fsm.SetClock(func() time.Time { return now })
+
+	now = start.Add(time.Hour)
+	fsm.Transition(CustomStateEnumB, nil)
+	now = start.Add(2 * time.Hour)
+	fsm.Transition(CustomStateEnumA, nil)
+
+	if _, err := fsm.StateAt(start.Add(time.Hour)); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Errorf("StateAt() returned %v for an evicted period, expected ErrHistoryUnavailable", err)
+	}
+
+	if state, err := fsm.StateAt(start.Add(3 * time.Hour)); err != nil || state != CustomStateEnumA {
+		t.Errorf("StateAt() returned %v, %v, expected %v", state, err, CustomStateEnumA)
+	}
+}
+
+func Test_stateAtExpiredHistory(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	fsm.SetClock(func() time.Time { return now })
+	fsm.SetHistoryTTL(30 * time.Minute)
+
+	now = start.Add(time.Hour)
+	fsm.Transition(CustomStateEnumB, nil)
+	now = start.Add(2 * time.Hour)
+	fsm.Transition(CustomStateEnumA, nil)
+
+	if _, err := fsm.StateAt(start.Add(90 * time.Minute)); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Errorf("StateAt() returned %v for a period removed by the TTL, expected ErrHistoryUnavailable", err)
+	}
+
+	now = start.Add(5 * time.Hour)
+	fsm.PruneHistory()
+
+	if _, err := fsm.StateAt(start.Add(90 * time.Minute)); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Errorf("StateAt() returned %v once the whole history expired, expected ErrHistoryUnavailable", err)
+	}
+	if state, err := fsm.StateAt(start.Add(3 * time.Hour)); err != nil || state != CustomStateEnumA {
+		t.Errorf("StateAt() returned %v, %v after the last transition, expected %v", state, err, CustomStateEnumA)
+	}
+}
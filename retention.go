@@ -0,0 +1,80 @@
+package statetrooper
+
+import "time"
+
+// HistoryEvictHandler receives transitions removed from the history because of the history limit or TTL,
+// for example to archive them
+// It is called while the FSM is locked and must not call back into the FSM
+type HistoryEvictHandler[T comparable] func(evicted []Transition[T])
+
+// SetHistoryTTL sets how long transitions are kept in the history, in addition to the maxHistory limit
+// Expired transitions are evicted as new transitions are recorded or when PruneHistory is called
+// A zero TTL keeps transitions until the history limit is reached
+func (fsm *FSM[T]) SetHistoryTTL(ttl time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.historyTTL = ttl
+}
+
+// SetHistoryEvictHandler sets the handler called with transitions evicted from the history
+func (fsm *FSM[T]) SetHistoryEvictHandler(handler HistoryEvictHandler[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.evictHandler = handler
+}
+
+// PruneHistory evicts the transitions older than the history TTL
+func (fsm *FSM[T]) PruneHistory() {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.pruneHistory()
+}
+
+// pruneHistory evicts expired transitions. The caller must hold the lock
+func (fsm *FSM[T]) pruneHistory() {
+	if fsm.historyTTL <= 0 {
+		return
+	}
+
+	cutoff := fsm.timeNow().Add(-fsm.historyTTL)
+
+	n := 0
+	for n < len(fsm.transitions) && expired(&fsm.transitions[n], cutoff) {
+		n++
+	}
+
+	fsm.evict(n)
+}
+
+// expired reports whether a transition, or the end of a compacted loop, happened before cutoff
+func expired[T comparable](tr *Transition[T], cutoff time.Time) bool {
+	last := tr.Timestamp
+	if tr.Until != nil {
+		last = tr.Until
+	}
+
+	return last != nil && last.Before(cutoff)
+}
+
+// evict removes the n oldest transitions, summarizes them if enabled and passes them to the evict handler
+// The caller must hold the lock
+func (fsm *FSM[T]) evict(n int) {
+	if n <= 0 {
+		return
+	}
+
+	evicted := fsm.transitions[:n:n]
+	fsm.transitions = fsm.transitions[n:]
+	fsm.evicted = true
+
+	if fsm.summary != nil {
+		fsm.summary.add(evicted)
+	}
+
+	if fsm.evictHandler != nil {
+		fsm.evictHandler(evicted)
+	}
+}
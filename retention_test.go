@@ -0,0 +1,52 @@
+package statetrooper
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_historyRetention(t *testing.T) {
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	fsm := newPingPongFSM()
+	fsm.SetClock(func() time.Time { return now })
+	fsm.SetHistoryTTL(24 * time.Hour)
+
+	var evicted []Transition[CustomStateEnum]
+	fsm.SetHistoryEvictHandler(func(trs []Transition[CustomStateEnum]) {
+		evicted = append(evicted, trs...)
+	})
+
+	for day := 0; day < 3; day++ {
+		now = start.Add(time.Duration(day) * 12 * time.Hour)
+		pingPong(fsm, 1)
+	}
+
+	// Day 0 at 12:00, 00:00 and 12:00 the next day; nothing is older than 24h yet
+	if len(fsm.Transitions()) != 3 || len(evicted) != 0 {
+		t.Errorf("History has %d transitions and %d evicted, expected 3 and 0", len(fsm.Transitions()), len(evicted))
+	}
+
+	now = start.Add(30 * time.Hour)
+	fsm.PruneHistory()
+
+	if len(fsm.Transitions()) != 2 || len(evicted) != 1 || !evicted[0].Timestamp.Equal(start) {
+		t.Errorf("History has %d transitions and evicted %v after pruning", len(fsm.Transitions()), evicted)
+	}
+
+	// The count limit also goes through the handler
+	limited := NewFSM[CustomStateEnum](CustomStateEnumA, 2)
+	limited.AddRule(CustomStateEnumA, CustomStateEnumB)
+	limited.AddRule(CustomStateEnumB, CustomStateEnumA)
+
+	count := 0
+	limited.SetHistoryEvictHandler(func(trs []Transition[CustomStateEnum]) {
+		count += len(trs)
+	})
+
+	pingPong(limited, 5)
+	if count != 3 {
+		t.Errorf("Evict handler received %d transitions, expected 3", count)
+	}
+}
@@ -0,0 +1,202 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures how a guard-rejected transition is retried
+type RetryPolicy struct {
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Zero means no cap
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each retry. Values below 1 keep the delay constant
+	Multiplier float64
+	// Timeout is how long to keep retrying before giving up, measured on the FSM's clock. Zero means retry until cancelled
+	Timeout time.Duration
+}
+
+// Retry tracks a transition that is retried while its guards reject it
+type Retry[T comparable] struct {
+	fsm      *FSM[T]
+	target   T
+	metadata map[string]string
+	policy   RetryPolicy
+	deadline time.Time
+
+	mu          sync.Mutex
+	attempts    int
+	backoff     time.Duration
+	nextAttempt time.Time
+	lastErr     error
+	state       T
+	err         error
+	timer       *time.Timer
+	finished    bool
+	done        chan struct{}
+}
+
+// RetryInfo describes a pending retry
+type RetryInfo[T comparable] struct {
+	ToState     T
+	Attempts    int
+	NextAttempt time.Time
+	LastError   error
+}
+
+// RetryTransition attempts to transition to targetState and, while the transition is rejected by a guard,
+// keeps retrying it in the background according to policy
+// Any other error ends the retry immediately. The first attempt is made before RetryTransition returns
+// Retries that fail or expire are sent to the dead-letter sink, if one is configured
+func (fsm *FSM[T]) RetryTransition(targetState T, metadata map[string]string, policy RetryPolicy) *Retry[T] {
+	r := &Retry[T]{
+		fsm:      fsm,
+		target:   targetState,
+		metadata: metadata,
+		policy:   policy,
+		backoff:  policy.InitialBackoff,
+		done:     make(chan struct{}),
+	}
+
+	fsm.mu.Lock()
+	if policy.Timeout > 0 {
+		r.deadline = fsm.timeNow().Add(policy.Timeout)
+	}
+	if fsm.retries == nil {
+		fsm.retries = make(map[*Retry[T]]struct{})
+	}
+	fsm.retries[r] = struct{}{}
+	fsm.mu.Unlock()
+
+	r.attempt()
+
+	return r
+}
+
+// PendingRetries returns information about all retries that have not finished yet
+func (fsm *FSM[T]) PendingRetries() []RetryInfo[T] {
+	fsm.mu.Lock()
+	retries := make([]*Retry[T], 0, len(fsm.retries))
+	for r := range fsm.retries {
+		retries = append(retries, r)
+	}
+	fsm.mu.Unlock()
+
+	infos := make([]RetryInfo[T], 0, len(retries))
+	for _, r := range retries {
+		r.mu.Lock()
+		if !r.finished {
+			infos = append(infos, RetryInfo[T]{
+				ToState:     r.target,
+				Attempts:    r.attempts,
+				NextAttempt: r.nextAttempt,
+				LastError:   r.lastErr,
+			})
+		}
+		r.mu.Unlock()
+	}
+
+	return infos
+}
+
+// Done returns a channel that is closed when the retry has finished
+func (r *Retry[T]) Done() <-chan struct{} {
+	return r.done
+}
+
+// Result returns the state and error of the finished retry
+// It must only be called after Done is closed
+func (r *Retry[T]) Result() (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.state, r.err
+}
+
+// Attempts returns the number of attempts made so far
+func (r *Retry[T]) Attempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.attempts
+}
+
+// Cancel stops any further attempts. It has no effect if the retry has already finished
+// An attempt that is already in progress may still complete the transition
+func (r *Retry[T]) Cancel() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.finished {
+		return
+	}
+
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+
+	r.finish(r.fsm.CurrentState(), fmt.Errorf("%w: %v", ErrRetryCancelled, r.lastErr))
+}
+
+// attempt performs a single transition attempt and schedules the next one if a guard rejected it
+func (r *Retry[T]) attempt() {
+	state, committed, err := r.fsm.apply(context.Background(), r.target, r.metadata)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.finished {
+		return
+	}
+
+	r.attempts++
+
+	// Errors of a committed transition come from its actions, and retrying would attempt it a second time
+	var gErr GuardError[T]
+	if err == nil || committed || !errors.As(err, &gErr) {
+		r.finish(state, err)
+		if err != nil && !committed {
+			r.fsm.deadLetter(state, r.target, r.metadata, err, r.attempts)
+		}
+		return
+	}
+
+	r.lastErr = err
+
+	now := r.fsm.lockedNow()
+	delay := r.backoff
+
+	if !r.deadline.IsZero() && now.Add(delay).After(r.deadline) {
+		r.finish(state, fmt.Errorf("%w: %w", ErrRetryExpired, err))
+		r.fsm.deadLetter(state, r.target, r.metadata, r.err, r.attempts)
+		return
+	}
+
+	if r.policy.Multiplier > 1 {
+		r.backoff = time.Duration(float64(r.backoff) * r.policy.Multiplier)
+	}
+
+	if r.policy.MaxBackoff > 0 && r.backoff > r.policy.MaxBackoff {
+		r.backoff = r.policy.MaxBackoff
+	}
+
+	r.nextAttempt = now.Add(delay)
+	r.timer = time.AfterFunc(delay, r.attempt)
+}
+
+// finish records the outcome of the retry and removes it from the FSM's pending retries
+// r.mu must be held
+func (r *Retry[T]) finish(state T, err error) {
+	r.finished = true
+	r.state = state
+	r.err = err
+	close(r.done)
+
+	r.fsm.mu.Lock()
+	delete(r.fsm.retries, r)
+	r.fsm.mu.Unlock()
+}
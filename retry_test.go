@@ -0,0 +1,105 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_retryTransition(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	var calls int32
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+
+	r := fsm.RetryTransition(CustomStateEnumB, nil, RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		Multiplier:     2,
+		Timeout:        time.Second,
+	})
+
+	if pending := fsm.PendingRetries(); len(pending) != 1 || pending[0].Attempts != 1 || pending[0].LastError == nil {
+		t.Errorf("PendingRetries() = %v, expected one retry after one attempt", pending)
+	}
+
+	select {
+	case <-r.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Retry did not finish")
+	}
+
+	state, err := r.Result()
+	if err != nil || state != CustomStateEnumB {
+		t.Errorf("Retry result = (%v, %v), expected (%v, nil)", state, err, CustomStateEnumB)
+	}
+
+	if r.Attempts() != 3 {
+		t.Errorf("Attempts() = %d, expected 3", r.Attempts())
+	}
+
+	if len(fsm.PendingRetries()) != 0 {
+		t.Errorf("Finished retry is still pending")
+	}
+}
+
+func Test_retryTransitionCancelAndExpire(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errors.New("never ready")
+	})
+
+	r := fsm.RetryTransition(CustomStateEnumB, nil, RetryPolicy{InitialBackoff: time.Hour})
+	r.Cancel()
+
+	<-r.Done()
+	if _, err := r.Result(); !errors.Is(err, ErrRetryCancelled) {
+		t.Errorf("Cancelled retry returned %v, expected ErrRetryCancelled", err)
+	}
+
+	r = fsm.RetryTransition(CustomStateEnumB, nil, RetryPolicy{InitialBackoff: time.Millisecond, Timeout: 5 * time.Millisecond})
+
+	select {
+	case <-r.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Retry did not expire")
+	}
+
+	var gErr GuardError[CustomStateEnum]
+	if _, err := r.Result(); !errors.Is(err, ErrRetryExpired) || !errors.As(err, &gErr) {
+		t.Errorf("Expired retry returned %v, expected ErrRetryExpired wrapping a GuardError", err)
+	}
+
+	// Errors other than guard rejections are not retried
+	r = fsm.RetryTransition(CustomStateEnumC, nil, RetryPolicy{InitialBackoff: time.Millisecond})
+
+	<-r.Done()
+	if _, err := r.Result(); err == nil || r.Attempts() != 1 {
+		t.Errorf("Invalid transition was retried %d times with result %v", r.Attempts(), err)
+	}
+}
+
+func Test_retryDeadlineUsesClock(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	fsm := newPingPongFSM()
+	fsm.SetClock(func() time.Time { return now })
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumB, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return errors.New("not ready")
+	})
+
+	r := fsm.RetryTransition(CustomStateEnumB, nil, RetryPolicy{InitialBackoff: time.Hour, Timeout: 2 * time.Hour})
+	defer r.Cancel()
+
+	if info := fsm.PendingRetries(); len(info) != 1 || !info[0].NextAttempt.Equal(now.Add(time.Hour)) {
+		t.Errorf("PendingRetries() returned %+v, expected the next attempt on the FSM's clock", info)
+	}
+}
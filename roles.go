@@ -0,0 +1,108 @@
+package statetrooper
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetAllowedRoles restricts the rule from fromState to toState to actors holding at least one of roles,
+// so authorization for a workflow step lives with the workflow definition
+// The actor is taken from the context passed to TransitionCtx, see WithActor; attempts without an actor
+// are rejected. Calling it without roles lifts the restriction
+func (fsm *FSM[T]) SetAllowedRoles(fromState T, toState T, roles ...string) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	e := edge[T]{from: fromState, to: toState}
+
+	if len(roles) == 0 {
+		delete(fsm.allowedRoles, e)
+		return
+	}
+
+	if fsm.allowedRoles == nil {
+		fsm.allowedRoles = make(map[edge[T]][]string)
+	}
+
+	fsm.allowedRoles[e] = append([]string(nil), roles...)
+}
+
+// checkRoles returns an AuthorizationError if a rule the transition from fromState to toState takes is
+// restricted to roles that actor does not hold. Restrictions on rules from or to composite states apply
+// to their substates, see ruleEdges
+func (fsm *FSM[T]) checkRoles(actor *Actor, fromState *T, toState *T) error {
+	for _, e := range fsm.configuredEdges(len(fsm.allowedRoles), *fromState, fsm.resolveTarget(*toState)) {
+		roles, ok := fsm.allowedRoles[e]
+		if !ok || holdsRole(actor, roles) {
+			continue
+		}
+
+		return AuthorizationError[T]{
+			FromState: *fromState,
+			ToState:   *toState,
+			Actor:     actor.ID,
+			Err:       fmt.Errorf("requires one of the roles %v", roles),
+		}
+	}
+
+	return nil
+}
+
+// holdsRole reports whether actor holds at least one of roles
+func holdsRole(actor *Actor, roles []string) bool {
+	for _, role := range actor.Roles {
+		if contains(roles, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authorizer decides whether an actor may perform a transition, for example by validating a token or
+// querying a policy engine, so authorization is enforced centrally rather than by every caller of Transition
+type Authorizer[T comparable] interface {
+	// Authorize returns an error if actor may not transition from fromState to toState
+	// The actor is the zero Actor if none was supplied with WithActor
+	Authorize(ctx context.Context, actor Actor, fromState T, toState T) error
+}
+
+// AuthorizerFunc adapts a function to an Authorizer
+type AuthorizerFunc[T comparable] func(ctx context.Context, actor Actor, fromState T, toState T) error
+
+// Authorize calls fn
+func (fn AuthorizerFunc[T]) Authorize(ctx context.Context, actor Actor, fromState T, toState T) error {
+	return fn(ctx, actor, fromState, toState)
+}
+
+// SetAuthorizer sets the authorizer consulted before each transition, after the allowed roles are checked
+// It is called while the FSM is locked, bounded by the hook timeout, and must not call back into the FSM
+// A nil authorizer allows every transition
+func (fsm *FSM[T]) SetAuthorizer(authorizer Authorizer[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.authorizer = authorizer
+}
+
+// authorize returns an AuthorizationError if the authorizer rejects the transition
+func (fsm *FSM[T]) authorize(ctx context.Context, actor *Actor, fromState *T, toState *T) error {
+	if fsm.authorizer == nil {
+		return nil
+	}
+
+	tr := Transition[T]{FromState: *fromState, ToState: *toState, Actor: actor.ID}
+	err := callWithTimeout(ctx, fsm.hookTimeout, tr, func(ctx context.Context, tr Transition[T]) error {
+		return fsm.authorizer.Authorize(ctx, *actor, tr.FromState, tr.ToState)
+	})
+	if err == nil {
+		return nil
+	}
+
+	return AuthorizationError[T]{
+		FromState: *fromState,
+		ToState:   *toState,
+		Actor:     actor.ID,
+		Err:       err,
+	}
+}
@@ -0,0 +1,98 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_allowedRoles(t *testing.T) {
+	fsm := newPingPongFSM()
+	fsm.SetAllowedRoles(CustomStateEnumA, CustomStateEnumB, "warehouse", "admin")
+
+	// Attempts without an actor or without a matching role are rejected
+	var authErr AuthorizationError[CustomStateEnum]
+	if _, err := fsm.Transition(CustomStateEnumB, nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Transition() returned %v without an actor, expected ErrUnauthorized", err)
+	}
+
+	clerk := WithActor(context.Background(), Actor{ID: "bob", Roles: []string{"sales"}})
+	if _, err := fsm.TransitionCtx(clerk, CustomStateEnumB, nil); !errors.As(err, &authErr) || authErr.Actor != "bob" {
+		t.Errorf("TransitionCtx() returned %v, expected an AuthorizationError for bob", err)
+	}
+
+	picker := WithActor(context.Background(), Actor{ID: "alice", Roles: []string{"sales", "warehouse"}})
+	if _, err := fsm.TransitionCtx(picker, CustomStateEnumB, nil); err != nil {
+		t.Errorf("TransitionCtx() returned an error for an allowed role: %v", err)
+	}
+
+	// Unrestricted rules are open to everyone
+	if _, err := fsm.Transition(CustomStateEnumA, nil); err != nil {
+		t.Errorf("Transition() returned an error for an unrestricted rule: %v", err)
+	}
+
+	fsm.SetAllowedRoles(CustomStateEnumA, CustomStateEnumB)
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Errorf("Transition() returned an error after lifting the restriction: %v", err)
+	}
+
+	fsm.SetAllowedRoles(CustomStateEnumA, CustomStateEnumC, "admin")
+	if err := fsm.Validate(); err == nil || !strings.Contains(err.Error(), "allowed roles on A -> C") {
+		t.Errorf("Validate() returned %v, expected it to report roles on a missing rule", err)
+	}
+}
+
+func Test_allowedRolesComposite(t *testing.T) {
+	fsm := NewFSM[string]("picking", 10)
+	fsm.AddCompositeState("fulfillment", "picking", NoHistory, "picking", "packing")
+	fsm.AddRule("fulfillment", "canceled")
+	fsm.SetAllowedRoles("fulfillment", "canceled", "admin")
+
+	// A restriction on a rule from a composite state applies to its substates
+	guest := WithActor(context.Background(), Actor{ID: "guest", Roles: []string{"guest"}})
+	if _, err := fsm.TransitionCtx(guest, "canceled", nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("TransitionCtx() from a substate returned %v, expected ErrUnauthorized", err)
+	}
+
+	admin := WithActor(context.Background(), Actor{ID: "root", Roles: []string{"admin"}})
+	if _, err := fsm.TransitionCtx(admin, "canceled", nil); err != nil {
+		t.Errorf("TransitionCtx() returned an error for an allowed role: %v", err)
+	}
+}
+
+func Test_authorizer(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	denied := errors.New("token expired")
+	var calls []string
+	fsm.SetAuthorizer(AuthorizerFunc[CustomStateEnum](func(ctx context.Context, actor Actor, fromState CustomStateEnum, toState CustomStateEnum) error {
+		calls = append(calls, fmt.Sprintf("%s:%v->%v", actor.ID, fromState, toState))
+		if actor.Claims["exp"] == "past" {
+			return denied
+		}
+		return nil
+	}))
+
+	expired := WithActor(context.Background(), Actor{ID: "bob", Claims: map[string]any{"exp": "past"}})
+	_, err := fsm.TransitionCtx(expired, CustomStateEnumB, nil)
+	if !errors.Is(err, ErrUnauthorized) || !errors.Is(err, denied) {
+		t.Errorf("TransitionCtx() returned %v, expected ErrUnauthorized wrapping the authorizer's error", err)
+	}
+
+	valid := WithActor(context.Background(), Actor{ID: "alice", Claims: map[string]any{"exp": "future"}})
+	if _, err := fsm.TransitionCtx(valid, CustomStateEnumB, nil); err != nil {
+		t.Errorf("TransitionCtx() returned an error for an authorized actor: %v", err)
+	}
+
+	// The authorizer is not consulted for transitions rejected by the roles
+	fsm.SetAllowedRoles(CustomStateEnumB, CustomStateEnumA, "admin")
+	fsm.TransitionCtx(valid, CustomStateEnumA, nil)
+
+	expected := []string{"bob:A->B", "alice:A->B"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Authorizer was called with %v, expected %v", calls, expected)
+	}
+}
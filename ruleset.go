@@ -0,0 +1,103 @@
+package statetrooper
+
+import "sort"
+
+// RuleSet is a set of rules mapping each from state to its allowed target states, as returned by Rules
+// It can be combined with the set operations below and installed with AddRules
+type RuleSet[T comparable] map[T][]T
+
+// Union returns the rules in r or other. Conflicts lists the rules defined in both,
+// which usually means an overlay repeats an edge the base already has
+// Targets keep the order of r followed by the targets only in other
+func (r RuleSet[T]) Union(other RuleSet[T]) (union RuleSet[T], conflicts [][2]T) {
+	union = r.Clone()
+	if union == nil {
+		union = make(RuleSet[T])
+	}
+
+	for from, targets := range other {
+		for _, to := range targets {
+			if contains(union[from], to) {
+				conflicts = append(conflicts, [2]T{from, to})
+				continue
+			}
+			union[from] = append(union[from], to)
+		}
+	}
+
+	return union, sortedPairs(conflicts)
+}
+
+// Intersect returns the rules in both r and other
+func (r RuleSet[T]) Intersect(other RuleSet[T]) RuleSet[T] {
+	intersection := make(RuleSet[T])
+
+	for from, targets := range r {
+		for _, to := range targets {
+			if contains(other[from], to) {
+				intersection[from] = append(intersection[from], to)
+			}
+		}
+	}
+
+	return intersection
+}
+
+// Subtract returns the rules in r that are not in other. Conflicts lists the rules of other
+// that are not in r, which usually means the edge to remove was misspelled or already removed
+func (r RuleSet[T]) Subtract(other RuleSet[T]) (difference RuleSet[T], conflicts [][2]T) {
+	difference = make(RuleSet[T])
+
+	for from, targets := range r {
+		for _, to := range targets {
+			if !contains(other[from], to) {
+				difference[from] = append(difference[from], to)
+			}
+		}
+	}
+
+	for from, targets := range other {
+		for _, to := range targets {
+			if !contains(r[from], to) {
+				conflicts = append(conflicts, [2]T{from, to})
+			}
+		}
+	}
+
+	return difference, sortedPairs(conflicts)
+}
+
+// NewFSMWithRuleSet creates an FSM in initialState that uses rules without copying them, so that one
+// ruleset can back any number of FSMs. The rules are installed as is, without the checks of AddRule,
+// and must not be changed afterwards; an FSM that adds or renames rules copies them first
+func NewFSMWithRuleSet[T comparable](rules RuleSet[T], initialState T, maxHistory int) *FSM[T] {
+	fsm := NewFSM(initialState, maxHistory)
+	if rules != nil {
+		fsm.ruleset, fsm.sharedRules = rules, true
+	}
+
+	return fsm
+}
+
+// ownRules copies the ruleset if it is shared, so that it can be changed. The caller must hold the lock
+func (fsm *FSM[T]) ownRules() {
+	if fsm.sharedRules {
+		fsm.ruleset = cloneMapOfSlices(fsm.ruleset)
+		fsm.sharedRules = false
+	}
+}
+
+// Clone returns a copy of r that can be modified independently, or nil if r is nil
+func (r RuleSet[T]) Clone() RuleSet[T] {
+	return cloneMapOfSlices(r)
+}
+
+// sortedPairs orders pairs by the string form of their states, so that results don't depend on map iteration order
+func sortedPairs[T comparable](pairs [][2]T) [][2]T {
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		return toString(a[0])+"\x00"+toString(a[1]) < toString(b[0])+"\x00"+toString(b[1])
+	})
+
+	return pairs
+}
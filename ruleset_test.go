@@ -0,0 +1,107 @@
+package statetrooper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ruleSetOperations(t *testing.T) {
+	base := RuleSet[string]{
+		"created": {"paid", "canceled"},
+		"paid":    {"shipped"},
+	}
+	staging := RuleSet[string]{
+		"created": {"canceled", "manual_override"},
+		"shipped": {"created"},
+	}
+
+	union, conflicts := base.Union(staging)
+	expected := RuleSet[string]{
+		"created": {"paid", "canceled", "manual_override"},
+		"paid":    {"shipped"},
+		"shipped": {"created"},
+	}
+	if !reflect.DeepEqual(union, expected) {
+		t.Errorf("Union() returned %v, expected %v", union, expected)
+	}
+
+	if !reflect.DeepEqual(conflicts, [][2]string{{"created", "canceled"}}) {
+		t.Errorf("Union() reported conflicts %v, expected created -> canceled", conflicts)
+	}
+
+	if len(base["created"]) != 2 {
+		t.Errorf("Union() modified its receiver: %v", base)
+	}
+
+	if intersection := base.Intersect(staging); !reflect.DeepEqual(intersection, RuleSet[string]{"created": {"canceled"}}) {
+		t.Errorf("Intersect() returned %v", intersection)
+	}
+
+	difference, conflicts := union.Subtract(RuleSet[string]{"created": {"manual_override", "refunded"}})
+	expected["created"] = []string{"paid", "canceled"}
+	if !reflect.DeepEqual(difference, expected) {
+		t.Errorf("Subtract() returned %v, expected %v", difference, expected)
+	}
+
+	if !reflect.DeepEqual(conflicts, [][2]string{{"created", "refunded"}}) {
+		t.Errorf("Subtract() reported conflicts %v, expected created -> refunded", conflicts)
+	}
+
+	// The result can be installed directly
+	fsm := NewFSM[string]("created", 10)
+	if err := fsm.AddRules(difference); err != nil {
+		t.Errorf("AddRules() returned an error: %v", err)
+	}
+}
+
+func Test_newFSMWithRuleSet(t *testing.T) {
+	rules := RuleSet[string]{
+		"created": {"paid"},
+		"paid":    {"shipped"},
+	}
+
+	a := NewFSMWithRuleSet(rules, "created", 10)
+	b := NewFSMWithRuleSet(rules, "paid", 10)
+
+	if _, err := a.Transition("paid", nil); err != nil {
+		t.Fatalf("Transition() returned %v, expected the shared rule to apply", err)
+	}
+	if !b.CanTransition("shipped") {
+		t.Errorf("CanTransition() returned false, expected the shared rule to apply")
+	}
+
+	if err := a.AddRule("created", "canceled"); err != nil {
+		t.Fatalf("AddRule() returned %v", err)
+	}
+	if err := b.RenameState("shipped", "delivered"); err != nil {
+		t.Fatalf("RenameState() returned %v", err)
+	}
+
+	expected := RuleSet[string]{
+		"created": {"paid"},
+		"paid":    {"shipped"},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("changing an FSM modified the shared rules: %v", rules)
+	}
+	if !reflect.DeepEqual(RuleSet[string](a.Rules()), RuleSet[string]{"created": {"paid", "canceled"}, "paid": {"shipped"}}) {
+		t.Errorf("Rules() returned %v, expected the added rule", a.Rules())
+	}
+}
+
+func Test_templateSharesRuleSet(t *testing.T) {
+	prototype := newPingPongFSM()
+	tmpl := NewTemplate(prototype)
+
+	a, b := tmpl.New(CustomStateEnumA), tmpl.New(CustomStateEnumA)
+	if reflect.ValueOf(a.ruleset).Pointer() != reflect.ValueOf(b.ruleset).Pointer() {
+		t.Errorf("FSMs of a template have their own copy of the rules, expected them to be shared")
+	}
+
+	a.AddRule(CustomStateEnumB, CustomStateEnumC)
+	prototype.AddRule(CustomStateEnumA, CustomStateEnumD)
+
+	if b.CanTransition(CustomStateEnumD) || len(b.Rules()[CustomStateEnumB]) != 1 {
+		t.Errorf("changes to the prototype or another FSM leaked into the template's rules: %v", b.Rules())
+	}
+}
@@ -0,0 +1,86 @@
+package statetrooper
+
+import "time"
+
+// SameStatePolicy determines what happens when the target of a transition is the current state
+// and there is no self-loop rule for it. A self-loop rule always makes it a regular transition
+type SameStatePolicy int
+
+const (
+	// SameStateError rejects the request with a TransitionError
+	SameStateError SameStatePolicy = iota
+	// SameStateNoOp makes the request succeed without recording anything or running hooks and actions
+	SameStateNoOp
+	// SameStateTouch handles the request like Touch, always recording it in the history,
+	// without running guards, hooks or actions
+	SameStateTouch
+)
+
+// SetSameStatePolicy sets the policy for requests targeting the current state
+// Policies set for individual states with SetStateSameStatePolicy take precedence
+func (fsm *FSM[T]) SetSameStatePolicy(policy SameStatePolicy) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.sameStatePolicy = policy
+}
+
+// SetStateSameStatePolicy sets the policy for requests targeting state while the FSM is in state
+func (fsm *FSM[T]) SetStateSameStatePolicy(state T, policy SameStatePolicy) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.stateSameStatePolicies == nil {
+		fsm.stateSameStatePolicies = make(map[T]SameStatePolicy)
+	}
+
+	fsm.stateSameStatePolicies[state] = policy
+}
+
+// sameState applies the same-state policy to a request for targetState at tn and reports whether
+// it is handled as a successful no-op or touch. For a touch, it also returns the entry to record once
+// the request is committed. The caller must hold the lock
+func (fsm *FSM[T]) sameState(targetState *T, metadata map[string]string, tn time.Time) (*Transition[T], bool) {
+	switch fsm.sameStatePolicyFor(targetState) {
+	case SameStateNoOp:
+		return nil, true
+	case SameStateTouch:
+		return &Transition[T]{
+			FromState: fsm.currentState,
+			ToState:   fsm.currentState,
+			Timestamp: &tn,
+			Metadata:  metadata,
+			Touch:     true,
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// noOp reports whether tr is a touch or duplicate entry prepared for a request that leaves the state unchanged
+func (tr *Transition[T]) noOp() bool {
+	return tr.Touch || tr.Duplicate
+}
+
+// commitNoOp records a touch or duplicate entry prepared by prepare. The caller must hold the lock
+func (fsm *FSM[T]) commitNoOp(tr *Transition[T]) {
+	if tr.Touch {
+		fsm.lastTouch = *tr.Timestamp
+	}
+
+	fsm.recordTransition(tr)
+}
+
+// sameStatePolicyFor returns the policy that applies to a request for targetState
+// Only requests for the current state without a self-loop rule are subject to a policy
+func (fsm *FSM[T]) sameStatePolicyFor(targetState *T) SameStatePolicy {
+	if *targetState != fsm.currentState || fsm.canTransition(&fsm.currentState, targetState) {
+		return SameStateError
+	}
+
+	if policy, ok := fsm.stateSameStatePolicies[*targetState]; ok {
+		return policy
+	}
+
+	return fsm.sameStatePolicy
+}
@@ -0,0 +1,61 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_sameStatePolicy(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	var trErr TransitionError[CustomStateEnum]
+	if _, err := fsm.Transition(CustomStateEnumA, nil); !errors.As(err, &trErr) {
+		t.Errorf("Transition(A) from A returned %v, expected a TransitionError by default", err)
+	}
+
+	fsm.SetSameStatePolicy(SameStateNoOp)
+	if state, err := fsm.Transition(CustomStateEnumA, nil); err != nil || state != CustomStateEnumA {
+		t.Errorf("Transition(A) returned %v, %v under SameStateNoOp", state, err)
+	}
+
+	if len(fsm.Transitions()) != 0 {
+		t.Errorf("SameStateNoOp recorded %v", fsm.Transitions())
+	}
+
+	if ex := fsm.Explain(CustomStateEnumA); !ex.Allowed {
+		t.Errorf("Explain(A) returned %+v under SameStateNoOp", ex)
+	}
+
+	// A per-state policy takes precedence
+	// The FSM was created with the real clock, so the touch must come later to be its last activity
+	start := time.Now().Add(time.Hour)
+	fsm.SetClock(func() time.Time { return start })
+	fsm.SetStateSameStatePolicy(CustomStateEnumA, SameStateTouch)
+	if _, err := fsm.Transition(CustomStateEnumA, map[string]string{"heartbeat": "1"}); err != nil {
+		t.Errorf("Transition(A) returned %v under SameStateTouch", err)
+	}
+
+	history := fsm.Transitions()
+	if len(history) != 1 || !history[0].Touch || history[0].FromState != CustomStateEnumA || history[0].ToState != CustomStateEnumA {
+		t.Fatalf("SameStateTouch recorded %v, expected a single touch entry", history)
+	}
+
+	if last := fsm.LastActivity(); !last.Equal(start) {
+		t.Errorf("LastActivity() returned %v after a touch, expected %v", last, start)
+	}
+
+	fsm.SetStateSameStatePolicy(CustomStateEnumA, SameStateError)
+	if _, err := fsm.Transition(CustomStateEnumA, nil); !errors.As(err, &trErr) {
+		t.Errorf("Transition(A) returned %v, expected the per-state SameStateError policy to apply", err)
+	}
+
+	// A self-loop rule makes it a regular transition regardless of the policy
+	fsm.AllowSelfLoops(true)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumB)
+	fsm.Transition(CustomStateEnumB, nil)
+	fsm.Transition(CustomStateEnumB, nil)
+	if history := fsm.Transitions(); history[len(history)-1].Touch || history[len(history)-1].FromState != CustomStateEnumB {
+		t.Errorf("Self-loop rule was handled by the same-state policy: %v", history)
+	}
+}
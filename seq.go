@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package statetrooper
+
+import "iter"
+
+// RulesSeq returns an iterator over the rules as from, to pairs, so the ruleset can be streamed
+// without copying it. The FSM is locked while iterating, so the loop body must not call back into the FSM
+// Rules are yielded in no particular order
+func (fsm *FSM[T]) RulesSeq() iter.Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		fsm.mu.Lock()
+		defer fsm.mu.Unlock()
+
+		for from, targets := range fsm.ruleset {
+			for _, to := range targets {
+				if !yield(from, to) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package statetrooper
+
+import "testing"
+
+func Test_rulesSeq(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+
+	seen := make(map[[2]CustomStateEnum]bool)
+	for from, to := range fsm.RulesSeq() {
+		seen[[2]CustomStateEnum{from, to}] = true
+	}
+
+	if len(seen) != 3 || !seen[[2]CustomStateEnum{CustomStateEnumB, CustomStateEnumA}] {
+		t.Errorf("RulesSeq() yielded %v, expected all 3 rules", seen)
+	}
+
+	// Breaking out of the loop stops the iteration and releases the lock
+	n := 0
+	for range fsm.RulesSeq() {
+		n++
+		break
+	}
+
+	if n != 1 || !fsm.CanTransition(CustomStateEnumB) {
+		t.Errorf("Breaking out of RulesSeq() yielded %d rules or left the FSM locked", n)
+	}
+}
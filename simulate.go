@@ -0,0 +1,164 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SimulationConfig configures a load simulation run by Simulate
+type SimulationConfig[T comparable] struct {
+	// Entities is the number of simulated entities, each advanced by its own goroutine
+	Entities int
+	// Interval is the mean time an entity waits between transitions. Waits are exponentially distributed,
+	// so entities don't move in lockstep. Zero transitions as fast as possible
+	Interval time.Duration
+	// Steps limits the transitions attempted per entity, zero for no limit
+	Steps int
+	// Weights sets the relative likelihood of taking each edge from the current state
+	// Edges without a weight have a weight of 1 and edges with a weight of zero or less are never taken
+	Weights map[[2]T]float64
+	// Metadata is copied into the metadata of every simulated transition, for example to mark it as synthetic
+	Metadata map[string]string
+	// Seed makes the choice of targets and waits reproducible; entity i uses Seed+i
+	Seed int64
+}
+
+// SimulationReport summarizes a load simulation
+type SimulationReport struct {
+	Entities    int
+	Attempts    int
+	Transitions int
+	Failures    int
+	// Finished counts the entities that reached a state with no enabled outgoing rules
+	Finished int
+	Elapsed  time.Duration
+}
+
+// Throughput returns the committed transitions per second
+func (r SimulationReport) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.Transitions) / r.Elapsed.Seconds()
+}
+
+// Simulate drives cfg.Entities FSMs created by newEntity through their rules, picking each target among the
+// enabled rules of the current state by weight, to put load on the hooks, outbox, subscribers and other
+// integrations attached to them. newEntity typically calls NewFSMWithRuleSet or Template.New, attaches the
+// integrations under test and adds the FSM to a Manager
+// An entity stops once its steps are used up, it has no enabled rules left or it is closed. Simulate returns
+// once every entity has stopped or ctx is done. Transitions run through the regular checks, so failures
+// from guards, cooldowns and hooks are counted rather than skipped
+func Simulate[T comparable](ctx context.Context, newEntity func(i int) *FSM[T], cfg SimulationConfig[T]) SimulationReport {
+	var attempts, transitions, failures, finished atomic.Int64
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Entities; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			fsm := newEntity(i)
+			rng := rand.New(rand.NewSource(cfg.Seed + int64(i)))
+
+			for step := 0; cfg.Steps == 0 || step < cfg.Steps; step++ {
+				if !simulationWait(ctx, rng, cfg.Interval) {
+					return
+				}
+
+				target, ok := fsm.simulationTarget(rng, cfg.Weights)
+				if !ok {
+					finished.Add(1)
+					return
+				}
+
+				_, err := fsm.TransitionCtx(ctx, target, cloneMap(cfg.Metadata))
+				if ctx.Err() != nil {
+					return
+				}
+
+				// A failed action does not undo its transition, so it counts as committed
+				attempts.Add(1)
+				if err != nil && !errors.Is(err, ErrActionFailed) {
+					failures.Add(1)
+				} else {
+					transitions.Add(1)
+				}
+
+				if errors.Is(err, ErrClosed) {
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return SimulationReport{
+		Entities:    cfg.Entities,
+		Attempts:    int(attempts.Load()),
+		Transitions: int(transitions.Load()),
+		Failures:    int(failures.Load()),
+		Finished:    int(finished.Load()),
+		Elapsed:     time.Since(start),
+	}
+}
+
+// simulationTarget picks one of the enabled targets of the current state by weight
+// It returns false if no target has a positive weight
+func (fsm *FSM[T]) simulationTarget(rng *rand.Rand, weights map[[2]T]float64) (T, bool) {
+	fsm.mu.Lock()
+	from := fsm.currentState
+	targets := fsm.allowedTargets(&from)
+	fsm.mu.Unlock()
+
+	cumulative := make([]float64, len(targets))
+	total := 0.0
+	for i, to := range targets {
+		weight, ok := weights[[2]T{from, to}]
+		if !ok {
+			weight = 1
+		}
+		if weight > 0 {
+			total += weight
+		}
+		cumulative[i] = total
+	}
+
+	if total == 0 {
+		var zero T
+		return zero, false
+	}
+
+	pick := rng.Float64() * total
+	for i, c := range cumulative {
+		if pick < c {
+			return targets[i], true
+		}
+	}
+
+	return targets[len(targets)-1], true
+}
+
+// simulationWait waits for an exponentially distributed time with the given mean
+// It returns false if ctx is done first
+func simulationWait(ctx context.Context, rng *rand.Rand, mean time.Duration) bool {
+	if mean <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(time.Duration(rng.ExpFloat64() * float64(mean)))
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		timer.Stop()
+		return false
+	}
+}
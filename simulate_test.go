@@ -0,0 +1,70 @@
+package statetrooper
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_simulate(t *testing.T) {
+	rules := RuleSet[string]{
+		"created": {"paid", "canceled"},
+		"paid":    {"shipped", "created"},
+	}
+
+	m := NewManager[int, string]()
+	var published atomic.Int64
+
+	report := Simulate(context.Background(), func(i int) *FSM[string] {
+		fsm := NewFSMWithRuleSet(rules, "created", 100)
+		fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[string]) error {
+			published.Add(1)
+			return nil
+		})
+		m.Add(i, fsm)
+		return fsm
+	}, SimulationConfig[string]{
+		Entities: 20,
+		Weights:  map[[2]string]float64{{"created", "canceled"}: 0, {"paid", "created"}: 3},
+		Metadata: map[string]string{"source": "simulation"},
+	})
+
+	if report.Entities != 20 || report.Finished != 20 || report.Failures != 0 {
+		t.Fatalf("Simulate reported %+v, expected every entity to finish without failures", report)
+	}
+	if report.Attempts != report.Transitions || int(published.Load()) != report.Transitions {
+		t.Errorf("Simulate reported %d transitions, hooks saw %d", report.Transitions, published.Load())
+	}
+
+	for i := 0; i < 20; i++ {
+		fsm, _ := m.Get(i)
+		if fsm.CurrentState() != "shipped" {
+			t.Errorf("entity %d ended in %s, expected shipped since canceled has no weight", i, fsm.CurrentState())
+		}
+		if md := fsm.Transitions()[0].Metadata; md["source"] != "simulation" {
+			t.Errorf("simulated transition has metadata %v, expected the configured metadata", md)
+		}
+	}
+}
+
+func Test_simulateStops(t *testing.T) {
+	report := Simulate(context.Background(), func(i int) *FSM[CustomStateEnum] {
+		return newPingPongFSM()
+	}, SimulationConfig[CustomStateEnum]{Entities: 3, Steps: 5})
+
+	if report.Transitions != 15 || report.Finished != 0 {
+		t.Errorf("Simulate reported %+v, expected 5 transitions per entity", report)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	report = Simulate(ctx, func(i int) *FSM[CustomStateEnum] {
+		return newPingPongFSM()
+	}, SimulationConfig[CustomStateEnum]{Entities: 3, Interval: time.Hour})
+
+	if report.Attempts != 0 || report.Elapsed > time.Second {
+		t.Errorf("Simulate reported %+v, expected it to stop once the context is done", report)
+	}
+}
@@ -0,0 +1,96 @@
+package statetrooper
+
+import "context"
+
+// NewUnstartedFSM creates an FSM that does not enter initialState until Start is called
+// Transitions are rejected with ErrNotStarted until then
+func NewUnstartedFSM[T comparable](initialState T, maxHistory int) *FSM[T] {
+	fsm := NewFSM[T](initialState, maxHistory)
+	fsm.unstarted = true
+
+	return fsm
+}
+
+// NewFSMFunc creates an unstarted FSM whose initial state is computed by resolve when Start is called,
+// for example from a database row. Until then CurrentState returns the zero value of T
+// The resolved state is recorded in the "resolved_initial_state" metadata of the initial entry
+func NewFSMFunc[T comparable](resolve func(ctx context.Context) (T, error), maxHistory int) *FSM[T] {
+	var zero T
+	fsm := NewUnstartedFSM[T](zero, maxHistory)
+	fsm.resolveInitial = resolve
+
+	return fsm
+}
+
+// Start enters the initial state of an FSM created with NewUnstartedFSM or NewFSMFunc
+// If the initial state cannot be resolved, the error is returned and the FSM stays unstarted
+// Entering it is recorded in the history as a transition from and to the initial state with Initial set,
+// and is published to subscribers and post-commit hooks and runs the state's entry action like any
+// other transition. It returns ErrAlreadyStarted if the FSM has already been started
+func (fsm *FSM[T]) Start(ctx context.Context) error {
+	fsm.mu.Lock()
+
+	if fsm.closed {
+		fsm.mu.Unlock()
+		return ErrClosed
+	}
+
+	if !fsm.unstarted {
+		fsm.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+
+	if err := ctx.Err(); err != nil {
+		fsm.mu.Unlock()
+		return err
+	}
+
+	var metadata map[string]string
+	if resolve := fsm.resolveInitial; resolve != nil {
+		// The state may be loaded from elsewhere, so it is resolved without holding the lock
+		fsm.mu.Unlock()
+		state, err := resolve(ctx)
+		if err != nil {
+			return err
+		}
+		fsm.mu.Lock()
+
+		if !fsm.unstarted {
+			fsm.mu.Unlock()
+			return ErrAlreadyStarted
+		}
+
+		fsm.currentState = state
+		metadata = map[string]string{"resolved_initial_state": toString(state)}
+	}
+
+	tn := fsm.timeNow()
+	tr := Transition[T]{
+		FromState: fsm.currentState,
+		ToState:   fsm.currentState,
+		Timestamp: &tn,
+		Metadata:  fsm.tag(ctx, metadata),
+		Initial:   true,
+	}
+
+	fsm.unstarted = false
+	fsm.recordTransition(&tr)
+	fsm.enqueue(&tr)
+	fsm.enteredAt = *tr.Timestamp
+	fsm.rememberActive(tr.ToState)
+	fsm.checkTerminal()
+	fsm.mu.Unlock()
+
+	_, err := fsm.afterCommit(ctx, &tr)
+
+	return err
+}
+
+// Started reports whether the FSM has entered its initial state
+// It is false only for an FSM created with NewUnstartedFSM until Start is called
+func (fsm *FSM[T]) Started() bool {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return !fsm.unstarted
+}
@@ -0,0 +1,103 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_start(t *testing.T) {
+	fsm := NewUnstartedFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	var entered, exited []CustomStateEnum
+	fsm.SetEntryAction(CustomStateEnumA, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		entered = append(entered, tr.ToState)
+		return nil
+	}, ActionPolicy[CustomStateEnum]{})
+	fsm.SetExitAction(CustomStateEnumA, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		exited = append(exited, tr.FromState)
+		return nil
+	}, 0)
+	sub, _ := fsm.Subscribe(SubscriptionOptions{Buffer: 10})
+
+	if fsm.Started() {
+		t.Errorf("Started() returned true before Start")
+	}
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); !errors.Is(err, ErrNotStarted) {
+		t.Errorf("Transition() returned %v before Start, expected ErrNotStarted", err)
+	}
+
+	if ex := fsm.Explain(CustomStateEnumB); ex.Reason != RejectNotStarted {
+		t.Errorf("Explain() returned %+v before Start, expected RejectNotStarted", ex)
+	}
+
+	if err := fsm.Start(context.Background()); err != nil {
+		t.Fatalf("Start() returned an error: %v", err)
+	}
+
+	history := fsm.Transitions()
+	if len(history) != 1 || !history[0].Initial || history[0].ToState != CustomStateEnumA {
+		t.Errorf("Start() recorded %v, expected an initial entry into A", history)
+	}
+
+	if len(entered) != 1 || len(exited) != 0 {
+		t.Errorf("Start() ran the entry action %d times and the exit action %d times, expected 1 and 0", len(entered), len(exited))
+	}
+
+	if states := received(sub); len(states) != 1 || states[0] != CustomStateEnumA {
+		t.Errorf("Subscriber received %v, expected the initial entry into A", states)
+	}
+
+	if err := fsm.Start(context.Background()); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("Start() returned %v when called twice, expected ErrAlreadyStarted", err)
+	}
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Errorf("Transition() returned %v after Start", err)
+	}
+
+	if len(exited) != 1 {
+		t.Errorf("Exit action ran %d times after leaving A, expected 1", len(exited))
+	}
+}
+
+func Test_newFSMFunc(t *testing.T) {
+	errUnavailable := errors.New("database unavailable")
+	available := false
+
+	fsm := NewFSMFunc(func(ctx context.Context) (CustomStateEnum, error) {
+		if !available {
+			return "", errUnavailable
+		}
+		return CustomStateEnumC, nil
+	}, 10)
+	fsm.AddRule(CustomStateEnumC, CustomStateEnumD)
+
+	if err := fsm.Start(context.Background()); !errors.Is(err, errUnavailable) {
+		t.Errorf("Start() returned %v, expected the resolution error", err)
+	}
+
+	if fsm.Started() {
+		t.Errorf("FSM was started although its initial state could not be resolved")
+	}
+
+	available = true
+	if err := fsm.Start(context.Background()); err != nil {
+		t.Fatalf("Start() returned an error: %v", err)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumC {
+		t.Errorf("Current state is %v, expected the resolved state C", fsm.CurrentState())
+	}
+
+	history := fsm.Transitions()
+	if len(history) != 1 || history[0].Metadata["resolved_initial_state"] != "C" {
+		t.Errorf("Start() recorded %v, expected the resolved state in the metadata", history)
+	}
+
+	if _, err := fsm.Transition(CustomStateEnumD, nil); err != nil {
+		t.Errorf("Transition() returned %v after Start", err)
+	}
+}
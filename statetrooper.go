@@ -27,20 +27,43 @@ SOFTWARE.
 package statetrooper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 )
 
 // Transition represents information about a state transition
 type Transition[T comparable] struct {
+	// ID identifies the transition within the FSM's history. IDs increase by one with each recorded entry
+	ID        uint64            `json:"id,omitempty"`
 	FromState T                 `json:"from_state"`
 	ToState   T                 `json:"to_state"`
 	Timestamp *time.Time        `json:"timestamp"`
 	Metadata  map[string]string `json:"metadata"`
+	// Actor is the ID of the actor the transition was attempted on behalf of, see WithActor
+	Actor string `json:"actor,omitempty"`
+	// Duplicate marks a debounced repeat request for the state the FSM was already in
+	Duplicate bool `json:"duplicate,omitempty"`
+	// Cycles is set on a compacted entry standing for FromState -> ToState -> FromState repeated Cycles times
+	// between Timestamp and Until
+	Cycles int        `json:"cycles,omitempty"`
+	Until  *time.Time `json:"until,omitempty"`
+	// Failed marks a rejected attempt recorded because SetRecordFailures is enabled, with the rejection in Error
+	Failed bool   `json:"failed,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Touch marks a request for the current state recorded under the SameStateTouch policy
+	Touch bool `json:"touch,omitempty"`
+	// Initial marks the pseudo-transition into the initial state performed by Start
+	Initial bool `json:"initial,omitempty"`
+	// Amendments lists the changes made to Metadata after the transition was recorded, oldest first
+	Amendments []Amendment `json:"amendments,omitempty"`
 }
 
 // FSM represents the finite state machine for managing states
@@ -48,17 +71,132 @@ type FSM[T comparable] struct {
 	currentState T
 	transitions  []Transition[T]
 	ruleset      map[T][]T
-	mu           sync.Mutex
-	maxHistory   int
+	// sharedRules is set while ruleset may be shared with other FSMs, so it must be copied before it is changed
+	sharedRules bool
+	states      map[T]struct{}
+	guards      map[edge[T]][]Guard[T]
+	mu          sync.Mutex
+	maxHistory  int
+	selfLoops   bool
+	now         func() time.Time
+
+	cooldown         time.Duration
+	edgeCooldowns    map[edge[T]]time.Duration
+	lastTransitionAt time.Time
+	edgeLastAt       map[edge[T]]time.Time
+
+	actorLimits  map[string]rateLimit
+	actorTimes   map[string][]time.Time
+	allowedRoles map[edge[T]][]string
+	authorizer   Authorizer[T]
+
+	maxTransitions     int
+	edgeMaxTransitions map[edge[T]]int
+	transitionCount    int
+	edgeCounts         map[edge[T]]int
+
+	debounceWindow   time.Duration
+	recordDuplicates bool
+
+	retries        map[*Retry[T]]struct{}
+	deadLetterSink DeadLetterSink[T]
+	hooks          map[HookPhase][]*registeredHook[T]
+	asyncHooks     *asyncHooks[T]
+
+	hookErrorHandler HookErrorHandler[T]
+	hookTimeout      time.Duration
+
+	entryActions map[T]stateAction[T]
+	exitActions  map[T]exitAction[T]
+
+	composites map[T]composite[T]
+	parents    map[T]T
+	lastActive map[T]T
+
+	terminals map[T]error
+	done      chan struct{}
+	finished  bool
+	outcome   error
+
+	recorder *Recorder[T]
+	aliases  map[T]T
+
+	version    int
+	migrations map[int]Migration[T]
+
+	enteredAt       time.Time
+	dwellThresholds map[T]time.Duration
+	minDwells       map[T]time.Duration
+
+	autoTransitions map[T][]autoTransition[T]
+	events          map[eventRule[T]]T
+
+	groups     map[string][]T
+	groupRules map[string][]T
+	sealed     bool
+
+	lastTouch      time.Time
+	recordTouches  bool
+	idleThresholds map[T]time.Duration
+
+	subscribers []*Subscription[T]
+
+	stringTemplate *template.Template
+	marshalOptions MarshalOptions
+	compactHistory bool
+	redactionRules []RedactionRule
+	recordFailures bool
+
+	sameStatePolicy        SameStatePolicy
+	stateSameStatePolicies map[T]SameStatePolicy
+	ruleFlags              map[edge[T]]func() bool
+
+	// metadataTags are added to the metadata of every transition, such as the experiment variant
+	metadataTags     map[string]string
+	contextExtractor ContextExtractor
+
+	historyTTL   time.Duration
+	evictHandler HistoryEvictHandler[T]
+	summary      *historySummary[T]
+	// evicted is set once transitions have been removed from the history by the history limit or TTL
+	evicted bool
+
+	lastID      uint64
+	lastStamp   time.Time
+	idGenerator IDGenerator
+	timestamper Timestamper
+
+	unstarted      bool
+	closed         bool
+	resolveInitial func(ctx context.Context) (T, error)
+
+	outboxEnabled bool
+	outbox        []Transition[T]
+
+	latency *latencyTracking[T]
+
+	// transitionPool recycles prepared transitions if pooling is enabled and historyBuf is the backing array
+	// of the history that is reused instead of growing a new one
+	transitionPool atomic.Pointer[sync.Pool]
+	historyBuf     []Transition[T]
+
+	// lockOrder is the FSM's position in the global lock order, assigned when first needed
+	lockOrder atomic.Uint64
+
+	name     string
+	entityID string
 }
 
 // NewFSM creates a new instance of FSM with predefined transitions
 func NewFSM[T comparable](initialState T, maxHistory int) *FSM[T] {
-	return &FSM[T]{
+	fsm := &FSM[T]{
 		currentState: initialState,
 		ruleset:      make(map[T][]T),
 		maxHistory:   maxHistory,
 	}
+	fsm.enteredAt = fsm.timeNow()
+
+	return fsm
 }
 
 // CanTransition checks if a transition from the current state to the target state is valid
@@ -70,66 +208,467 @@ func (fsm *FSM[T]) CanTransition(targetState T) bool {
 }
 
 // canTransition checks if a transition from one state to another state is valid
+// Rules of composite states apply to all of their substates. Disabled rules are ignored
 func (fsm *FSM[T]) canTransition(fromState *T, toState *T) bool {
-	validTransitions, ok := fsm.ruleset[*fromState]
-	if !ok {
-		return false
+	for state, ok := *fromState, true; ok; state, ok = fsm.parents[state] {
+		for _, validState := range fsm.ruleset[state] {
+			if validState == *toState && fsm.ruleEnabled(state, validState) {
+				return true
+			}
+		}
 	}
 
-	for _, validState := range validTransitions {
-		if validState == *toState {
-			return true
+	return false
+}
+
+// allowedTargets returns a copy of the valid target states from the given state
+func (fsm *FSM[T]) allowedTargets(fromState *T) []T {
+	allowed := make([]T, 0, len(fsm.ruleset[*fromState]))
+
+	for state, ok := *fromState, true; ok; state, ok = fsm.parents[state] {
+		for _, to := range fsm.ruleset[state] {
+			if fsm.ruleEnabled(state, to) {
+				allowed = append(allowed, to)
+			}
 		}
 	}
 
-	return false
+	return allowed
+}
+
+// RegisterStates registers the full set of states the FSM may use
+// Once states are registered, rules and transitions referencing unregistered states are rejected
+func (fsm *FSM[T]) RegisterStates(states ...T) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.states == nil {
+		fsm.states = make(map[T]struct{}, len(states))
+	}
+
+	for _, state := range states {
+		fsm.states[state] = struct{}{}
+	}
+}
+
+// checkRegistered returns an error if states have been registered and the given state is not one of them
+func (fsm *FSM[T]) checkRegistered(state *T) error {
+	if len(fsm.states) == 0 {
+		return nil
+	}
+
+	if _, ok := fsm.states[*state]; !ok {
+		return fmt.Errorf("%w: %v", ErrStateNotRegistered, *state)
+	}
+
+	return nil
+}
+
+// AllowSelfLoops sets whether rules from a state to itself may be added
+func (fsm *FSM[T]) AllowSelfLoops(allow bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.selfLoops = allow
 }
 
 // AddRule adds a valid transition between two states
-func (fsm *FSM[T]) AddRule(fromState T, toState ...T) {
+// An error is returned and no rules are added if any of the transitions already exists,
+// is a self-loop while self-loops are not allowed, or references an unregistered state,
+// or if the FSM has been sealed
+func (fsm *FSM[T]) AddRule(fromState T, toState ...T) error {
 	fsm.mu.Lock()
 	defer fsm.mu.Unlock()
 
+	if err := fsm.checkRule(&fromState, toState); err != nil {
+		return err
+	}
+
+	fsm.ownRules()
 	fsm.ruleset[fromState] = append(fsm.ruleset[fromState], toState...)
+
+	return nil
+}
+
+// checkRule returns an error if any of the rules from fromState to toState cannot be added
+// The caller must hold the lock
+func (fsm *FSM[T]) checkRule(fromState *T, toState []T) error {
+	if fsm.sealed {
+		return ErrSealed
+	}
+
+	if err := fsm.checkRegistered(fromState); err != nil {
+		return err
+	}
+
+	for i := range toState {
+		if err := fsm.checkRegistered(&toState[i]); err != nil {
+			return err
+		}
+
+		if !fsm.selfLoops && toState[i] == *fromState {
+			return fmt.Errorf("%w: %v -> %v", ErrSelfLoop, *fromState, toState[i])
+		}
+
+		if contains(fsm.ruleset[*fromState], toState[i]) || contains(toState[:i], toState[i]) {
+			return fmt.Errorf("%w: %v -> %v", ErrDuplicateRule, *fromState, toState[i])
+		}
+	}
+
+	return nil
 }
 
 // Transition transitions the entity from the current state to the target state
 // if the transition is invalid, an error is returned and the current state is not changed
+// Rejected transitions are sent to the dead-letter sink, if one is configured. If an entry or exit action fails
+// once the transition has been committed, an ActionError is returned instead and nothing is dead-lettered
 func (fsm *FSM[T]) Transition(targetState T, metadata map[string]string) (T, error) {
+	return fsm.TransitionCtx(context.Background(), targetState, metadata)
+}
+
+// TransitionCtx is like Transition but passes ctx to guards and hooks
+// If ctx is done before the transition is committed, including while guards or pre-commit hooks are running,
+// an error wrapping ctx.Err() is returned and the current state is not changed. Like any other rejection,
+// the cancelled attempt is recorded as a failed transition if SetRecordFailures is enabled
+func (fsm *FSM[T]) TransitionCtx(ctx context.Context, targetState T, metadata map[string]string) (T, error) {
+	state, committed, err := fsm.apply(ctx, targetState, metadata)
+	if err != nil && !committed {
+		fsm.deadLetter(state, targetState, metadata, err, 1)
+	}
+
+	return state, err
+}
+
+// apply performs a single transition attempt and runs the post-commit hooks once it is committed and unlocked
+// It reports whether the transition was committed, in which case any error occurred after the commit
+func (fsm *FSM[T]) apply(ctx context.Context, targetState T, metadata map[string]string) (T, bool, error) {
+	ctx, recorder, in := fsm.startRecording(ctx, targetState, metadata)
+	ctx, timings, fromState := fsm.startTiming(ctx)
+	start := startPhase(timings)
+
+	state, committed, err := fsm.transition(ctx, targetState, metadata)
+	targetState = fsm.firedTarget(ctx, targetState)
+	if committed != nil {
+		// A chain of automatic transitions may end in a loop error, which is reported with any action errors
+		var actionErr error
+		state, actionErr = fsm.afterCommitChain(ctx, committed)
+		err = errors.Join(err, actionErr)
+	}
+	err = fsm.attribute(err)
+
+	if timings != nil {
+		timings.Total = time.Since(start)
+		fsm.observeLatency(fromState, targetState, timings, err)
+	}
+
+	fsm.release(committed)
+
+	if recorder != nil {
+		in.Target = targetState
+		in.State = state
+		if err != nil {
+			in.Err = err.Error()
+		}
+		recorder.add(*in)
+	}
+
+	return state, committed != nil, err
+}
+
+// afterCommit publishes a committed transition and runs the post-commit hooks and state actions
+// It must be called without holding the lock
+func (fsm *FSM[T]) afterCommit(ctx context.Context, committed *Transition[T]) (T, error) {
+	fsm.committed(ctx, committed)
+
+	timings := timingsFrom(ctx)
+	start := startPhase(timings)
+	defer endPhase(timings, actionsPhase, start)
+
+	// The exit action of the previous state always completes before the entry action of the new state
+	exitErr := fsm.runExitAction(ctx, committed)
+	state, err := fsm.runEntryAction(ctx, committed)
+	if exitErr != nil {
+		err = errors.Join(exitErr, err)
+	}
+
+	return state, err
+}
+
+// force moves the FSM from expectedState to targetState without checking rules, guards or hooks
+// It is used for compensating transitions and does nothing if the FSM is no longer in expectedState
+func (fsm *FSM[T]) force(expectedState T, targetState T, metadata map[string]string) *Transition[T] {
 	fsm.mu.Lock()
 	defer fsm.mu.Unlock()
 
+	if fsm.currentState != expectedState {
+		return nil
+	}
+
+	tn := fsm.timeNow()
+	tr := Transition[T]{
+		FromState: fsm.currentState,
+		ToState:   targetState,
+		Timestamp: &tn,
+		Metadata:  metadata,
+	}
+
+	fsm.recordTransition(&tr)
+	fsm.enqueue(&tr)
+	fsm.countTransition(&tr)
+	fsm.currentState = targetState
+	fsm.enteredAt = *tr.Timestamp
+	fsm.rememberActive(targetState)
+	fsm.checkTerminal()
+
+	return &tr
+}
+
+// transition performs a single transition attempt under the lock
+// The committed transitions, including any automatic ones that followed, are returned if the state was changed
+func (fsm *FSM[T]) transition(ctx context.Context, targetState T, metadata map[string]string) (T, []*Transition[T], error) {
+	timings := timingsFrom(ctx)
+	start := startPhase(timings)
+
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	endPhase(timings, lockWaitPhase, start)
+
+	targetState, err := fsm.resolveEvent(ctx, targetState)
+	if err != nil {
+		return fsm.currentState, nil, err
+	}
+
+	metadata = fsm.tag(ctx, metadata)
+
+	tr, err := fsm.prepare(ctx, targetState, metadata)
+	if err != nil {
+		fsm.recordFailure(ctx, targetState, metadata, err)
+		return fsm.currentState, nil, err
+	}
+
+	if tr == nil {
+		return fsm.currentState, nil, nil
+	}
+
+	start = startPhase(timings)
+	chain, err := fsm.commitChain(ctx, tr)
+	endPhase(timings, commitPhase, start)
+
+	return fsm.currentState, chain, err
+}
+
+// prepare checks a transition attempt without changing the state and returns the transition to commit
+// A nil transition and error means the attempt was a debounced or same-state no-op, while a touch or
+// duplicate entry is returned for a no-op that is recorded in the history. The caller must hold the lock
+func (fsm *FSM[T]) prepare(ctx context.Context, targetState T, metadata map[string]string) (*Transition[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	tn := fsm.timeNow()
+	fsm.recordClock(ctx, tn)
+
+	actor, _ := ActorFromContext(ctx)
+	targetState, noOp, _, err := fsm.checkTransition(ctx, &actor, targetState, tn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Debounced and same-state requests succeed without a transition. Any entry they leave in the history
+	// is returned to be recorded on commit, so that nothing is written if the caller aborts instead
+	if noOp {
+		if tr, ok := fsm.debounced(&targetState, metadata, tn); ok {
+			return tr, nil
+		}
+
+		tr, _ := fsm.sameState(&targetState, metadata, tn)
+		return tr, nil
+	}
+
+	tr := fsm.newTransition()
+	*tr = Transition[T]{
+		FromState: fsm.currentState,
+		ToState:   targetState,
+		Timestamp: &tn,
+		Metadata:  metadata,
+		Actor:     actor.ID,
+	}
+
+	timings := timingsFrom(ctx)
+
+	start := startPhase(timings)
+	err = fsm.checkGuards(ctx, tr)
+	endPhase(timings, guardsPhase, start)
+	if err != nil {
+		return nil, err
+	}
+
+	start = startPhase(timings)
+	err = fsm.runPreCommitHooks(ctx, tr)
+	endPhase(timings, hooksPhase, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// Guards and hooks may have used up the deadline, in which case nothing is committed
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tr, nil
+}
+
+// checkTransition runs the checks a transition attempt to targetState must pass before its guards, in the
+// order they are applied. It is shared by prepare and ExplainCtx, so the two cannot disagree
+// noOp reports a debounced or same-state request, which succeeds without further checks. Otherwise the
+// state the transition enters is returned, or the reason and error it is rejected with
+// The caller must hold the lock
+func (fsm *FSM[T]) checkTransition(ctx context.Context, actor *Actor, targetState T, tn time.Time) (T, bool, RejectReason, error) {
+	reject := func(reason RejectReason, err error) (T, bool, RejectReason, error) {
+		return targetState, false, reason, err
+	}
+
+	if fsm.closed {
+		return reject(RejectClosed, ErrClosed)
+	}
+
+	if fsm.unstarted {
+		return reject(RejectNotStarted, ErrNotStarted)
+	}
+
+	if err := fsm.checkRegistered(&targetState); err != nil {
+		return reject(RejectNotRegistered, err)
+	}
+
+	if fsm.duplicate(&targetState, tn) || fsm.sameStatePolicyFor(&targetState) != SameStateError {
+		return targetState, true, NotRejected, nil
+	}
+
 	if !fsm.canTransition(&fsm.currentState, &targetState) {
-		return fsm.currentState, TransitionError[T]{
+		err := TransitionError[T]{
 			FromState: fsm.currentState,
 			ToState:   targetState,
+			Allowed:   fsm.allowedTargets(&fsm.currentState),
+			Timestamp: tn,
+			Machine:   identity(fsm.name, fsm.entityID),
 		}
+
+		if _, ok := fsm.terminals[fsm.currentState]; ok {
+			return reject(RejectTerminal, err)
+		}
+
+		if fsm.hasRule(edge[T]{from: fsm.currentState, to: targetState}) {
+			return reject(RejectDisabled, err)
+		}
+
+		return reject(RejectNoRule, err)
+	}
+
+	if err := fsm.checkRoles(actor, &fsm.currentState, &targetState); err != nil {
+		return reject(RejectUnauthorized, err)
+	}
+
+	if err := fsm.authorize(ctx, actor, &fsm.currentState, &targetState); err != nil {
+		return reject(RejectUnauthorized, err)
+	}
+
+	// A composite target is entered at its initial or remembered substate
+	targetState = fsm.resolveTarget(targetState)
+
+	if err := fsm.checkMinDwell(&targetState, tn); err != nil {
+		return reject(RejectMinDwell, err)
+	}
+
+	if err := fsm.checkCooldown(&fsm.currentState, &targetState, tn); err != nil {
+		return reject(RejectCooldown, err)
 	}
 
+	if err := fsm.checkBudget(&fsm.currentState, &targetState); err != nil {
+		return reject(RejectBudget, err)
+	}
+
+	if err := fsm.checkThrottle(actor.ID, &fsm.currentState, &targetState, tn); err != nil {
+		return reject(RejectThrottled, err)
+	}
+
+	return targetState, false, NotRejected, nil
+}
+
+// commit applies a prepared transition. The caller must hold the lock
+func (fsm *FSM[T]) commit(tr *Transition[T]) {
+	fsm.recordTransition(tr)
+	fsm.enqueue(tr)
+	fsm.markCooldown(tr)
+	fsm.markThrottle(tr)
+	fsm.countTransition(tr)
+	fsm.currentState = tr.ToState
+	fsm.enteredAt = *tr.Timestamp
+	fsm.rememberActive(tr.ToState)
+	fsm.checkTerminal()
+}
+
+// recordTransition assigns the transition its ID and timestamp and appends it to the history, evicting the oldest entry if needed
+func (fsm *FSM[T]) recordTransition(tr *Transition[T]) {
+	fsm.stamp(tr)
+
 	if fsm.maxHistory == 0 {
-		fsm.currentState = targetState
-		return fsm.currentState, nil
+		return
 	}
 
-	// Track the transition
+	fsm.pruneHistory()
+
 	// Check if we need to remove the oldest transition
 	if len(fsm.transitions) >= fsm.maxHistory {
-		fsm.transitions = fsm.transitions[1:]
+		fsm.evict(1)
 	}
 
-	tn := time.Now()
-	fsm.transitions = append(
-		fsm.transitions,
-		Transition[T]{
-			FromState: fsm.currentState,
-			ToState:   targetState,
-			Timestamp: &tn,
-			Metadata:  metadata,
-		})
+	pooling := fsm.transitionPool.Load() != nil
+	if pooling {
+		fsm.reuseHistory()
+	}
 
-	fsm.currentState = targetState
+	fsm.transitions = append(fsm.transitions, *tr)
+
+	if pooling && !sameArray(fsm.historyBuf, fsm.transitions) {
+		fsm.historyBuf = fsm.transitions[:0]
+	}
+
+	if fsm.compactHistory {
+		fsm.transitions = compactLoops(fsm.transitions)
+	}
+}
+
+// SetClock sets the function used to timestamp transitions and evaluate cooldowns and debouncing
+// Passing nil restores time.Now
+// Deterministic runtimes such as Temporal workflows should pass their own clock, e.g. workflow.Now
+// If the FSM has not transitioned yet, the time its initial state was entered is read from the new clock
+func (fsm *FSM[T]) SetClock(now func() time.Time) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.now = now
+
+	if len(fsm.transitions) == 0 && fsm.lastTransitionAt.IsZero() {
+		fsm.enteredAt = fsm.timeNow()
+	}
+}
 
-	return fsm.currentState, nil
+// timeNow returns the current time from the FSM's clock
+func (fsm *FSM[T]) timeNow() time.Time {
+	if fsm.now != nil {
+		return fsm.now()
+	}
+
+	return time.Now()
+}
+
+// lockedNow is like timeNow for callers that don't hold the lock
+func (fsm *FSM[T]) lockedNow() time.Time {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.timeNow()
 }
 
 // CurrentState returns the current state of the FSM
@@ -153,6 +692,16 @@ func (fsm *FSM[T]) Transitions() []Transition[T] {
 	return transitions
 }
 
+// WithTransitionsLocked calls fn with the retained history without copying it, for frequent reads of large
+// histories such as metrics scrapes. The FSM is locked while fn runs, so fn must not call back into the FSM,
+// and it must neither modify the slice nor retain it after returning
+func (fsm *FSM[T]) WithTransitionsLocked(fn func(transitions []Transition[T])) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fn(fsm.transitions[:len(fsm.transitions):len(fsm.transitions)])
+}
+
 // Rules returns the configured ruleset of the FSM
 func (fsm *FSM[T]) Rules() map[T][]T {
 	fsm.mu.Lock()
@@ -196,7 +745,7 @@ func (fsm *FSM[T]) GenerateMermaidRulesDiagram() (string, error) {
 	var nodes []string
 
 	for state := range fsm.ruleset {
-		nodes = append(nodes, toString(state))
+		nodes = append(nodes, mermaidLabel(state))
 	}
 
 	// Sort nodes
@@ -254,7 +803,7 @@ func (fsm *FSM[T]) GenerateMermaidTransitionHistoryDiagram() (string, error) {
 	var nodes []string
 
 	for state := range uniqueStates {
-		nodes = append(nodes, fmt.Sprintf("%s;\n", toString(state)))
+		nodes = append(nodes, fmt.Sprintf("%s;\n", mermaidLabel(state)))
 	}
 
 	// Sort nodes
@@ -287,13 +836,37 @@ func (fsm *FSM[T]) MarshalJSON() ([]byte, error) {
 	defer fsm.mu.Unlock()
 
 	type FSMExport struct {
+		Name         string          `json:"name,omitempty"`
+		EntityID     string          `json:"entity_id,omitempty"`
+		Version      int             `json:"version,omitempty"`
 		CurrentState T               `json:"current_state"`
 		Transitions  []Transition[T] `json:"transitions"`
+		Ruleset      []RuleExport[T] `json:"ruleset,omitempty"`
+		MaxHistory   *int            `json:"max_history,omitempty"`
+		Stats        *Stats[T]       `json:"stats,omitempty"`
+		Outbox       []Transition[T] `json:"outbox,omitempty"`
 	}
 
 	export := FSMExport{
+		Name:         fsm.name,
+		EntityID:     fsm.entityID,
+		Version:      fsm.version,
 		CurrentState: fsm.currentState,
-		Transitions:  fsm.transitions,
+		Transitions:  fsm.redact(fsm.transitions),
+		Outbox:       fsm.redact(fsm.outbox),
+	}
+
+	if fsm.marshalOptions.IncludeRuleset {
+		export.Ruleset = fsm.exportRuleset()
+	}
+
+	if fsm.marshalOptions.IncludeMaxHistory {
+		export.MaxHistory = &fsm.maxHistory
+	}
+
+	if fsm.marshalOptions.IncludeStats {
+		stats := fsm.stats()
+		export.Stats = &stats
 	}
 
 	return json.Marshal(export)
@@ -305,8 +878,13 @@ func (fsm *FSM[T]) UnmarshalJSON(data []byte) error {
 	defer fsm.mu.Unlock()
 
 	type FSMImport struct {
+		Name         string          `json:"name"`
+		EntityID     string          `json:"entity_id"`
+		Version      int             `json:"version"`
 		CurrentState T               `json:"current_state"`
 		Transitions  []Transition[T] `json:"transitions"`
+		Stats        *Stats[T]       `json:"stats"`
+		Outbox       []Transition[T] `json:"outbox"`
 	}
 
 	var importData FSMImport
@@ -315,7 +893,15 @@ func (fsm *FSM[T]) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	fsm.currentState = importData.CurrentState
+	if err := fsm.checkMigrations(importData.Version); err != nil {
+		return err
+	}
+
+	fsm.currentState = fsm.resolveAlias(importData.CurrentState)
+	for i := range importData.Transitions {
+		importData.Transitions[i].FromState = fsm.resolveAlias(importData.Transitions[i].FromState)
+		importData.Transitions[i].ToState = fsm.resolveAlias(importData.Transitions[i].ToState)
+	}
 
 	var s int
 
@@ -327,6 +913,46 @@ func (fsm *FSM[T]) UnmarshalJSON(data []byte) error {
 
 	fsm.transitions = importData.Transitions[:s]
 
+	// An FSM created without an identity takes the one it was exported with
+	if fsm.name == "" {
+		fsm.name = importData.Name
+	}
+	if fsm.entityID == "" {
+		fsm.entityID = importData.EntityID
+	}
+
+	// A restored FSM continues where it left off, so it does not need to be started again
+	fsm.unstarted = false
+
+	// New transitions continue the restored IDs
+	fsm.lastID = 0
+	fsm.lastStamp = time.Time{}
+	for _, tr := range fsm.transitions {
+		if tr.ID > fsm.lastID {
+			fsm.lastID = tr.ID
+		}
+		if tr.Timestamp != nil && tr.Timestamp.After(fsm.lastStamp) {
+			fsm.lastStamp = *tr.Timestamp
+		}
+	}
+
+	fsm.enteredAt = fsm.timeNow()
+	if n := len(importData.Transitions); n > 0 && importData.Transitions[n-1].Timestamp != nil {
+		fsm.enteredAt = *importData.Transitions[n-1].Timestamp
+	}
+
+	// Counters are only replaced when they were exported, otherwise they keep counting from where they were
+	if importData.Stats != nil {
+		fsm.restoreStats(importData.Stats)
+	}
+
+	// Pending events are relayed after a restart, so none is lost
+	if fsm.outboxEnabled {
+		fsm.outbox = importData.Outbox
+	}
+
+	fsm.migrate(importData.Version)
+
 	return nil
 }
 
@@ -335,11 +961,15 @@ func (fsm *FSM[T]) String() string {
 	fsm.mu.Lock()
 	defer fsm.mu.Unlock()
 
-	currentState := fmt.Sprintf("Current State: %v\n", fsm.currentState)
+	if fsm.stringTemplate != nil {
+		return fsm.formatString()
+	}
+
+	currentState := fmt.Sprintf("Current State: %v\n", display(fsm.currentState))
 
 	rules := "Rules:\n"
 	for fromState, toStates := range fsm.ruleset {
-		rules += fmt.Sprintf("\t%v -> %v\n", fromState, toStates)
+		rules += fmt.Sprintf("\t%v -> %v\n", display(fromState), displayAll(toStates))
 	}
 
 	transitions := "Transitions:\n"
@@ -352,5 +982,5 @@ func (fsm *FSM[T]) String() string {
 
 // String returns a string representation of the Transition
 func (t *Transition[T]) String() string {
-	return fmt.Sprintf("Transition from %v to %v at %v with metadata %v", t.FromState, t.ToState, t.Timestamp, t.Metadata)
+	return fmt.Sprintf("Transition from %v to %v at %v with metadata %v", display(t.FromState), display(t.ToState), t.Timestamp, t.Metadata)
 }
@@ -26,6 +26,7 @@ package statetrooper
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"sort"
 	"sync"
@@ -369,6 +370,72 @@ func Test_unmarshalJSON(t *testing.T) {
 	}
 }
 
+func Test_stateRegistry(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.RegisterStates(CustomStateEnumA, CustomStateEnumB, CustomStateEnumC)
+
+	if err := fsm.AddRule(CustomStateEnumA, CustomStateEnumB); err != nil {
+		t.Errorf("AddRule(%v, %v) returned an error: %v", CustomStateEnumA, CustomStateEnumB, err)
+	}
+
+	if err := fsm.AddRule(CustomStateEnumB, CustomStateEnumD); !errors.Is(err, ErrStateNotRegistered) {
+		t.Errorf("AddRule(%v, %v) returned %v, expected ErrStateNotRegistered", CustomStateEnumB, CustomStateEnumD, err)
+	}
+
+	if err := fsm.AddRule(CustomStateEnumD, CustomStateEnumA); !errors.Is(err, ErrStateNotRegistered) {
+		t.Errorf("AddRule(%v, %v) returned %v, expected ErrStateNotRegistered", CustomStateEnumD, CustomStateEnumA, err)
+	}
+
+	if _, ok := fsm.Rules()[CustomStateEnumD]; ok {
+		t.Errorf("AddRule with an unregistered state should not modify the ruleset")
+	}
+
+	if _, err := fsm.Transition(CustomStateEnumD, nil); !errors.Is(err, ErrStateNotRegistered) {
+		t.Errorf("Transition(%v) returned %v, expected ErrStateNotRegistered", CustomStateEnumD, err)
+	}
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Errorf("Transition(%v) returned an error: %v", CustomStateEnumB, err)
+	}
+}
+
+func Test_addRuleValidation(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+
+	if err := fsm.AddRule(CustomStateEnumA, CustomStateEnumB); err != nil {
+		t.Errorf("AddRule(%v, %v) returned an error: %v", CustomStateEnumA, CustomStateEnumB, err)
+	}
+
+	tests := []struct {
+		fromState CustomStateEnum
+		toStates  []CustomStateEnum
+		wantErr   error
+	}{
+		{CustomStateEnumA, []CustomStateEnum{CustomStateEnumB}, ErrDuplicateRule},                   // Existing rule
+		{CustomStateEnumA, []CustomStateEnum{CustomStateEnumC, CustomStateEnumC}, ErrDuplicateRule}, // Duplicate within the same call
+		{CustomStateEnumA, []CustomStateEnum{CustomStateEnumA}, ErrSelfLoop},                        // Self-loop
+	}
+
+	for _, test := range tests {
+		err := fsm.AddRule(test.fromState, test.toStates...)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("AddRule(%v, %v) returned %v, expected %v", test.fromState, test.toStates, err, test.wantErr)
+		}
+	}
+
+	// Rejected calls must not partially modify the ruleset
+	expected := map[CustomStateEnum][]CustomStateEnum{CustomStateEnumA: {CustomStateEnumB}}
+	if !reflect.DeepEqual(fsm.Rules(), expected) {
+		t.Errorf("Rules() = %v, expected %v", fsm.Rules(), expected)
+	}
+
+	fsm.AllowSelfLoops(true)
+
+	if err := fsm.AddRule(CustomStateEnumA, CustomStateEnumA); err != nil {
+		t.Errorf("AddRule(%v, %v) with self-loops allowed returned an error: %v", CustomStateEnumA, CustomStateEnumA, err)
+	}
+}
+
 func Benchmark_singleTransition(b *testing.B) {
 	// CustomEntity represents a custom entity with its current state
 	type CustomEntity struct {
@@ -449,6 +516,21 @@ func Benchmark_accessTransitions(b *testing.B) {
 	}
 }
 
+func Benchmark_accessTransitionsLocked(b *testing.B) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+
+	fsm.Transition(CustomStateEnumB, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fsm.WithTransitionsLocked(func(transitions []Transition[CustomStateEnum]) {
+			_ = len(transitions)
+		})
+	}
+}
+
 func Benchmark_marshalJSON(b *testing.B) {
 	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
 	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
@@ -492,3 +574,47 @@ func Benchmark_unmarshalJSON(b *testing.B) {
 		}
 	}
 }
+
+func Test_setClock(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+
+	now := time.Date(2023, 7, 1, 15, 0, 0, 0, time.UTC)
+	fsm.SetClock(func() time.Time { return now })
+
+	if _, err := fsm.Transition(CustomStateEnumB, nil); err != nil {
+		t.Fatalf("Transition() returned an error: %v", err)
+	}
+
+	if tr := fsm.Transitions()[0]; !tr.Timestamp.Equal(now) {
+		t.Errorf("Transition timestamp is %v, expected %v", tr.Timestamp, now)
+	}
+
+	fsm.SetClock(nil)
+	if fsm.timeNow().Equal(now) {
+		t.Errorf("Clock was not reset to time.Now")
+	}
+}
+
+func Test_withTransitionsLocked(t *testing.T) {
+	fsm := newPingPongFSM()
+	pingPong(fsm, 3)
+
+	var states []CustomStateEnum
+	fsm.WithTransitionsLocked(func(transitions []Transition[CustomStateEnum]) {
+		for _, tr := range transitions {
+			states = append(states, tr.ToState)
+		}
+
+		// Appending must not write into the history
+		_ = append(transitions, Transition[CustomStateEnum]{})
+	})
+
+	if len(states) != 3 || states[0] != CustomStateEnumB || states[2] != CustomStateEnumB {
+		t.Errorf("WithTransitionsLocked passed transitions into %v, expected B, A, B", states)
+	}
+
+	if n := len(fsm.Transitions()); n != 3 {
+		t.Errorf("history has %d transitions, expected 3", n)
+	}
+}
@@ -0,0 +1,84 @@
+package statetrooper
+
+import "sort"
+
+// Stats is a snapshot of the FSM's aggregate counters
+// Unlike the history, the counters are never truncated, so they can be carried across restarts with
+// MarshalOptions.IncludeStats to keep metrics and transition budgets from being reset
+type Stats[T comparable] struct {
+	// Name and EntityID identify the FSM, so they can be used as metric labels
+	Name     string `json:"name,omitempty"`
+	EntityID string `json:"entity_id,omitempty"`
+	// TransitionCount is the total number of transitions performed
+	TransitionCount int `json:"transition_count"`
+	// Edges counts the transitions performed along each edge, ordered by from and to state
+	Edges []EdgeCount[T] `json:"edges,omitempty"`
+	// Summary is the summary of evicted history entries, if enabled with SetHistorySummary
+	Summary *HistorySummary[T] `json:"summary,omitempty"`
+}
+
+// Stats returns a snapshot of the FSM's aggregate counters
+func (fsm *FSM[T]) Stats() Stats[T] {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.stats()
+}
+
+// stats returns a snapshot of the aggregate counters. The caller must hold the lock
+func (fsm *FSM[T]) stats() Stats[T] {
+	stats := Stats[T]{
+		Name:            fsm.name,
+		EntityID:        fsm.entityID,
+		TransitionCount: fsm.transitionCount,
+		Edges:           sortedEdgeCounts(fsm.edgeCounts),
+	}
+
+	if fsm.summary != nil {
+		summary := fsm.summary.export()
+		stats.Summary = &summary
+	}
+
+	return stats
+}
+
+// restoreStats replaces the aggregate counters with stats, resolving aliased states. The caller must hold the lock
+func (fsm *FSM[T]) restoreStats(stats *Stats[T]) {
+	fsm.transitionCount = stats.TransitionCount
+	fsm.edgeCounts = fsm.importEdgeCounts(stats.Edges)
+
+	if stats.Summary != nil {
+		fsm.summary = &historySummary[T]{
+			evicted: stats.Summary.Evicted,
+			failed:  stats.Summary.Failed,
+			edges:   fsm.importEdgeCounts(stats.Summary.Edges),
+			first:   stats.Summary.First,
+			last:    stats.Summary.Last,
+		}
+	}
+}
+
+// importEdgeCounts converts edge counts to a map, resolving aliased states
+func (fsm *FSM[T]) importEdgeCounts(counts []EdgeCount[T]) map[edge[T]]int {
+	edges := make(map[edge[T]]int, len(counts))
+	for _, c := range counts {
+		edges[edge[T]{from: fsm.resolveAlias(c.From), to: fsm.resolveAlias(c.To)}] += c.Count
+	}
+
+	return edges
+}
+
+// sortedEdgeCounts converts a map of edge counts to a list ordered by the string form of the from and to states
+func sortedEdgeCounts[T comparable](edges map[edge[T]]int) []EdgeCount[T] {
+	var counts []EdgeCount[T]
+	for e, count := range edges {
+		counts = append(counts, EdgeCount[T]{From: e.from, To: e.to, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		return toString(a.From)+"\x00"+toString(a.To) < toString(b.From)+"\x00"+toString(b.To)
+	})
+
+	return counts
+}
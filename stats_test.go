@@ -0,0 +1,68 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_statsSurviveRestart(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 2)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.SetHistorySummary(true)
+	pingPong(fsm, 5)
+
+	data, err := json.Marshal(fsm)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	if strings.Contains(string(data), "stats") {
+		t.Errorf("json.Marshal() returned %s, expected no stats by default", data)
+	}
+
+	fsm.SetMarshalOptions(MarshalOptions{IncludeStats: true})
+
+	data, err = json.Marshal(fsm)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	restored := NewFSM[CustomStateEnum](CustomStateEnumA, 2)
+	restored.AddRule(CustomStateEnumA, CustomStateEnumB)
+	restored.AddRule(CustomStateEnumB, CustomStateEnumA)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	got, expected := restored.Stats(), fsm.Stats()
+	if got.TransitionCount != expected.TransitionCount || !reflect.DeepEqual(got.Edges, expected.Edges) {
+		t.Errorf("Restored stats are %+v, expected %+v", got, expected)
+	}
+
+	if got.Summary == nil || !reflect.DeepEqual(got.Summary.Edges, expected.Summary.Edges) || !got.Summary.Last.Equal(*expected.Summary.Last) {
+		t.Errorf("Restored summary is %+v, expected %+v", got.Summary, expected.Summary)
+	}
+
+	if restored.TransitionCount() != 5 || restored.EdgeCount(CustomStateEnumB, CustomStateEnumA) != 2 {
+		t.Errorf("Restored counts are %d and %d, expected 5 and 2", restored.TransitionCount(), restored.EdgeCount(CustomStateEnumB, CustomStateEnumA))
+	}
+
+	if summary := restored.HistorySummary(); summary.Evicted != 3 {
+		t.Errorf("Restored summary counts %d evicted transitions, expected 3", summary.Evicted)
+	}
+
+	// Budgets apply to the restored counts
+	restored.SetMaxTransitions(6)
+	if _, err := restored.Transition(CustomStateEnumA, nil); err != nil {
+		t.Errorf("Transition() returned an error: %v", err)
+	}
+
+	var budgetErr BudgetError[CustomStateEnum]
+	if _, err := restored.Transition(CustomStateEnumB, nil); !errors.As(err, &budgetErr) {
+		t.Errorf("Transition() returned %v, expected a BudgetError", err)
+	}
+}
@@ -0,0 +1,243 @@
+package statetrooper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy determines what happens when a transition is published to a subscription whose buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest discards the oldest buffered transition to make room for the new one
+	// It is the default, so a slow subscriber cannot stall transitions
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowDropNewest discards the new transition
+	OverflowDropNewest
+	// OverflowCoalesce discards all buffered transitions in favour of the new one, so a lagging subscriber
+	// skips straight to the latest transition
+	OverflowCoalesce
+	// OverflowBlock blocks the transition until the subscriber makes room, so a slow subscriber stalls
+	// every caller of Transition. It requires a positive Buffer
+	OverflowBlock
+)
+
+// SubscriptionOptions configures a subscription
+type SubscriptionOptions struct {
+	// Buffer is the number of transitions buffered for the subscriber
+	// Without a buffer, transitions are only delivered to a subscriber already waiting on the channel
+	Buffer int
+	// Overflow determines what happens when the buffer is full, OverflowDropOldest by default
+	Overflow OverflowPolicy
+}
+
+// Filter selects the transitions delivered to a subscription
+type Filter[T comparable] func(tr Transition[T]) bool
+
+// FilterTo selects transitions into any of the given states
+func FilterTo[T comparable](states ...T) Filter[T] {
+	return func(tr Transition[T]) bool {
+		return contains(states, tr.ToState)
+	}
+}
+
+// FilterEdge selects transitions from fromState to toState
+func FilterEdge[T comparable](fromState T, toState T) Filter[T] {
+	return func(tr Transition[T]) bool {
+		return tr.FromState == fromState && tr.ToState == toState
+	}
+}
+
+// FilterMetadata selects transitions whose metadata satisfies match
+func FilterMetadata[T comparable](match func(metadata map[string]string) bool) Filter[T] {
+	return func(tr Transition[T]) bool {
+		return match(tr.Metadata)
+	}
+}
+
+// Subscription receives the transitions committed by an FSM
+type Subscription[T comparable] struct {
+	c        chan Transition[T]
+	overflow OverflowPolicy
+	filters  []Filter[T]
+	dropped  atomic.Uint64
+
+	// mu serializes publishing so that dropping and sending are not interleaved
+	mu     sync.Mutex
+	closed chan struct{}
+	once   sync.Once
+	fsm    *FSM[T]
+}
+
+// Subscribe returns a subscription that receives the transitions committed from now on that match all filters
+// Transitions are published after they are committed and unlocked, before the post-commit hooks run
+// Each subscription has its own buffer, so a slow subscriber only affects others under OverflowBlock
+// An error wrapping ErrInvalidConfig is returned for OverflowBlock without a buffer
+func (fsm *FSM[T]) Subscribe(opts SubscriptionOptions, filters ...Filter[T]) (*Subscription[T], error) {
+	if opts.Overflow == OverflowBlock && opts.Buffer <= 0 {
+		return nil, fmt.Errorf("%w: OverflowBlock requires a positive buffer", ErrInvalidConfig)
+	}
+
+	sub := &Subscription[T]{
+		c:        make(chan Transition[T], opts.Buffer),
+		overflow: opts.Overflow,
+		filters:  filters,
+		closed:   make(chan struct{}),
+		fsm:      fsm,
+	}
+
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	// Copy on write so subscribers can be published to from a snapshot without holding the lock
+	subs := make([]*Subscription[T], len(fsm.subscribers), len(fsm.subscribers)+1)
+	copy(subs, fsm.subscribers)
+	fsm.subscribers = append(subs, sub)
+
+	return sub, nil
+}
+
+// C returns the channel transitions are delivered on
+// It is closed when the subscription is closed
+func (s *Subscription[T]) C() <-chan Transition[T] {
+	return s.c
+}
+
+// Dropped returns the number of transitions discarded because the buffer was full
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes from the FSM and closes the channel
+// A transition blocked on a full buffer is released
+func (s *Subscription[T]) Close() {
+	s.once.Do(func() {
+		close(s.closed)
+
+		s.fsm.mu.Lock()
+		subs := make([]*Subscription[T], 0, len(s.fsm.subscribers))
+		for _, sub := range s.fsm.subscribers {
+			if sub != s {
+				subs = append(subs, sub)
+			}
+		}
+		s.fsm.subscribers = subs
+		s.fsm.mu.Unlock()
+
+		s.mu.Lock()
+		close(s.c)
+		s.mu.Unlock()
+	})
+}
+
+// publish delivers tr according to the subscription's overflow policy
+func (s *Subscription[T]) publish(tr Transition[T]) {
+	for _, filter := range s.filters {
+		if !filter(tr) {
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.closed:
+		return
+	default:
+	}
+
+	switch s.overflow {
+	case OverflowBlock:
+		select {
+		case s.c <- tr:
+		case <-s.closed:
+		}
+	case OverflowDropNewest:
+		select {
+		case s.c <- tr:
+		default:
+			s.dropped.Add(1)
+		}
+	case OverflowDropOldest, OverflowCoalesce:
+		for {
+			select {
+			case s.c <- tr:
+				return
+			default:
+			}
+
+			// Without a buffer there is nothing older to drop
+			if cap(s.c) == 0 {
+				s.dropped.Add(1)
+				return
+			}
+
+			// The subscriber may have drained the buffer in the meantime, in which case nothing is dropped
+			select {
+			case <-s.c:
+				s.dropped.Add(1)
+			default:
+			}
+
+			if s.overflow == OverflowCoalesce {
+				s.drain()
+			}
+		}
+	}
+}
+
+// drain discards all buffered transitions
+func (s *Subscription[T]) drain() {
+	for {
+		select {
+		case <-s.c:
+			s.dropped.Add(1)
+		default:
+			return
+		}
+	}
+}
+
+// publish delivers a committed transition to all matching subscribers
+func (fsm *FSM[T]) publish(tr *Transition[T]) {
+	fsm.mu.Lock()
+	subs := fsm.subscribers
+	fsm.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.publish(*tr)
+	}
+}
+
+// committed publishes a committed transition and runs the post-commit hooks
+func (fsm *FSM[T]) committed(ctx context.Context, tr *Transition[T]) {
+	timings := timingsFrom(ctx)
+
+	start := startPhase(timings)
+	fsm.publish(tr)
+	endPhase(timings, publishPhase, start)
+
+	start = startPhase(timings)
+	fsm.runPostCommitHooks(ctx, tr)
+	endPhase(timings, hooksPhase, start)
+}
+
+// NextTransition blocks until the next transition is committed and returns it
+// It returns ctx.Err() if ctx is done first
+func (fsm *FSM[T]) NextTransition(ctx context.Context) (Transition[T], error) {
+	sub, err := fsm.Subscribe(SubscriptionOptions{Buffer: 1, Overflow: OverflowDropNewest})
+	if err != nil {
+		return Transition[T]{}, err
+	}
+	defer sub.Close()
+
+	select {
+	case tr := <-sub.C():
+		return tr, nil
+	case <-ctx.Done():
+		return Transition[T]{}, ctx.Err()
+	}
+}
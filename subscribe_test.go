@@ -0,0 +1,214 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// pingPong moves the FSM between A and B n times
+func pingPong(fsm *FSM[CustomStateEnum], n int) {
+	for i := 0; i < n; i++ {
+		if fsm.CurrentState() == CustomStateEnumA {
+			fsm.Transition(CustomStateEnumB, nil)
+		} else {
+			fsm.Transition(CustomStateEnumA, nil)
+		}
+	}
+}
+
+func newPingPongFSM() *FSM[CustomStateEnum] {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	return fsm
+}
+
+// received returns the target states buffered in sub
+func received(sub *Subscription[CustomStateEnum]) []CustomStateEnum {
+	var states []CustomStateEnum
+	for {
+		select {
+		case tr := <-sub.C():
+			states = append(states, tr.ToState)
+		default:
+			return states
+		}
+	}
+}
+
+func Test_subscribeOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     SubscriptionOptions
+		expected []CustomStateEnum
+		dropped  uint64
+	}{
+		{"drop newest", SubscriptionOptions{Buffer: 2, Overflow: OverflowDropNewest}, []CustomStateEnum{CustomStateEnumB, CustomStateEnumA}, 3},
+		{"drop oldest", SubscriptionOptions{Buffer: 2, Overflow: OverflowDropOldest}, []CustomStateEnum{CustomStateEnumA, CustomStateEnumB}, 3},
+		{"coalesce", SubscriptionOptions{Buffer: 2, Overflow: OverflowCoalesce}, []CustomStateEnum{CustomStateEnumB}, 4},
+		{"unbuffered", SubscriptionOptions{Overflow: OverflowDropOldest}, nil, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsm := newPingPongFSM()
+			sub, _ := fsm.Subscribe(tt.opts)
+			defer sub.Close()
+
+			// B, A, B, A, B
+			pingPong(fsm, 5)
+
+			states := received(sub)
+			if len(states) != len(tt.expected) {
+				t.Fatalf("Received %v, expected %v", states, tt.expected)
+			}
+			for i := range states {
+				if states[i] != tt.expected[i] {
+					t.Errorf("Received %v, expected %v", states, tt.expected)
+				}
+			}
+
+			if sub.Dropped() != tt.dropped {
+				t.Errorf("Dropped() returned %d, expected %d", sub.Dropped(), tt.dropped)
+			}
+		})
+	}
+}
+
+func Test_subscribeBlock(t *testing.T) {
+	fsm := newPingPongFSM()
+	sub, _ := fsm.Subscribe(SubscriptionOptions{Buffer: 1, Overflow: OverflowBlock})
+
+	done := make(chan struct{})
+	go func() {
+		pingPong(fsm, 2)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("Transitions completed while the subscriber's buffer was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Reading frees the buffer and unblocks the second transition
+	if tr := <-sub.C(); tr.ToState != CustomStateEnumB {
+		t.Errorf("Received a transition to %v, expected %v", tr.ToState, CustomStateEnumB)
+	}
+	<-done
+
+	// Closing releases a blocked transition and unsubscribes
+	go pingPong(fsm, 2)
+	time.Sleep(20 * time.Millisecond)
+	sub.Close()
+
+	for range sub.C() {
+	}
+
+	pingPong(fsm, 1)
+	if sub.Dropped() != 0 {
+		t.Errorf("Dropped() returned %d, expected 0", sub.Dropped())
+	}
+}
+
+func Test_subscribeDefaults(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	if _, err := fsm.Subscribe(SubscriptionOptions{Overflow: OverflowBlock}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Subscribe() returned %v for OverflowBlock without a buffer, expected ErrInvalidConfig", err)
+	}
+
+	// An idle subscriber with the default options does not stall transitions
+	sub, err := fsm.Subscribe(SubscriptionOptions{Buffer: 1})
+	if err != nil {
+		t.Fatalf("Subscribe() returned an error: %v", err)
+	}
+	defer sub.Close()
+
+	done := make(chan struct{})
+	go func() {
+		pingPong(fsm, 3)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Transitions blocked on an idle subscriber")
+	}
+
+	if tr := <-sub.C(); tr.ToState != CustomStateEnumB || sub.Dropped() != 2 {
+		t.Errorf("Received %v with %d dropped, expected the latest transition to B and 2 dropped", tr.ToState, sub.Dropped())
+	}
+}
+
+func Test_subscribeFilters(t *testing.T) {
+	fsm := NewFSM[string]("created", 10)
+	fsm.AddRule("created", "picked", "canceled")
+	fsm.AddRule("picked", "canceled")
+	fsm.AddRule("canceled", "created")
+
+	opts := SubscriptionOptions{Buffer: 10, Overflow: OverflowDropNewest}
+	all, _ := fsm.Subscribe(opts)
+	canceled, _ := fsm.Subscribe(opts, FilterTo("canceled"))
+	picking, _ := fsm.Subscribe(opts, FilterEdge("created", "picked"))
+	manual, _ := fsm.Subscribe(opts, FilterTo("canceled"), FilterMetadata[string](func(metadata map[string]string) bool {
+		return metadata["source"] == "manual"
+	}))
+
+	fsm.Transition("picked", nil)
+	fsm.Transition("canceled", map[string]string{"source": "manual"})
+	fsm.Transition("created", nil)
+	fsm.Transition("canceled", nil)
+
+	counts := map[string]int{}
+	for name, sub := range map[string]*Subscription[string]{"all": all, "canceled": canceled, "picking": picking, "manual": manual} {
+		sub.Close()
+		for range sub.C() {
+			counts[name]++
+		}
+	}
+
+	expected := map[string]int{"all": 4, "canceled": 2, "picking": 1, "manual": 1}
+	for name, n := range expected {
+		if counts[name] != n {
+			t.Errorf("Subscriber %s received %d transitions, expected %d", name, counts[name], n)
+		}
+	}
+}
+
+func Test_nextTransition(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	done := make(chan Transition[CustomStateEnum])
+	go func() {
+		tr, err := fsm.NextTransition(context.Background())
+		if err != nil {
+			t.Errorf("NextTransition() returned an error: %v", err)
+		}
+		done <- tr
+	}()
+
+	// Keep transitioning until the waiting goroutine has subscribed and received one
+	var tr Transition[CustomStateEnum]
+	for received := false; !received; {
+		pingPong(fsm, 1)
+		select {
+		case tr = <-done:
+			received = true
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	if tr.FromState == tr.ToState {
+		t.Errorf("NextTransition() returned %v", tr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := fsm.NextTransition(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("NextTransition() returned %v, expected context.DeadlineExceeded", err)
+	}
+}
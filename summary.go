@@ -0,0 +1,99 @@
+package statetrooper
+
+import "time"
+
+// EdgeCount is the number of transitions along an edge
+type EdgeCount[T comparable] struct {
+	From  T   `json:"from"`
+	To    T   `json:"to"`
+	Count int `json:"count"`
+}
+
+// HistorySummary aggregates the transitions evicted from the history
+type HistorySummary[T comparable] struct {
+	// Evicted is the number of history entries evicted, counting each compacted entry once
+	Evicted int `json:"evicted"`
+	// Failed is the number of evicted entries that recorded rejected attempts
+	Failed int `json:"failed"`
+	// Edges counts the evicted transitions per edge, expanding compacted loops, ordered by from and to state
+	Edges []EdgeCount[T] `json:"edges,omitempty"`
+	// First and Last are the timestamps of the oldest and newest evicted transitions
+	First *time.Time `json:"first,omitempty"`
+	Last  *time.Time `json:"last,omitempty"`
+}
+
+// historySummary is the running summary of evicted transitions
+type historySummary[T comparable] struct {
+	evicted int
+	failed  int
+	edges   map[edge[T]]int
+	first   *time.Time
+	last    *time.Time
+}
+
+// SetHistorySummary sets whether the transitions evicted by the history limit or TTL are summarized,
+// so that aggregate facts survive after the raw entries are gone. Disabling it discards the summary
+func (fsm *FSM[T]) SetHistorySummary(enabled bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	switch {
+	case !enabled:
+		fsm.summary = nil
+	case fsm.summary == nil:
+		fsm.summary = &historySummary[T]{edges: make(map[edge[T]]int)}
+	}
+}
+
+// HistorySummary returns the summary of the transitions evicted since SetHistorySummary was enabled
+func (fsm *FSM[T]) HistorySummary() HistorySummary[T] {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.summary == nil {
+		return HistorySummary[T]{}
+	}
+
+	return fsm.summary.export()
+}
+
+// export returns a copy of the summary
+func (s *historySummary[T]) export() HistorySummary[T] {
+	return HistorySummary[T]{
+		Evicted: s.evicted,
+		Failed:  s.failed,
+		Edges:   sortedEdgeCounts(s.edges),
+		First:   s.first,
+		Last:    s.last,
+	}
+}
+
+// add adds evicted transitions to the summary
+func (s *historySummary[T]) add(evicted []Transition[T]) {
+	for i := range evicted {
+		tr := &evicted[i]
+		s.evicted++
+
+		if tr.Timestamp != nil && (s.first == nil || tr.Timestamp.Before(*s.first)) {
+			s.first = tr.Timestamp
+		}
+
+		last := tr.Timestamp
+		if tr.Until != nil {
+			last = tr.Until
+		}
+		if last != nil && (s.last == nil || last.After(*s.last)) {
+			s.last = last
+		}
+
+		switch {
+		case tr.Failed:
+			s.failed++
+		case tr.Cycles > 0:
+			s.edges[edge[T]{from: tr.FromState, to: tr.ToState}] += tr.Cycles
+			s.edges[edge[T]{from: tr.ToState, to: tr.FromState}] += tr.Cycles
+		default:
+			s.edges[edge[T]{from: tr.FromState, to: tr.ToState}]++
+		}
+	}
+}
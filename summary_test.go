@@ -0,0 +1,60 @@
+package statetrooper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_historySummary(t *testing.T) {
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 2)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumA)
+	fsm.SetClock(func() time.Time { return now })
+
+	// Nothing is summarized until enabled
+	pingPong(fsm, 3)
+	if summary := fsm.HistorySummary(); summary.Evicted != 0 || summary.Edges != nil {
+		t.Errorf("Summary is %+v before being enabled, expected it empty", summary)
+	}
+
+	fsm.SetHistorySummary(true)
+
+	for i := 0; i < 5; i++ {
+		now = start.Add(time.Duration(i) * time.Minute)
+		pingPong(fsm, 1)
+	}
+
+	// The last two of the eight transitions are retained; the five evicted since enabling end at start+2m
+	summary := fsm.HistorySummary()
+	if summary.Evicted != 5 {
+		t.Errorf("Summary counts %d evicted transitions, expected 5", summary.Evicted)
+	}
+
+	expected := []EdgeCount[CustomStateEnum]{
+		{From: CustomStateEnumA, To: CustomStateEnumB, Count: 2},
+		{From: CustomStateEnumB, To: CustomStateEnumA, Count: 3},
+	}
+	if !reflect.DeepEqual(summary.Edges, expected) {
+		t.Errorf("Summary edges are %+v, expected %+v", summary.Edges, expected)
+	}
+
+	if summary.Last == nil || !summary.Last.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("Summary ends at %v, expected %v", summary.Last, start.Add(2*time.Minute))
+	}
+
+	// Compacted loops are expanded into their edges
+	compacted := &historySummary[CustomStateEnum]{edges: make(map[edge[CustomStateEnum]]int)}
+	compacted.add([]Transition[CustomStateEnum]{{FromState: CustomStateEnumA, ToState: CustomStateEnumB, Cycles: 3}, {Failed: true}})
+	if compacted.evicted != 2 || compacted.failed != 1 || compacted.edges[edge[CustomStateEnum]{from: CustomStateEnumB, to: CustomStateEnumA}] != 3 {
+		t.Errorf("Compacted summary is %+v, expected 2 evicted, 1 failed and 3 loops", compacted)
+	}
+
+	fsm.SetHistorySummary(false)
+	if summary := fsm.HistorySummary(); summary.Evicted != 0 {
+		t.Errorf("Summary is %+v after being disabled, expected it empty", summary)
+	}
+}
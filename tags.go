@@ -0,0 +1,109 @@
+package statetrooper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ruleTag is the struct tag key used by RulesFromTags
+const ruleTag = "fsm"
+
+// RulesFromTags builds a ruleset from the `fsm` struct tags of def, a struct or pointer to a struct
+// whose fields hold the states, e.g.
+//
+//	var OrderStates = struct {
+//		Created OrderState `fsm:"-> picked,canceled"`
+//		Picked  OrderState `fsm:"-> packed,canceled"`
+//		Packed  OrderState `fsm:"-> shipped"`
+//		...
+//	}{Created: Created, Picked: Picked, Packed: Packed, ...}
+//
+// Targets are matched against the field names, case-insensitively, and then against the string form
+// of the field values
+// The returned ruleset can be passed to AddRule state by state
+func RulesFromTags[T comparable](def any) (map[T][]T, error) {
+	v := reflect.ValueOf(def)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: expected a struct, got %T", ErrInvalidRuleTag, def)
+	}
+
+	stateType := reflect.TypeOf((*T)(nil)).Elem()
+	names := make(map[string]T)
+	tags := make(map[T]string)
+	var order []T
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag, tagged := field.Tag.Lookup(ruleTag)
+
+		if !field.IsExported() || field.Type != stateType {
+			if tagged {
+				return nil, fmt.Errorf("%w: field %s must be an exported %v", ErrInvalidRuleTag, field.Name, stateType)
+			}
+			continue
+		}
+
+		state := v.Field(i).Interface().(T)
+		names[strings.ToLower(field.Name)] = state
+		if _, ok := names[strings.ToLower(toString(state))]; !ok {
+			names[strings.ToLower(toString(state))] = state
+		}
+
+		if tagged {
+			tags[state] = tag
+			order = append(order, state)
+		}
+	}
+
+	rules := make(map[T][]T, len(order))
+	for _, state := range order {
+		targets, err := parseRuleTag(tags[state], names)
+		if err != nil {
+			return nil, fmt.Errorf("%w: state %v: %v", ErrInvalidRuleTag, state, err)
+		}
+
+		if len(targets) > 0 {
+			rules[state] = targets
+		}
+	}
+
+	return rules, nil
+}
+
+// parseRuleTag parses a tag of the form "-> a,b,c" into the states named by names
+// An empty tag or "-" declares a state without outgoing rules
+func parseRuleTag[T comparable](tag string, names map[string]T) ([]T, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || tag == "-" {
+		return nil, nil
+	}
+
+	list, ok := strings.CutPrefix(tag, "->")
+	if !ok {
+		return nil, fmt.Errorf("tag %q must start with ->", tag)
+	}
+
+	var targets []T
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("tag %q has an empty target", tag)
+		}
+
+		target, ok := names[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown target %q", name)
+		}
+
+		if !contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,89 @@
+package statetrooper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_rulesFromTags(t *testing.T) {
+	def := struct {
+		A     CustomStateEnum `fsm:"-> b, C"`
+		B     CustomStateEnum `fsm:"-> c"`
+		C     CustomStateEnum `fsm:"-> D,a"`
+		D     CustomStateEnum `fsm:"-"`
+		Label string
+	}{A: CustomStateEnumA, B: CustomStateEnumB, C: CustomStateEnumC, D: CustomStateEnumD}
+
+	rules, err := RulesFromTags[CustomStateEnum](&def)
+	if err != nil {
+		t.Fatalf("RulesFromTags() returned an error: %v", err)
+	}
+
+	expected := map[CustomStateEnum][]CustomStateEnum{
+		CustomStateEnumA: {CustomStateEnumB, CustomStateEnumC},
+		CustomStateEnumB: {CustomStateEnumC},
+		CustomStateEnumC: {CustomStateEnumD, CustomStateEnumA},
+	}
+
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("RulesFromTags() returned %v, expected %v", rules, expected)
+	}
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	for from, to := range rules {
+		if err := fsm.AddRule(from, to...); err != nil {
+			t.Fatalf("AddRule() returned an error: %v", err)
+		}
+	}
+
+	if !fsm.CanTransition(CustomStateEnumC) {
+		t.Errorf("Expected transition from A to C to be allowed")
+	}
+}
+
+func Test_rulesFromTagsByValue(t *testing.T) {
+	def := struct {
+		Start string `fsm:"-> done"`
+		End   string
+	}{Start: "begin", End: "done"}
+
+	rules, err := RulesFromTags[string](def)
+	if err != nil {
+		t.Fatalf("RulesFromTags() returned an error: %v", err)
+	}
+
+	if !reflect.DeepEqual(rules, map[string][]string{"begin": {"done"}}) {
+		t.Errorf("RulesFromTags() returned %v", rules)
+	}
+}
+
+func Test_rulesFromTagsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		def  any
+	}{
+		{"not a struct", 42},
+		{"missing arrow", struct {
+			A CustomStateEnum `fsm:"b"`
+			B CustomStateEnum
+		}{CustomStateEnumA, CustomStateEnumB}},
+		{"unknown target", struct {
+			A CustomStateEnum `fsm:"-> z"`
+		}{CustomStateEnumA}},
+		{"empty target", struct {
+			A CustomStateEnum `fsm:"-> a,"`
+		}{CustomStateEnumA}},
+		{"wrong field type", struct {
+			A int `fsm:"-> a"`
+		}{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RulesFromTags[CustomStateEnum](tt.def); !errors.Is(err, ErrInvalidRuleTag) {
+				t.Errorf("RulesFromTags() returned %v, expected ErrInvalidRuleTag", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package statetrooper
+
+// Template captures the configuration of an FSM so that per-entity FSMs can be stamped out without
+// repeating rule, guard, hook and action registration for every entity
+type Template[T comparable] struct {
+	prototype *FSM[T]
+}
+
+// NewTemplate creates a Template from the configuration of prototype
+// The prototype's current state, history and counters are not captured, nor is its async hook pool
+// Later changes to prototype do not affect the template
+func NewTemplate[T comparable](prototype *FSM[T]) *Template[T] {
+	prototype.mu.Lock()
+	defer prototype.mu.Unlock()
+
+	// The template shares the prototype's ruleset, so the prototype copies it before changing it
+	prototype.sharedRules = true
+
+	return &Template[T]{prototype: prototype.cloneConfig()}
+}
+
+// New creates an FSM in initialState with the template's configuration
+// Each FSM gets its own copy of the configuration, so it can be changed without affecting other FSMs
+// The ruleset is not copied but shared with the template until the FSM changes it
+func (tmpl *Template[T]) New(initialState T) *FSM[T] {
+	fsm := tmpl.prototype.cloneConfig()
+	fsm.currentState = initialState
+	fsm.enteredAt = fsm.timeNow()
+	fsm.checkTerminal()
+
+	return fsm
+}
+
+// cloneConfig returns a new FSM with a copy of fsm's configuration but none of its runtime state
+// The clone shares fsm's ruleset, which fsm must copy before changing it unless it is already marked shared
+// The caller must hold fsm's lock unless fsm is not shared
+func (fsm *FSM[T]) cloneConfig() *FSM[T] {
+	clone := &FSM[T]{
+		ruleset:                fsm.ruleset,
+		sharedRules:            true,
+		states:                 cloneMap(fsm.states),
+		guards:                 cloneMapOfSlices(fsm.guards),
+		maxHistory:             fsm.maxHistory,
+		selfLoops:              fsm.selfLoops,
+		now:                    fsm.now,
+		cooldown:               fsm.cooldown,
+		edgeCooldowns:          cloneMap(fsm.edgeCooldowns),
+		actorLimits:            cloneMap(fsm.actorLimits),
+		allowedRoles:           cloneMapOfSlices(fsm.allowedRoles),
+		authorizer:             fsm.authorizer,
+		outboxEnabled:          fsm.outboxEnabled,
+		idGenerator:            fsm.idGenerator,
+		timestamper:            fsm.timestamper,
+		maxTransitions:         fsm.maxTransitions,
+		edgeMaxTransitions:     cloneMap(fsm.edgeMaxTransitions),
+		debounceWindow:         fsm.debounceWindow,
+		recordDuplicates:       fsm.recordDuplicates,
+		deadLetterSink:         fsm.deadLetterSink,
+		hooks:                  cloneMap(fsm.hooks),
+		hookErrorHandler:       fsm.hookErrorHandler,
+		hookTimeout:            fsm.hookTimeout,
+		entryActions:           cloneMap(fsm.entryActions),
+		exitActions:            cloneMap(fsm.exitActions),
+		composites:             cloneMap(fsm.composites),
+		parents:                cloneMap(fsm.parents),
+		terminals:              cloneMap(fsm.terminals),
+		aliases:                cloneMap(fsm.aliases),
+		version:                fsm.version,
+		migrations:             cloneMap(fsm.migrations),
+		dwellThresholds:        cloneMap(fsm.dwellThresholds),
+		minDwells:              cloneMap(fsm.minDwells),
+		autoTransitions:        cloneMapOfSlices(fsm.autoTransitions),
+		events:                 cloneMap(fsm.events),
+		groups:                 cloneMapOfSlices(fsm.groups),
+		groupRules:             cloneMapOfSlices(fsm.groupRules),
+		sealed:                 fsm.sealed,
+		recordTouches:          fsm.recordTouches,
+		idleThresholds:         cloneMap(fsm.idleThresholds),
+		stringTemplate:         fsm.stringTemplate,
+		marshalOptions:         fsm.marshalOptions,
+		compactHistory:         fsm.compactHistory,
+		redactionRules:         fsm.redactionRules,
+		recordFailures:         fsm.recordFailures,
+		sameStatePolicy:        fsm.sameStatePolicy,
+		stateSameStatePolicies: cloneMap(fsm.stateSameStatePolicies),
+		ruleFlags:              cloneMap(fsm.ruleFlags),
+		contextExtractor:       fsm.contextExtractor,
+		name:                   fsm.name,
+		historyTTL:             fsm.historyTTL,
+		evictHandler:           fsm.evictHandler,
+	}
+
+	// The summary itself is runtime state; only whether it is enabled is configuration
+	if fsm.summary != nil {
+		clone.summary = &historySummary[T]{edges: make(map[edge[T]]int)}
+	}
+
+	if fsm.transitionPool.Load() != nil {
+		clone.SetPooling(true)
+	}
+
+	// Likewise latency tracking keeps its threshold but starts with empty stats
+	if fsm.latency != nil {
+		clone.latency = &latencyTracking[T]{threshold: fsm.latency.threshold, onSlow: fsm.latency.onSlow}
+	}
+
+	return clone
+}
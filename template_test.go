@@ -0,0 +1,75 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_template(t *testing.T) {
+	prototype := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	prototype.AddRule(CustomStateEnumA, CustomStateEnumB)
+	prototype.AddRule(CustomStateEnumB, CustomStateEnumC)
+
+	errBlocked := errors.New("blocked")
+	prototype.AddGuard(CustomStateEnumB, CustomStateEnumC, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		if tr.Metadata["ok"] != "yes" {
+			return errBlocked
+		}
+		return nil
+	})
+
+	hookCalls := 0
+	prototype.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		hookCalls++
+		return nil
+	})
+
+	tmpl := NewTemplate(prototype)
+
+	// Changes to the prototype after the template is created are not captured
+	prototype.AddRule(CustomStateEnumC, CustomStateEnumD)
+
+	first := tmpl.New(CustomStateEnumA)
+	second := tmpl.New(CustomStateEnumB)
+
+	if first.CurrentState() != CustomStateEnumA || second.CurrentState() != CustomStateEnumB {
+		t.Fatalf("Template FSMs started in %v and %v, expected %v and %v",
+			first.CurrentState(), second.CurrentState(), CustomStateEnumA, CustomStateEnumB)
+	}
+
+	if _, err := first.Transition(CustomStateEnumB, nil); err != nil {
+		t.Errorf("Transition() returned an error: %v", err)
+	}
+
+	if _, err := second.Transition(CustomStateEnumC, nil); !errors.Is(err, errBlocked) {
+		t.Errorf("Transition() returned %v, expected the guard error %v", err, errBlocked)
+	}
+
+	if _, err := second.Transition(CustomStateEnumC, map[string]string{"ok": "yes"}); err != nil {
+		t.Errorf("Transition() returned an error: %v", err)
+	}
+
+	if second.CanTransition(CustomStateEnumD) {
+		t.Errorf("Rule added to the prototype after NewTemplate leaked into a template FSM")
+	}
+
+	if hookCalls != 2 {
+		t.Errorf("Template hook ran %d times, expected 2", hookCalls)
+	}
+
+	// Each FSM owns its configuration and history
+	first.AddRule(CustomStateEnumB, CustomStateEnumD)
+	if second.CanTransition(CustomStateEnumD) {
+		t.Errorf("Rule added to one template FSM leaked into another")
+	}
+
+	if len(first.transitions) != 1 || len(second.transitions) != 1 {
+		t.Errorf("Template FSMs have %d and %d transitions, expected 1 each",
+			len(first.transitions), len(second.transitions))
+	}
+
+	if len(prototype.transitions) != 0 {
+		t.Errorf("Prototype has %d transitions, expected 0", len(prototype.transitions))
+	}
+}
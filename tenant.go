@@ -0,0 +1,84 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// EntityExport is the JSON form of an entity's FSM written by Manager.Export
+type EntityExport[K comparable, T comparable] struct {
+	ID  K       `json:"id"`
+	FSM *FSM[T] `json:"fsm"`
+}
+
+// Tenant returns the namespace of a tenant, creating it on first use
+// A tenant's namespace is a Manager of its own, so its entity IDs, counts, triggers and exports are isolated
+// from the parent and from other tenants, and its quota applies to it alone
+func (m *Manager[K, T]) Tenant(name string) *Manager[K, T] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tenant, ok := m.tenants[name]; ok {
+		return tenant
+	}
+
+	if m.tenants == nil {
+		m.tenants = make(map[string]*Manager[K, T])
+	}
+
+	tenant := NewManager[K, T]()
+	tenant.closed = m.closed
+	m.tenants[name] = tenant
+
+	return tenant
+}
+
+// Tenants returns the names of the tenants, sorted
+func (m *Manager[K, T]) Tenants() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.tenants))
+	for name := range m.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetQuota limits the number of entities the Manager holds, for example per tenant
+// Add fails with ErrQuotaExceeded once the quota is reached. Zero means no limit
+// Lowering the quota below the current number of entities does not remove any
+func (m *Manager[K, T]) SetQuota(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.quota = n
+}
+
+// Export writes the FSMs of all entities to w as NDJSON, one EntityExport per line, ordered by the
+// string form of their IDs. Each FSM is serialized with MarshalJSON
+func (m *Manager[K, T]) Export(w io.Writer) error {
+	m.mu.RLock()
+	entities := make([]EntityExport[K, T], 0, len(m.fsms))
+	for id, fsm := range m.fsms {
+		entities = append(entities, EntityExport[K, T]{ID: id, FSM: fsm})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(entities, func(i, j int) bool {
+		return fmt.Sprint(entities[i].ID) < fmt.Sprint(entities[j].ID)
+	})
+
+	enc := json.NewEncoder(w)
+	for i := range entities {
+		if err := enc.Encode(&entities[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package statetrooper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_tenants(t *testing.T) {
+	m := NewManager[string, CustomStateEnum]()
+
+	acme := m.Tenant("acme")
+	globex := m.Tenant("globex")
+	if m.Tenant("acme") != acme {
+		t.Errorf("Tenant() returned a new namespace for an existing tenant")
+	}
+
+	if names := m.Tenants(); !reflect.DeepEqual(names, []string{"acme", "globex"}) {
+		t.Errorf("Tenants() returned %v, expected [acme globex]", names)
+	}
+
+	// IDs are scoped to the tenant
+	if err := acme.Add("order-1", newPingPongFSM()); err != nil {
+		t.Fatalf("Add() returned an error: %v", err)
+	}
+	if err := globex.Add("order-1", newPingPongFSM()); err != nil {
+		t.Fatalf("Add() returned an error for the same ID in another tenant: %v", err)
+	}
+	if _, ok := m.Get("order-1"); ok {
+		t.Errorf("Get() on the parent found a tenant's entity")
+	}
+
+	// Quotas apply per tenant
+	acme.SetQuota(2)
+	if err := acme.Add("order-2", newPingPongFSM()); err != nil {
+		t.Errorf("Add() returned an error within the quota: %v", err)
+	}
+	if err := acme.Add("order-3", newPingPongFSM()); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Add() returned %v, expected ErrQuotaExceeded", err)
+	}
+	if err := globex.Add("order-2", newPingPongFSM()); err != nil {
+		t.Errorf("Add() returned an error in a tenant without a quota: %v", err)
+	}
+
+	// Counts are isolated
+	fsm, _ := acme.Get("order-1")
+	fsm.Transition(CustomStateEnumB, nil)
+
+	if counts := globex.Counts(); counts[CustomStateEnumB] != 0 || counts[CustomStateEnumA] != 2 {
+		t.Errorf("Counts() of globex returned %v, expected 2 entities in A", counts)
+	}
+
+	// Exports only include the tenant's entities
+	var buf bytes.Buffer
+	if err := acme.Export(&buf); err != nil {
+		t.Fatalf("Export() returned an error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Export() wrote %d entities, expected 2", len(lines))
+	}
+
+	var entity struct {
+		ID  string `json:"id"`
+		FSM struct {
+			CurrentState CustomStateEnum `json:"current_state"`
+		} `json:"fsm"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &entity); err != nil || entity.ID != "order-1" || entity.FSM.CurrentState != CustomStateEnumB {
+		t.Errorf("Export() wrote %s, expected order-1 in B", lines[0])
+	}
+}
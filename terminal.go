@@ -0,0 +1,57 @@
+package statetrooper
+
+// SetTerminalState declares state as terminal with the given outcome
+// Entering a terminal state closes the Done channel and makes Err return outcome,
+// so a nil outcome marks a successful completion and an error marks a failed one
+func (fsm *FSM[T]) SetTerminalState(state T, outcome error) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.terminals == nil {
+		fsm.terminals = make(map[T]error)
+	}
+
+	fsm.terminals[state] = outcome
+	fsm.checkTerminal()
+}
+
+// Done returns a channel that is closed when the FSM enters a terminal state
+// Once closed, it stays closed even if the FSM later leaves the terminal state
+func (fsm *FSM[T]) Done() <-chan struct{} {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.done == nil {
+		fsm.done = make(chan struct{})
+	}
+
+	return fsm.done
+}
+
+// Err returns nil until Done is closed. Afterwards it returns the outcome of the terminal state that was reached
+func (fsm *FSM[T]) Err() error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.outcome
+}
+
+// checkTerminal closes the Done channel if the current state is terminal
+func (fsm *FSM[T]) checkTerminal() {
+	if fsm.finished {
+		return
+	}
+
+	outcome, ok := fsm.terminals[fsm.currentState]
+	if !ok {
+		return
+	}
+
+	if fsm.done == nil {
+		fsm.done = make(chan struct{})
+	}
+
+	fsm.finished = true
+	fsm.outcome = outcome
+	close(fsm.done)
+}
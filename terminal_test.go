@@ -0,0 +1,57 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_doneChannel(t *testing.T) {
+	errCanceled := errors.New("order canceled")
+
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC, CustomStateEnumD)
+	fsm.SetTerminalState(CustomStateEnumC, nil)
+	fsm.SetTerminalState(CustomStateEnumD, errCanceled)
+
+	done := fsm.Done()
+
+	fsm.Transition(CustomStateEnumB, nil)
+
+	select {
+	case <-done:
+		t.Fatalf("Done closed before a terminal state was reached")
+	default:
+	}
+
+	if fsm.Err() != nil {
+		t.Errorf("Err() = %v before a terminal state was reached, expected nil", fsm.Err())
+	}
+
+	fsm.Transition(CustomStateEnumD, nil)
+
+	select {
+	case <-done:
+	default:
+		t.Fatalf("Done not closed after a terminal state was reached")
+	}
+
+	if fsm.Err() != errCanceled {
+		t.Errorf("Err() = %v, expected %v", fsm.Err(), errCanceled)
+	}
+}
+
+func Test_doneInitialTerminalState(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumC, 10)
+	fsm.SetTerminalState(CustomStateEnumC, nil)
+
+	select {
+	case <-fsm.Done():
+	default:
+		t.Errorf("Done not closed for an FSM starting in a terminal state")
+	}
+
+	if fsm.Err() != nil {
+		t.Errorf("Err() = %v for a successful terminal state, expected nil", fsm.Err())
+	}
+}
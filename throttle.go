@@ -0,0 +1,88 @@
+package statetrooper
+
+import "time"
+
+// rateLimit is a maximum number of transitions within a sliding window
+type rateLimit struct {
+	n      int
+	window time.Duration
+}
+
+// SetActorRateLimit limits the actor with the given ID to n transitions of the FSM within any window,
+// so a runaway automation can be contained without affecting other actors
+// Transitions attempted without an actor are limited under the empty ID. A non-positive n or window removes the limit
+func (fsm *FSM[T]) SetActorRateLimit(actor string, n int, window time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if n <= 0 || window <= 0 {
+		delete(fsm.actorLimits, actor)
+		delete(fsm.actorTimes, actor)
+		return
+	}
+
+	if fsm.actorLimits == nil {
+		fsm.actorLimits = make(map[string]rateLimit)
+	}
+
+	fsm.actorLimits[actor] = rateLimit{n: n, window: window}
+}
+
+// checkThrottle returns a ThrottleError if the actor has used up its rate limit at now
+func (fsm *FSM[T]) checkThrottle(actor string, fromState *T, toState *T, now time.Time) error {
+	limit, ok := fsm.actorLimits[actor]
+	if !ok {
+		return nil
+	}
+
+	times := fsm.recentActorTimes(actor, limit, now)
+	if len(times) < limit.n {
+		return nil
+	}
+
+	return ThrottleError[T]{
+		FromState:  *fromState,
+		ToState:    *toState,
+		Actor:      actor,
+		Limit:      limit.n,
+		Window:     limit.window,
+		RetryAfter: times[len(times)-limit.n].Add(limit.window).Sub(now),
+	}
+}
+
+// markThrottle records the time of the given transition against its actor's rate limit
+func (fsm *FSM[T]) markThrottle(tr *Transition[T]) {
+	limit, ok := fsm.actorLimits[tr.Actor]
+	if !ok {
+		return
+	}
+
+	if fsm.actorTimes == nil {
+		fsm.actorTimes = make(map[string][]time.Time)
+	}
+
+	fsm.actorTimes[tr.Actor] = append(fsm.recentActorTimes(tr.Actor, limit, *tr.Timestamp), *tr.Timestamp)
+}
+
+// recentActorTimes drops the actor's transition times that fell out of the window at now and returns the rest
+func (fsm *FSM[T]) recentActorTimes(actor string, limit rateLimit, now time.Time) []time.Time {
+	times := fsm.actorTimes[actor]
+
+	cutoff := now.Add(-limit.window)
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+
+	// Only the latest n times can affect the limit
+	if len(times)-i > limit.n {
+		i = len(times) - limit.n
+	}
+
+	times = times[i:]
+	if fsm.actorTimes != nil {
+		fsm.actorTimes[actor] = times
+	}
+
+	return times
+}
@@ -0,0 +1,58 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_actorRateLimit(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	fsm := newPingPongFSM()
+	fsm.SetClock(func() time.Time { return now })
+	fsm.SetActorRateLimit("bot", 2, time.Second)
+
+	bot := WithActor(context.Background(), Actor{ID: "bot"})
+	human := WithActor(context.Background(), Actor{ID: "alice"})
+
+	if _, err := fsm.TransitionCtx(bot, CustomStateEnumB, nil); err != nil {
+		t.Fatalf("TransitionCtx() returned an error: %v", err)
+	}
+
+	now = now.Add(400 * time.Millisecond)
+	if _, err := fsm.TransitionCtx(bot, CustomStateEnumA, nil); err != nil {
+		t.Fatalf("TransitionCtx() returned an error: %v", err)
+	}
+
+	// The third transition within a second is throttled until the first one leaves the window
+	var throttleErr ThrottleError[CustomStateEnum]
+	_, err := fsm.TransitionCtx(bot, CustomStateEnumB, nil)
+	if !errors.As(err, &throttleErr) || !errors.Is(err, ErrThrottled) {
+		t.Fatalf("TransitionCtx() returned %v, expected a ThrottleError", err)
+	}
+
+	if throttleErr.Actor != "bot" || throttleErr.Limit != 2 || throttleErr.RetryAfter != 600*time.Millisecond {
+		t.Errorf("ThrottleError is %+v, expected bot limited to 2 with 600ms to wait", throttleErr)
+	}
+
+	// Other actors are not affected
+	if _, err := fsm.TransitionCtx(human, CustomStateEnumB, nil); err != nil {
+		t.Errorf("TransitionCtx() returned an error for another actor: %v", err)
+	}
+
+	if last := fsm.Transitions()[len(fsm.Transitions())-1]; last.Actor != "alice" {
+		t.Errorf("Transition recorded actor %q, expected alice", last.Actor)
+	}
+
+	now = now.Add(600 * time.Millisecond)
+	if _, err := fsm.TransitionCtx(bot, CustomStateEnumA, nil); err != nil {
+		t.Errorf("TransitionCtx() returned an error after the window passed: %v", err)
+	}
+
+	fsm.SetActorRateLimit("bot", 0, 0)
+	if _, err := fsm.TransitionCtx(bot, CustomStateEnumB, nil); err != nil {
+		t.Errorf("TransitionCtx() returned an error after removing the limit: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package statetrooper
+
+import "time"
+
+// Touch marks the FSM as active without changing its state, for example on a heartbeat from
+// the process driving a long-running state. The time is reported by LastActivity and checked
+// against idle thresholds by HealthCheck. Touches are recorded in the history with Touch set
+// only if enabled with SetRecordTouches. It has no effect once the FSM is closed
+func (fsm *FSM[T]) Touch(metadata map[string]string) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.closed {
+		return
+	}
+
+	fsm.touch(metadata, fsm.timeNow(), fsm.recordTouches)
+}
+
+// SetRecordTouches sets whether Touch records an entry in the history
+func (fsm *FSM[T]) SetRecordTouches(record bool) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.recordTouches = record
+}
+
+// LastActivity returns the time of the last transition into the current state or the last touch since
+func (fsm *FSM[T]) LastActivity() time.Time {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.lastActivity()
+}
+
+// SetIdleThreshold sets how long the FSM may go without activity in state before HealthCheck reports it as idle
+// A zero threshold removes the check
+func (fsm *FSM[T]) SetIdleThreshold(state T, threshold time.Duration) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if threshold <= 0 {
+		delete(fsm.idleThresholds, state)
+		return
+	}
+
+	if fsm.idleThresholds == nil {
+		fsm.idleThresholds = make(map[T]time.Duration)
+	}
+
+	fsm.idleThresholds[state] = threshold
+}
+
+// touch marks the FSM as active at tn, recording a touch entry if record is true. The caller must hold the lock
+func (fsm *FSM[T]) touch(metadata map[string]string, tn time.Time, record bool) {
+	fsm.lastTouch = tn
+
+	if record {
+		fsm.recordTransition(&Transition[T]{
+			FromState: fsm.currentState,
+			ToState:   fsm.currentState,
+			Timestamp: &tn,
+			Metadata:  metadata,
+			Touch:     true,
+		})
+	}
+}
+
+// lastActivity returns the later of the time the current state was entered and the last touch
+func (fsm *FSM[T]) lastActivity() time.Time {
+	if fsm.lastTouch.After(fsm.enteredAt) {
+		return fsm.lastTouch
+	}
+
+	return fsm.enteredAt
+}
@@ -0,0 +1,51 @@
+package statetrooper
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_touch(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	fsm := newPingPongFSM()
+	fsm.SetClock(func() time.Time { return now })
+	fsm.Transition(CustomStateEnumB, nil)
+	fsm.SetIdleThreshold(CustomStateEnumB, 5*time.Minute)
+
+	now = start.Add(4 * time.Minute)
+	fsm.Touch(map[string]string{"worker": "1"})
+
+	if last := fsm.LastActivity(); !last.Equal(now) {
+		t.Errorf("LastActivity() returned %v, expected %v", last, now)
+	}
+
+	if len(fsm.Transitions()) != 1 {
+		t.Errorf("Touch() was recorded without SetRecordTouches")
+	}
+
+	// The touch keeps the FSM from being reported idle, but the dwell time still counts from entering B
+	now = start.Add(8 * time.Minute)
+	report := fsm.HealthCheck()
+	if !report.Healthy || report.Idle != 4*time.Minute || report.Dwell != 8*time.Minute {
+		t.Errorf("HealthCheck() returned %+v, expected a healthy report idle for 4m and dwelling for 8m", report)
+	}
+
+	now = start.Add(10 * time.Minute)
+	if report := fsm.HealthCheck(); report.Healthy || report.Problems[0].Kind != HealthIdleExceeded {
+		t.Errorf("HealthCheck() returned %+v, expected %s", report, HealthIdleExceeded)
+	}
+
+	fsm.SetRecordTouches(true)
+	fsm.Touch(map[string]string{"worker": "1"})
+
+	history := fsm.Transitions()
+	if len(history) != 2 || !history[1].Touch || history[1].ToState != CustomStateEnumB || history[1].Metadata["worker"] != "1" {
+		t.Errorf("SetRecordTouches(true) recorded %v", history)
+	}
+
+	if fsm.CurrentState() != CustomStateEnumB {
+		t.Errorf("Touch() changed the state to %v", fsm.CurrentState())
+	}
+}
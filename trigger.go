@@ -0,0 +1,103 @@
+package statetrooper
+
+import (
+	"context"
+	"fmt"
+)
+
+// trigger fires a transition of one entity when another entity enters a state
+type trigger[K comparable, T comparable] struct {
+	source      K
+	state       T
+	target      K
+	targetState T
+}
+
+// AddTrigger makes the FSM of targetID transition to targetState whenever the FSM of sourceID enters state,
+// e.g. advancing an order when its shipment is delivered
+// The transition runs as a post-commit hook of the source FSM, so its errors are reported to the source's
+// hook error handler. The metadata of triggered transitions records the source entity and state
+// ErrTriggerLoop is returned if the trigger could, directly or through other triggers, end up firing itself
+func (m *Manager[K, T]) AddTrigger(sourceID K, state T, targetID K, targetState T) (remove func(), err error) {
+	source, ok := m.Get(sourceID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrEntityNotFound, sourceID)
+	}
+
+	t := &trigger[K, T]{source: sourceID, state: state, target: targetID, targetState: targetState}
+
+	m.mu.Lock()
+	if m.reaches(targetID, targetState, sourceID, state) {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("%w: %v entering %v fires itself", ErrTriggerLoop, sourceID, state)
+	}
+	m.triggers = append(m.triggers, t)
+	m.mu.Unlock()
+
+	metadata := map[string]string{
+		"trigger_source": fmt.Sprint(sourceID),
+		"trigger_state":  toString(state),
+	}
+
+	removeHook := source.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[T]) error {
+		if tr.ToState != state {
+			return nil
+		}
+
+		target, ok := m.Get(targetID)
+		if !ok {
+			return fmt.Errorf("%w: %v", ErrEntityNotFound, targetID)
+		}
+
+		_, err := target.TransitionCtx(ctx, targetState, metadata)
+		return err
+	})
+
+	return func() {
+		removeHook()
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for i, other := range m.triggers {
+			if other == t {
+				m.triggers = append(m.triggers[:i:i], m.triggers[i+1:]...)
+				break
+			}
+		}
+	}, nil
+}
+
+// reaches reports whether an entity entering a state can, through the registered triggers,
+// lead to goalID entering goalState. The caller must hold m.mu
+func (m *Manager[K, T]) reaches(id K, state T, goalID K, goalState T) bool {
+	type node struct {
+		id    K
+		state T
+	}
+
+	visited := make(map[node]bool)
+	stack := []node{{id: id, state: state}}
+
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if n.id == goalID && n.state == goalState {
+			return true
+		}
+
+		if visited[n] {
+			continue
+		}
+		visited[n] = true
+
+		for _, t := range m.triggers {
+			if t.source == n.id && t.state == n.state {
+				stack = append(stack, node{id: t.target, state: t.targetState})
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package statetrooper
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_managerTriggers(t *testing.T) {
+	manager := NewManager[string, string]()
+
+	order := NewFSM[string]("placed", 10)
+	order.AddRule("placed", "fulfilled")
+	order.AddRule("fulfilled", "closed")
+
+	shipment := NewFSM[string]("pending", 10)
+	shipment.AddRule("pending", "delivered")
+
+	invoice := NewFSM[string]("open", 10)
+	invoice.AddRule("open", "settled")
+
+	manager.Add("order-1", order)
+	manager.Add("shipment-1", shipment)
+	manager.Add("invoice-1", invoice)
+
+	if _, err := manager.AddTrigger("shipment-1", "delivered", "order-1", "fulfilled"); err != nil {
+		t.Fatalf("AddTrigger() returned an error: %v", err)
+	}
+
+	remove, err := manager.AddTrigger("order-1", "fulfilled", "invoice-1", "settled")
+	if err != nil {
+		t.Fatalf("AddTrigger() returned an error: %v", err)
+	}
+
+	// invoice settled -> shipment delivered would close the loop
+	if _, err := manager.AddTrigger("invoice-1", "settled", "shipment-1", "delivered"); !errors.Is(err, ErrTriggerLoop) {
+		t.Errorf("AddTrigger() returned %v, expected ErrTriggerLoop", err)
+	}
+
+	if _, err := manager.AddTrigger("missing", "x", "order-1", "closed"); !errors.Is(err, ErrEntityNotFound) {
+		t.Errorf("AddTrigger() returned %v, expected ErrEntityNotFound", err)
+	}
+
+	shipment.Transition("delivered", nil)
+
+	if order.CurrentState() != "fulfilled" || invoice.CurrentState() != "settled" {
+		t.Errorf("Triggers left order in %v and invoice in %v, expected fulfilled and settled",
+			order.CurrentState(), invoice.CurrentState())
+	}
+
+	if tr := order.Transitions()[0]; tr.Metadata["trigger_source"] != "shipment-1" || tr.Metadata["trigger_state"] != "delivered" {
+		t.Errorf("Triggered transition has metadata %v", tr.Metadata)
+	}
+
+	// Once removed, the trigger no longer counts towards loops
+	remove()
+	if _, err := manager.AddTrigger("invoice-1", "settled", "shipment-1", "delivered"); err != nil {
+		t.Errorf("AddTrigger() returned %v after removing the conflicting trigger", err)
+	}
+}
@@ -0,0 +1,215 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// TransactionMetadataKey is the metadata key correlating the transitions of a transaction
+const TransactionMetadataKey = "transaction"
+
+// lockOrders issues the order in which FSMs are locked when several are locked at once, so that
+// transactions and batches can never wait on each other's locks
+var lockOrders atomic.Uint64
+
+// order returns the position of the FSM in the global lock order, assigning one on first use
+func (fsm *FSM[T]) order() uint64 {
+	if order := fsm.lockOrder.Load(); order != 0 {
+		return order
+	}
+
+	fsm.lockOrder.CompareAndSwap(0, lockOrders.Add(1))
+
+	return fsm.lockOrder.Load()
+}
+
+// Step is the transition of one FSM within a transaction, created with TransitionStep
+type Step interface {
+	name() string
+	order() uint64
+	lock()
+	unlock()
+	// prepare checks the transition, which is committed by commit and finished by finish after unlocking
+	prepare(ctx context.Context, transaction string) error
+	commit(ctx context.Context) error
+	finish(ctx context.Context) (string, error)
+	abort(err error) string
+}
+
+// StepResult is the outcome of a step of a transaction
+type StepResult struct {
+	Name string
+	// State is the display name of the FSM's state after the transaction
+	State string
+	Err   error
+}
+
+// TxReport describes the outcome of a transaction
+type TxReport struct {
+	Committed bool
+	// Failed names the step whose transition was rejected, empty if none was
+	Failed string
+	Steps  []StepResult
+}
+
+// TransitionStep returns a step transitioning fsm to targetState, identified by name in the report
+// A step keeps the outcome of its transition, so it can only be used in one transaction
+func TransitionStep[T comparable](name string, fsm *FSM[T], targetState T, metadata map[string]string) Step {
+	return &transitionStep[T]{stepName: name, fsm: fsm, target: targetState, metadata: metadata}
+}
+
+// Transact applies the transitions of steps, which may belong to FSMs of different state types, so that
+// either all of them are committed or none is. Rules, cooldowns, budgets, guards and pre-commit hooks are
+// checked for every step while all of the FSMs are locked, and nothing is committed if any of them fails
+// The other steps then fail with ErrBatchAborted. Entry and exit actions run once all steps are committed
+// and may still fail individually. If id is not empty it is added to the metadata of every transition under
+// TransactionMetadataKey. The returned error joins the errors of the failed steps
+func Transact(ctx context.Context, id string, steps ...Step) (TxReport, error) {
+	report := TxReport{Steps: make([]StepResult, len(steps))}
+
+	seen := make(map[uint64]bool, len(steps))
+	for i, step := range steps {
+		report.Steps[i].Name = step.name()
+		if seen[step.order()] {
+			return report, fmt.Errorf("%w: %s", ErrDuplicateStep, step.name())
+		}
+		seen[step.order()] = true
+	}
+
+	// Locking in the global order keeps concurrent transactions and batches from deadlocking
+	locking := append([]Step(nil), steps...)
+	sort.Slice(locking, func(i, j int) bool {
+		return locking[i].order() < locking[j].order()
+	})
+	for _, step := range locking {
+		step.lock()
+	}
+
+	failed := -1
+	for i, step := range steps {
+		if err := step.prepare(ctx, id); err != nil {
+			report.Steps[i].Err = err
+			failed = i
+			break
+		}
+	}
+
+	commitErrs := make([]error, len(steps))
+	if failed < 0 {
+		for i, step := range steps {
+			commitErrs[i] = step.commit(ctx)
+		}
+	}
+
+	for _, step := range locking {
+		step.unlock()
+	}
+
+	if failed >= 0 {
+		report.Failed = steps[failed].name()
+		for i, step := range steps {
+			if i != failed {
+				report.Steps[i].Err = ErrBatchAborted
+			}
+			report.Steps[i].State = step.abort(report.Steps[i].Err)
+		}
+	} else {
+		report.Committed = true
+		for i, step := range steps {
+			var err error
+			report.Steps[i].State, err = step.finish(ctx)
+			report.Steps[i].Err = errors.Join(commitErrs[i], err)
+		}
+	}
+
+	var errs []error
+	for _, r := range report.Steps {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Name, r.Err))
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// transitionStep is a Step transitioning an FSM with states of type T
+type transitionStep[T comparable] struct {
+	stepName string
+	fsm      *FSM[T]
+	target   T
+	metadata map[string]string
+
+	prepared *Transition[T]
+	chain    []*Transition[T]
+}
+
+func (s *transitionStep[T]) name() string {
+	return s.stepName
+}
+
+func (s *transitionStep[T]) order() uint64 {
+	return s.fsm.order()
+}
+
+func (s *transitionStep[T]) lock() {
+	s.fsm.mu.Lock()
+}
+
+func (s *transitionStep[T]) unlock() {
+	s.fsm.mu.Unlock()
+}
+
+func (s *transitionStep[T]) prepare(ctx context.Context, transaction string) error {
+	if transaction != "" {
+		metadata := make(map[string]string, len(s.metadata)+1)
+		for k, v := range s.metadata {
+			metadata[k] = v
+		}
+		metadata[TransactionMetadataKey] = transaction
+		s.metadata = metadata
+	}
+	s.metadata = s.fsm.tag(ctx, s.metadata)
+
+	tr, err := s.fsm.prepare(ctx, s.target, s.metadata)
+	if err != nil {
+		s.fsm.recordFailure(ctx, s.target, s.metadata, err)
+		return s.fsm.attributeLocked(err)
+	}
+	s.prepared = tr
+
+	return nil
+}
+
+func (s *transitionStep[T]) commit(ctx context.Context) error {
+	// A same-state no-op has nothing to commit
+	if s.prepared == nil {
+		return nil
+	}
+
+	var err error
+	s.chain, err = s.fsm.commitChain(ctx, s.prepared)
+
+	return err
+}
+
+func (s *transitionStep[T]) finish(ctx context.Context) (string, error) {
+	if s.chain == nil {
+		return DisplayName(s.fsm.CurrentState(), ""), nil
+	}
+
+	state, err := s.fsm.afterCommitChain(ctx, s.chain)
+
+	return DisplayName(state, ""), s.fsm.attribute(err)
+}
+
+func (s *transitionStep[T]) abort(err error) string {
+	state := s.fsm.CurrentState()
+	if err != nil && !errors.Is(err, ErrBatchAborted) {
+		s.fsm.deadLetter(state, s.target, s.metadata, err, 1)
+	}
+
+	return DisplayName(state, "")
+}
@@ -0,0 +1,138 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newShipmentFSM() *FSM[string] {
+	fsm := NewFSM[string]("pending", 10)
+	fsm.AddRule("pending", "shipped")
+	return fsm
+}
+
+func Test_transact(t *testing.T) {
+	order := newPingPongFSM()
+	shipment := newShipmentFSM()
+
+	report, err := Transact(context.Background(), "tx-1",
+		TransitionStep("order", order, CustomStateEnumB, map[string]string{"by": "jane"}),
+		TransitionStep("shipment", shipment, "shipped", nil),
+	)
+
+	if err != nil || !report.Committed || report.Failed != "" {
+		t.Fatalf("Transact returned %+v, %v, expected it to be committed", report, err)
+	}
+	if report.Steps[0].State != "B" || report.Steps[1].State != "shipped" {
+		t.Errorf("Transact reported %+v, expected B and shipped", report.Steps)
+	}
+
+	for _, md := range []map[string]string{order.Transitions()[0].Metadata, shipment.Transitions()[0].Metadata} {
+		if md[TransactionMetadataKey] != "tx-1" {
+			t.Errorf("transition has metadata %v, expected the transaction ID", md)
+		}
+	}
+	if order.Transitions()[0].Metadata["by"] != "jane" {
+		t.Errorf("Transact dropped the step's metadata")
+	}
+}
+
+func Test_transactAborted(t *testing.T) {
+	order := newPingPongFSM()
+	shipment := newShipmentFSM()
+	shipment.SetRecordFailures(true)
+
+	report, err := Transact(context.Background(), "",
+		TransitionStep("order", order, CustomStateEnumB, nil),
+		TransitionStep("shipment", shipment, "delivered", nil),
+	)
+
+	var transitionErr TransitionError[string]
+	if !errors.As(err, &transitionErr) || report.Committed || report.Failed != "shipment" {
+		t.Fatalf("Transact returned %+v, %v, expected the shipment step to fail", report, err)
+	}
+	if !errors.Is(report.Steps[0].Err, ErrBatchAborted) || report.Steps[0].State != "A" {
+		t.Errorf("Transact reported %+v for the order, expected it to be aborted in A", report.Steps[0])
+	}
+
+	if order.CurrentState() != CustomStateEnumA || len(order.Transitions()) != 0 {
+		t.Errorf("aborted transaction moved the order to %v", order.CurrentState())
+	}
+	if history := shipment.Transitions(); len(history) != 1 || !history[0].Failed {
+		t.Errorf("shipment history is %v, expected the failed attempt", history)
+	}
+
+	if _, err := Transact(context.Background(), "", TransitionStep("a", order, CustomStateEnumB, nil), TransitionStep("b", order, CustomStateEnumB, nil)); !errors.Is(err, ErrDuplicateStep) {
+		t.Errorf("Transact with two steps for one FSM returned %v, expected ErrDuplicateStep", err)
+	}
+}
+
+func Test_transactConcurrentWithBatches(t *testing.T) {
+	m := NewManager[string, CustomStateEnum]()
+	a, b := newPingPongFSM(), newPingPongFSM()
+	m.Add("a", a)
+	m.Add("b", b)
+
+	toggle := func(fsm *FSM[CustomStateEnum]) CustomStateEnum {
+		if fsm.CurrentState() == CustomStateEnumA {
+			return CustomStateEnumB
+		}
+		return CustomStateEnumA
+	}
+
+	// Transactions lock b before a in their step order while batches list a first
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				Transact(context.Background(), "", TransitionStep("b", b, toggle(b), nil), TransitionStep("a", a, toggle(a), nil))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				m.TransitionMany([]string{"a", "b"}, toggle(a), nil, AllOrNothing)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_transactAbortedLeavesNoHistory(t *testing.T) {
+	order := newPingPongFSM()
+	order.SetSameStatePolicy(SameStateTouch)
+	shipment := newShipmentFSM()
+	shipment.SetDebounce(time.Hour, true)
+	shipment.Transition("shipped", nil)
+
+	report, err := Transact(context.Background(), "tx-1",
+		TransitionStep("order", order, CustomStateEnumA, nil),
+		TransitionStep("shipment", shipment, "shipped", nil),
+		TransitionStep("other", newShipmentFSM(), "delivered", nil),
+	)
+	if !errors.Is(err, ErrBatchAborted) || report.Committed {
+		t.Fatalf("Transact returned %+v, %v, expected it to be aborted", report, err)
+	}
+
+	if history := order.Transitions(); len(history) != 0 {
+		t.Errorf("aborted transaction left %v in the order's history, expected the touch to be discarded", history)
+	}
+	if history := shipment.Transitions(); len(history) != 1 {
+		t.Errorf("aborted transaction left %v in the shipment's history, expected the duplicate to be discarded", history)
+	}
+	if !order.LastActivity().Equal(order.enteredAt) {
+		t.Errorf("aborted transaction touched the order")
+	}
+
+	if _, err := Transact(context.Background(), "tx-2", TransitionStep("order", order, CustomStateEnumA, nil)); err != nil {
+		t.Fatalf("Transact returned %v, expected the touch to succeed", err)
+	}
+	if history := order.Transitions(); len(history) != 1 || !history[0].Touch || history[0].Metadata[TransactionMetadataKey] != "tx-2" {
+		t.Errorf("committed transaction recorded %v, expected the touch", history)
+	}
+}
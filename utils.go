@@ -1,6 +1,10 @@
 package statetrooper
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 func stringable(t interface{}) bool {
 	if _, ok := t.(string); ok {
@@ -26,3 +30,56 @@ func toString(t interface{}) string {
 
 	return fmt.Sprintf("%v", t)
 }
+
+// contains reports whether v is present in s
+func contains[T comparable](s []T, v T) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// detachedContext carries the values of its parent but never expires or gets cancelled
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
+
+func (c detachedContext) Value(key any) any { return c.parent.Value(key) }
+
+// cloneMap returns a shallow copy of m, or nil if m is nil
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+
+	return c
+}
+
+// cloneMapOfSlices returns a copy of m with each slice copied, or nil if m is nil
+func cloneMapOfSlices[K comparable, V any](m map[K][]V) map[K][]V {
+	if m == nil {
+		return nil
+	}
+
+	c := make(map[K][]V, len(m))
+	for k, v := range m {
+		c[k] = make([]V, len(v))
+		copy(c[k], v)
+	}
+
+	return c
+}
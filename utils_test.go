@@ -1,6 +1,7 @@
 package statetrooper
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -56,3 +57,43 @@ func TestToString(t *testing.T) {
 		}
 	}
 }
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		input    []string
+		value    string
+		expected bool
+	}{
+		{[]string{"Nadia", "Yousif"}, "Yousif", true},
+		{[]string{"Nadia", "Yousif"}, "Jenna", false},
+		{nil, "Jenna", false},
+	}
+
+	for _, test := range tests {
+		actual := contains(test.input, test.value)
+		if actual != test.expected {
+			t.Errorf("contains(%v, %s) = %t, expected %t", test.input, test.value, actual, test.expected)
+		}
+	}
+}
+
+func TestDetachedContext(t *testing.T) {
+	type ctxKey struct{}
+
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	cancel()
+
+	ctx := detachedContext{parent}
+
+	if ctx.Err() != nil || ctx.Done() != nil {
+		t.Errorf("detachedContext is cancelled along with its parent")
+	}
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("detachedContext has a deadline")
+	}
+
+	if ctx.Value(ctxKey{}) != "value" {
+		t.Errorf("detachedContext.Value() = %v, expected value", ctx.Value(ctxKey{}))
+	}
+}
@@ -0,0 +1,142 @@
+package statetrooper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Validate checks the whole configuration of the FSM and returns all problems found joined into one error,
+// each wrapping ErrInvalidConfig. It is meant to be called once at startup so that misconfiguration
+// is caught before the first transition. It checks that
+//   - rules only refer to registered states, if states are registered
+//   - guards, edge cooldowns, edge budgets, rule flags, allowed roles and automatic transitions are set on
+//     edges that have a rule
+//   - entry actions, exit actions, dwell thresholds, minimum dwells and idle thresholds are set on declared states
+//   - terminal states have no outbound rules
+//   - group rules refer to known groups
+func (fsm *FSM[T]) Validate() error {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.validate()
+}
+
+// validate implements Validate. The caller must hold the lock
+func (fsm *FSM[T]) validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+
+	if fsm.states != nil {
+		for from, targets := range fsm.ruleset {
+			for _, state := range append([]T{from}, targets...) {
+				if _, ok := fsm.states[state]; !ok {
+					invalid("rule from %v refers to unregistered state %v", from, state)
+				}
+			}
+		}
+	}
+
+	for e := range fsm.guards {
+		if !fsm.hasRule(e) {
+			invalid("guard on %v -> %v, which has no rule", e.from, e.to)
+		}
+	}
+
+	for e := range fsm.edgeCooldowns {
+		if !fsm.hasRule(e) {
+			invalid("cooldown on %v -> %v, which has no rule", e.from, e.to)
+		}
+	}
+
+	for e := range fsm.edgeMaxTransitions {
+		if !fsm.hasRule(e) {
+			invalid("transition budget on %v -> %v, which has no rule", e.from, e.to)
+		}
+	}
+
+	for e := range fsm.ruleFlags {
+		if !contains(fsm.ruleset[e.from], e.to) {
+			invalid("flag on %v -> %v, which has no rule", e.from, e.to)
+		}
+	}
+
+	for group := range fsm.groupRules {
+		if _, ok := fsm.groups[group]; !ok {
+			invalid("group rule from unknown group %q", group)
+		}
+	}
+
+	for from, autos := range fsm.autoTransitions {
+		for _, a := range autos {
+			if !contains(fsm.ruleset[from], a.toState) {
+				invalid("automatic transition on %v -> %v, which has no rule", from, a.toState)
+			}
+		}
+	}
+
+	for e := range fsm.allowedRoles {
+		if !contains(fsm.ruleset[e.from], e.to) {
+			invalid("allowed roles on %v -> %v, which has no rule", e.from, e.to)
+		}
+	}
+
+	for state := range fsm.entryActions {
+		if !fsm.declared(state) {
+			invalid("entry action on undeclared state %v", state)
+		}
+	}
+
+	for state := range fsm.exitActions {
+		if !fsm.declared(state) {
+			invalid("exit action on undeclared state %v", state)
+		}
+	}
+
+	for state := range fsm.dwellThresholds {
+		if !fsm.declared(state) {
+			invalid("dwell threshold on undeclared state %v", state)
+		}
+	}
+
+	for state := range fsm.minDwells {
+		if !fsm.declared(state) {
+			invalid("minimum dwell on undeclared state %v", state)
+		}
+	}
+
+	for state := range fsm.idleThresholds {
+		if !fsm.declared(state) {
+			invalid("idle threshold on undeclared state %v", state)
+		}
+	}
+
+	for state := range fsm.terminals {
+		if len(fsm.ruleset[state]) > 0 {
+			invalid("terminal state %v has outbound rules to %v", state, fsm.ruleset[state])
+		}
+	}
+
+	// Map iteration order is random, so sort for a stable error message
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	return errors.Join(errs...)
+}
+
+// hasRule reports whether there is a rule for a transition along e, either directly or through
+// composite states that e.from or e.to belong to. Disabled rules count, as they may be enabled later
+func (fsm *FSM[T]) hasRule(e edge[T]) bool {
+	for to, ok := e.to, true; ok; to, ok = fsm.parents[to] {
+		for from, ok := e.from, true; ok; from, ok = fsm.parents[from] {
+			if contains(fsm.ruleset[from], to) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+package statetrooper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_validate(t *testing.T) {
+	fsm := NewFSM[CustomStateEnum](CustomStateEnumA, 10)
+	fsm.AddRule(CustomStateEnumA, CustomStateEnumB)
+	fsm.AddRule(CustomStateEnumB, CustomStateEnumC)
+	fsm.SetTerminalState(CustomStateEnumC, nil)
+
+	if err := fsm.Validate(); err != nil {
+		t.Fatalf("Validate() returned %v for a valid configuration", err)
+	}
+
+	fsm.AddRule(CustomStateEnumC, CustomStateEnumA)
+	fsm.AddGuard(CustomStateEnumA, CustomStateEnumC, func(ctx context.Context, tr Transition[CustomStateEnum]) error {
+		return nil
+	})
+	fsm.SetEdgeCooldown(CustomStateEnumB, CustomStateEnumA, time.Second)
+	fsm.SetDwellThreshold(CustomStateEnumD, time.Minute)
+
+	err := fsm.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Validate() returned %v, expected ErrInvalidConfig", err)
+	}
+
+	for _, problem := range []string{
+		"guard on A -> C",
+		"cooldown on B -> A",
+		"dwell threshold on undeclared state D",
+		"terminal state C has outbound rules",
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Validate() error %q does not report %q", err, problem)
+		}
+	}
+
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 4 {
+		t.Errorf("Validate() reported %d problems, expected 4", n)
+	}
+}
@@ -0,0 +1,78 @@
+package statetrooper
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Migration maps a state of one ruleset version to the corresponding state of the next version
+type Migration[T comparable] func(state T) T
+
+// SetVersion sets the version of the FSM's ruleset, which is persisted with the FSM
+func (fsm *FSM[T]) SetVersion(version int) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	fsm.version = version
+}
+
+// Version returns the version of the FSM's ruleset
+func (fsm *FSM[T]) Version() int {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	return fsm.version
+}
+
+// AddMigration registers the migration from ruleset version fromVersion to fromVersion+1
+// When an FSM persisted under an older version is unmarshaled, its current state is passed through
+// each migration in turn and the migration is recorded in the history
+func (fsm *FSM[T]) AddMigration(fromVersion int, migration Migration[T]) {
+	fsm.mu.Lock()
+	defer fsm.mu.Unlock()
+
+	if fsm.migrations == nil {
+		fsm.migrations = make(map[int]Migration[T])
+	}
+
+	fsm.migrations[fromVersion] = migration
+}
+
+// checkMigrations returns ErrUnsupportedVersion if an FSM persisted under version cannot be migrated
+func (fsm *FSM[T]) checkMigrations(version int) error {
+	if version > fsm.version {
+		return fmt.Errorf("%w: %d is newer than %d", ErrUnsupportedVersion, version, fsm.version)
+	}
+
+	for v := version; v < fsm.version; v++ {
+		if _, ok := fsm.migrations[v]; !ok {
+			return fmt.Errorf("%w: no migration from %d to %d", ErrUnsupportedVersion, v, v+1)
+		}
+	}
+
+	return nil
+}
+
+// migrate moves the current state of an FSM persisted under version to the current ruleset version
+func (fsm *FSM[T]) migrate(version int) {
+	if version == fsm.version {
+		return
+	}
+
+	fromState := fsm.currentState
+	for v := version; v < fsm.version; v++ {
+		fsm.currentState = fsm.migrations[v](fsm.currentState)
+	}
+
+	tn := fsm.timeNow()
+	fsm.enteredAt = tn
+	fsm.recordTransition(&Transition[T]{
+		FromState: fromState,
+		ToState:   fsm.currentState,
+		Timestamp: &tn,
+		Metadata: map[string]string{
+			"migrated_from_version": strconv.Itoa(version),
+			"migrated_to_version":   strconv.Itoa(fsm.version),
+		},
+	})
+}
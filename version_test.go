@@ -0,0 +1,70 @@
+package statetrooper
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func Test_versionMigration(t *testing.T) {
+	v1 := NewFSM[string]("created", 10)
+	v1.SetVersion(1)
+	v1.AddRule("created", "packed")
+	v1.Transition("packed", nil)
+
+	data, err := json.Marshal(v1)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	newV3 := func() *FSM[string] {
+		fsm := NewFSM[string]("created", 10)
+		fsm.SetVersion(3)
+		fsm.AddMigration(1, func(state string) string {
+			if state == "packed" {
+				return "staged"
+			}
+			return state
+		})
+		return fsm
+	}
+
+	// Missing migration from 2 to 3
+	fsm := newV3()
+	if err := json.Unmarshal(data, fsm); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("json.Unmarshal() returned %v, expected ErrUnsupportedVersion", err)
+	}
+
+	fsm = newV3()
+	fsm.AddMigration(2, func(state string) string {
+		if state == "staged" {
+			return "ready"
+		}
+		return state
+	})
+
+	if err := json.Unmarshal(data, fsm); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	if fsm.CurrentState() != "ready" {
+		t.Errorf("Current state is %v after migration, expected ready", fsm.CurrentState())
+	}
+
+	history := fsm.Transitions()
+	if len(history) != 2 {
+		t.Fatalf("History has %d transitions, expected 2", len(history))
+	}
+
+	migration := history[1]
+	if migration.FromState != "packed" || migration.ToState != "ready" ||
+		migration.Metadata["migrated_from_version"] != "1" || migration.Metadata["migrated_to_version"] != "3" {
+		t.Errorf("Migration recorded as %+v", migration)
+	}
+
+	// Snapshots from a newer version are rejected
+	old := NewFSM[string]("created", 10)
+	if err := json.Unmarshal(data, old); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("json.Unmarshal() returned %v for a newer snapshot, expected ErrUnsupportedVersion", err)
+	}
+}
@@ -0,0 +1,38 @@
+package statetrooper
+
+import "context"
+
+// TransitionSignalName is the default name of the workflow signals created by ToSignal
+const TransitionSignalName = "statetrooper.transition"
+
+// WorkflowSignal is a committed transition converted into a signal for a workflow engine such as Temporal
+type WorkflowSignal[T comparable] struct {
+	Name string
+	Arg  Transition[T]
+}
+
+// SignalSender delivers a signal to a running workflow. It has the signature of the SignalWorkflow
+// method of a Temporal client, so that method can be passed as is. An empty runID targets the latest run
+type SignalSender func(ctx context.Context, workflowID string, runID string, signalName string, arg any) error
+
+// ToSignal converts tr into a workflow signal named name, or TransitionSignalName if name is empty
+// The signal carries a copy of the transition's metadata, so the workflow cannot change the FSM's history
+func ToSignal[T comparable](tr Transition[T], name string) WorkflowSignal[T] {
+	if name == "" {
+		name = TransitionSignalName
+	}
+
+	tr.Metadata = cloneMap(tr.Metadata)
+
+	return WorkflowSignal[T]{Name: name, Arg: tr}
+}
+
+// SignalWorkflow registers a post-commit hook sending each committed transition to the workflow with
+// the ID returned by workflowID as a signal named name, or TransitionSignalName if name is empty
+// Failed signals are passed to the hook error handler. It returns a function that deregisters the hook
+func (fsm *FSM[T]) SignalWorkflow(send SignalSender, workflowID func(tr Transition[T]) string, name string) (remove func()) {
+	return fsm.AddHook(PostCommit, 0, func(ctx context.Context, tr Transition[T]) error {
+		signal := ToSignal(tr, name)
+		return send(ctx, workflowID(tr), "", signal.Name, signal.Arg)
+	})
+}
@@ -0,0 +1,38 @@
+package statetrooper
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_signalWorkflow(t *testing.T) {
+	fsm := newPingPongFSM()
+
+	type sent struct {
+		workflowID string
+		name       string
+		arg        Transition[CustomStateEnum]
+	}
+	var signals []sent
+
+	remove := fsm.SignalWorkflow(func(ctx context.Context, workflowID string, runID string, signalName string, arg any) error {
+		signals = append(signals, sent{workflowID, signalName, arg.(Transition[CustomStateEnum])})
+		return nil
+	}, func(tr Transition[CustomStateEnum]) string { return "fulfillment-order-1" }, "")
+
+	fsm.Transition(CustomStateEnumB, map[string]string{"by": "jane"})
+	remove()
+	fsm.Transition(CustomStateEnumA, nil)
+
+	if len(signals) != 1 {
+		t.Fatalf("SignalWorkflow sent %d signals, expected 1", len(signals))
+	}
+	if s := signals[0]; s.workflowID != "fulfillment-order-1" || s.name != TransitionSignalName || s.arg.ToState != CustomStateEnumB {
+		t.Errorf("SignalWorkflow sent %+v, expected the transition to B", s)
+	}
+
+	signals[0].arg.Metadata["by"] = "mallory"
+	if fsm.Transitions()[0].Metadata["by"] != "jane" {
+		t.Errorf("changing the signal's metadata changed the history")
+	}
+}